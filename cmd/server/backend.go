@@ -0,0 +1,82 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"config-manager/src/storage"
+)
+
+// Storage backends selectable with DB_DRIVER
+const (
+	driverSQLite   = "sqlite"
+	driverPostgres = "postgres"
+)
+
+// backend is a storage backend compiled into the binary. Each registers itself from a file
+// built only with its build tag, so main wires nothing that is not compiled in.
+type backend struct {
+	// dsn locates the primary database from the environment
+	dsn func() string
+	// open opens the database at dsn with the backend's settings
+	open func(dsn string) (*sql.DB, error)
+	// migrate applies the backend's embedded schema migrations
+	migrate func(db *sql.DB) error
+	// newStore creates the backend's store, writing to primary and reading from replica
+	newStore func(primary, replica *sql.DB, readAfterWriteWindow time.Duration) configurableStore
+}
+
+// backends holds the compiled-in storage backends by DB_DRIVER name
+var backends = map[string]backend{}
+
+// configurableStore is a storage backend along with the options set on it at startup
+type configurableStore interface {
+	storage.Store
+	SetCompressStorage(enabled bool)
+	SetEnforceContiguity(enabled bool)
+}
+
+// dbDriver reads DB_DRIVER, the storage backend. It defaults to sqlite, or to the only backend
+// compiled in, and must name a backend this binary was built with.
+func dbDriver() string {
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = driverSQLite
+		if _, ok := backends[driver]; !ok && len(backends) == 1 {
+			driver = driverPostgres
+		}
+	}
+	if _, ok := backends[driver]; !ok {
+		compiled := make([]string, 0, len(backends))
+		for name := range backends {
+			compiled = append(compiled, name)
+		}
+		sort.Strings(compiled)
+		log.Fatalf("Invalid DB_DRIVER %q, this binary is built with %s", driver, strings.Join(compiled, " and "))
+	}
+	return driver
+}
+
+// primaryDSN locates the primary database of driver
+func primaryDSN(driver string) string {
+	return backends[driver].dsn()
+}
+
+// openDatabase opens the database at dsn with the driver's settings
+func openDatabase(driver, dsn string) (*sql.DB, error) {
+	return backends[driver].open(dsn)
+}
+
+// migrateDatabase applies the driver's embedded schema migrations
+func migrateDatabase(driver string, db *sql.DB) error {
+	return backends[driver].migrate(db)
+}
+
+// newStore creates the driver's store, writing to primary and reading from replica
+func newStore(driver string, primary, replica *sql.DB, readAfterWriteWindow time.Duration) configurableStore {
+	return backends[driver].newStore(primary, replica, readAfterWriteWindow)
+}
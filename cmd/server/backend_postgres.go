@@ -0,0 +1,32 @@
+//go:build postgres || !sqlite
+
+package main
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"time"
+
+	"config-manager/src/storage"
+)
+
+func init() {
+	backends[driverPostgres] = backend{
+		dsn:     postgresURL,
+		open:    storage.OpenPostgres,
+		migrate: storage.MigratePostgres,
+		newStore: func(primary, replica *sql.DB, readAfterWriteWindow time.Duration) configurableStore {
+			return storage.NewPostgresStoreWithReplica(primary, replica, readAfterWriteWindow)
+		},
+	}
+}
+
+// postgresURL reads DATABASE_URL, the PostgreSQL connection string, which is required
+func postgresURL() string {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		log.Fatal("DATABASE_URL is required when DB_DRIVER is postgres")
+	}
+	return dsn
+}
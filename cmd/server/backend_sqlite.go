@@ -0,0 +1,39 @@
+//go:build sqlite || !postgres
+
+package main
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"time"
+
+	"config-manager/src/storage"
+)
+
+func init() {
+	backends[driverSQLite] = backend{
+		dsn: sqlitePath,
+		open: func(dsn string) (*sql.DB, error) {
+			return sql.Open("sqlite3", storage.SQLiteDSN(dsn))
+		},
+		migrate: storage.Migrate,
+		newStore: func(primary, replica *sql.DB, readAfterWriteWindow time.Duration) configurableStore {
+			return storage.NewSQLiteStoreWithReplica(primary, replica, readAfterWriteWindow)
+		},
+	}
+}
+
+// sqlitePath reads DB_PATH, the SQLite database file, which defaults to ./data/config.db
+func sqlitePath() string {
+	// Ensure data directory exists
+	if err := os.MkdirAll("./data", 0755); err != nil {
+		log.Fatal("Failed to create data directory:", err)
+	}
+
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = "./data/config.db"
+	}
+	return dbPath
+}
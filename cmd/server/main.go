@@ -1,38 +1,59 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"config-manager/src/handlers"
+	appmiddleware "config-manager/src/middleware"
+	"config-manager/src/server"
 	"config-manager/src/services"
-	"config-manager/src/storage"
 
-	_ "config-manager/docs"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite3"
-	"github.com/golang-migrate/migrate/v4/source/file"
+	"config-manager/docs"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/swaggo/echo-swagger"
 )
 
-func main() {
-	// Get database path from environment or use default
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./data/config.db"
-	}
+// Request timeouts per route class
+const (
+	getRouteTimeout   = 5 * time.Second
+	writeRouteTimeout = 10 * time.Second
+	listRouteTimeout  = 30 * time.Second
+)
 
-	// Ensure data directory exists
-	if err := os.MkdirAll("./data", 0755); err != nil {
-		log.Fatal("Failed to create data directory:", err)
-	}
+// defaultShutdownTimeout leaves in-flight requests time to finish while staying within the
+// usual 30s termination grace period of container orchestrators
+const defaultShutdownTimeout = 25 * time.Second
+
+// auditPurgeInterval is how often access log entries past AUDIT_RETENTION_DAYS are purged
+const auditPurgeInterval = time.Hour
+
+// versionPruneInterval is how often versions beyond VERSION_RETENTION_COUNT are pruned
+const versionPruneInterval = time.Hour
+
+// main serves the configuration management API; the annotations below are the general API info
+// of the generated swagger docs (make api-docs).
+//
+//	@title			Configuration Management API
+//	@version		1.0.0
+//	@description	API for managing versioned configurations with validation and rollback capabilities.
+func main() {
+	// Log JSON lines for the log pipeline; this also covers everything written with the log package
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
 
-	// Open database connection
-	db, err := sql.Open("sqlite3", dbPath)
+	// Open the primary database of the configured backend
+	driver := dbDriver()
+	db, err := openDatabase(driver, primaryDSN(driver))
 	if err != nil {
 		log.Fatal("Failed to open database:", err)
 	}
@@ -42,34 +63,111 @@ func main() {
 		}
 	}()
 
-	// Run database migrations
-	if err := runMigrations(dbPath); err != nil {
-		log.Fatal("Failed to run migrations:", err)
-	}
+	// The listener binds before migrations run so /ready can report them; until startup is
+	// done, the readiness gate keeps the API closed
+	readiness := appmiddleware.NewReadiness()
 
 	// Initialize services
-	validationService, err := services.NewValidationService()
+	validationService, err := newValidationService()
 	if err != nil {
-		log.Fatal("Failed to create validation service:", err)
+		// Optionally keep serving reads through a bad schema deploy instead of going down
+		if os.Getenv("READ_ONLY_ON_SCHEMA_ERROR") != "true" {
+			log.Fatal("Failed to create validation service:", err)
+		}
+		log.Printf("ERROR: configuration schema failed to load, serving reads only and rejecting writes: %v", err)
+		validationService = services.NewUnavailableValidationService(os.Getenv("SCHEMA_REGISTRY_URL"), err)
 	}
 
-	sqliteStore := storage.NewSQLiteStore(db)
-	configService := services.NewConfigService(sqliteStore, validationService)
+	// Periodically refresh the registry schema when configured
+	stopSchemaRefresh := validationService.StartSchemaRefresh(schemaRefreshInterval())
+	defer stopSchemaRefresh()
+
+	store := newStore(driver, db, db, 0)
+
+	// Route reads to a replica when configured
+	if readDSN := os.Getenv("READ_DATABASE_URL"); readDSN != "" {
+		readDB, err := openDatabase(driver, readDSN)
+		if err != nil {
+			log.Fatal("Failed to open read replica database:", err)
+		}
+		defer func() {
+			if err := readDB.Close(); err != nil {
+				log.Printf("Failed to close read replica database: %v", err)
+			}
+		}()
+
+		store = newStore(driver, db, readDB, readAfterWriteWindow())
+		log.Println("Routing reads to the read replica")
+	}
+	store.SetCompressStorage(os.Getenv("COMPRESS_STORAGE") == "true")
+	store.SetEnforceContiguity(os.Getenv("ENFORCE_VERSION_CONTIGUITY") == "true")
+	configService := services.NewConfigService(store, validationService)
+	configService.SetAccessLogEnabled(os.Getenv("ACCESS_LOG_ENABLED") == "true")
+	configService.SetAuditRetention(auditRetentionDays())
+	configService.SetVersionRetention(versionRetention())
 	configHandler := handlers.NewConfigHandler(configService)
+	configHandler.SetMaxNameLength(maxNameLength())
+	configHandler.SetAutoCreateOnUpdate(os.Getenv("AUTO_CREATE_ON_UPDATE") == "true")
 
-	// Create Echo instance
+	// Back up settings are checked up front so a misconfiguration fails before serving
+	var backupJob *services.BackupJob
+	backupEvery := backupInterval()
+	if backupEvery > 0 {
+		endpoint, bucket := os.Getenv("BACKUP_S3_ENDPOINT"), os.Getenv("BACKUP_S3_BUCKET")
+		if endpoint == "" || bucket == "" {
+			log.Fatal("BACKUP_INTERVAL requires BACKUP_S3_ENDPOINT and BACKUP_S3_BUCKET")
+		}
+		uploader := services.NewS3Uploader(endpoint, bucket, os.Getenv("BACKUP_S3_REGION"),
+			os.Getenv("BACKUP_S3_ACCESS_KEY_ID"), os.Getenv("BACKUP_S3_SECRET_ACCESS_KEY"))
+		backupJob = services.NewBackupJob(configService, uploader)
+		log.Printf("Backing up configurations to %s/%s every %s", endpoint, bucket, backupEvery)
+	}
+
+	// Create Echo instance; startup is logged as JSON below instead of Echo's banner
 	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
 
 	// Middleware
-	e.Use(middleware.Logger())
+	e.Use(appmiddleware.RequestID())
+	e.Use(appmiddleware.RequestLogger(logger))
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 
+	// Debug logging of mutating request/response bodies
+	if os.Getenv("LOG_BODIES") == "true" {
+		e.Use(appmiddleware.BodyLogger(bodyLogConfig(configService)))
+	}
+
+	// All routes live under BASE_PATH when serving behind a path-routing proxy
+	prefix := basePath()
+	root := e.Group(prefix)
+	docs.SwaggerInfo.BasePath = prefix
+
 	// Swagger UI endpoint
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	root.GET("/swagger/*", echoSwagger.WrapHandler)
+
+	// Admin endpoints
+	admin := root.Group("/admin", readiness.Gate(), appmiddleware.CacheControl(appmiddleware.CacheNoStore))
+	admin.GET("/ui", handlers.AdminUI)
+	admin.POST("/repair", configHandler.RepairCurrentVersions)
+	admin.GET("/contiguity", configHandler.CheckVersionContiguity)
+	admin.POST("/verify-checksums", configHandler.VerifyChecksums)
+	admin.GET("/backups", configHandler.ListBackups)
+	admin.POST("/audit/purge", configHandler.PurgeAuditLog)
+	admin.GET("/schema-violations", configHandler.FindSchemaViolations)
+	admin.POST("/schema/impact", configHandler.SchemaImpact)
+	admin.GET("/configs/:name/versions/:version/raw", configHandler.GetRawVersionData)
+	admin.PUT("/defaults", configHandler.SetDefaultConfig)
+	admin.PUT("/defaults/:name", configHandler.SetDefaultConfig)
+	admin.DELETE("/defaults", configHandler.DeleteDefaultConfig)
+	admin.DELETE("/defaults/:name", configHandler.DeleteDefaultConfig)
+	admin.GET("/budgets", configHandler.ListConfigBudgets)
+	admin.PUT("/budgets/:name", configHandler.SetConfigBudget)
+	admin.DELETE("/budgets/:name", configHandler.DeleteConfigBudget)
 
 	// Health check endpoint
-	e.GET("/health", func(c echo.Context) error {
+	root.GET("/health", func(c echo.Context) error {
 		// Test database connection
 		if err := db.Ping(); err != nil {
 			return c.JSON(503, map[string]string{
@@ -84,16 +182,74 @@ func main() {
 		})
 	})
 
+	// Readiness probe: reports migrating until the schema is complete
+	root.GET("/ready", readiness.Handler)
+
 	// API routes
-	api := e.Group("/api/v1")
+	// Responses are revalidated. Even specific versions are not cached for good: squashing renumbers
+	// them and renaming moves them, so a version URL can come to serve other data.
+	// Requests addressing a configuration count against its request budget, if it has one.
+	api := root.Group("/api/v1", readiness.Gate(), appmiddleware.CacheControl(appmiddleware.CacheRevalidate),
+		appmiddleware.ConfigRequestBudget(configService.AllowRequest))
+
+	// Per-route timeouts: tight on single-config hot paths, generous for full-history and export reads
+	getTimeout := appmiddleware.RequestTimeout(getRouteTimeout)
+	writeTimeout := appmiddleware.RequestTimeout(writeRouteTimeout)
+	listTimeout := appmiddleware.RequestTimeout(listRouteTimeout)
+
+	// Declared query parameters per route; unknown ones are rejected under STRICT_QUERY_PARAMS
+	strictQuery := os.Getenv("STRICT_QUERY_PARAMS") == "true"
+	query := func(route string) echo.MiddlewareFunc {
+		params, ok := handlers.QueryParams[route]
+		if !ok {
+			log.Fatalf("No query parameters declared for %s", route)
+		}
+		return appmiddleware.AllowedQueryParams(strictQuery, params...)
+	}
 
 	// Configuration endpoints
-	api.POST("/configs", configHandler.CreateConfig)
-	api.PUT("/configs/:name", configHandler.UpdateConfig)
-	api.POST("/configs/:name/rollback", configHandler.RollbackConfig)
-	api.GET("/configs/:name", configHandler.GetLatestConfig)
-	api.GET("/configs/:name/versions/:version", configHandler.GetConfigVersion)
-	api.GET("/configs/:name/versions", configHandler.ListVersions)
+	api.GET("/configs", configHandler.ListConfigs, listTimeout, query("GET /configs"))
+	api.GET("/configs/all", configHandler.ListAllLatestConfigs, listTimeout, query("GET /configs/all"))
+	api.GET("/diff", configHandler.CompareConfigs, getTimeout, query("GET /diff"))
+	api.POST("/configs", configHandler.CreateConfig, writeTimeout, query("POST /configs"))
+	api.POST("/configs\\:exists", configHandler.ConfigsExist, getTimeout, query("POST /configs\\:exists"))
+	api.POST("/configs/sync", configHandler.SyncConfigs, listTimeout, query("POST /configs/sync"))
+	api.POST("/configs/batch", configHandler.CreateConfigsBatch, listTimeout, query("POST /configs/batch"))
+	api.DELETE("/configs", configHandler.DeleteConfigs, writeTimeout, query("DELETE /configs"))
+	api.PUT("/configs/:name", configHandler.UpdateConfig, writeTimeout, query("PUT /configs/:name"))
+	api.PATCH("/configs/:name", configHandler.PatchConfig, writeTimeout, query("PATCH /configs/:name"))
+	api.DELETE("/configs/:name", configHandler.DeleteConfig, writeTimeout, query("DELETE /configs/:name"))
+	api.POST("/configs/:name/restore", configHandler.RestoreConfig, writeTimeout, query("POST /configs/:name/restore"))
+	api.POST("/configs/:name/rollback", configHandler.RollbackConfig, writeTimeout, query("POST /configs/:name/rollback"))
+	api.POST("/configs/:name/undo", configHandler.UndoConfig, writeTimeout, query("POST /configs/:name/undo"))
+	api.POST("/configs/:name/redo", configHandler.RedoConfig, writeTimeout, query("POST /configs/:name/redo"))
+	api.POST("/configs/:name/squash", configHandler.SquashConfig, writeTimeout, query("POST /configs/:name/squash"))
+	api.POST("/configs/:name/prune", configHandler.PruneVersions, writeTimeout, query("POST /configs/:name/prune"))
+	api.POST("/configs/:name/rename", configHandler.RenameConfig, writeTimeout, query("POST /configs/:name/rename"))
+	api.GET("/configs/:name", configHandler.GetLatestConfig, getTimeout, query("GET /configs/:name"))
+	api.GET("/configs/:name/versions/:version", configHandler.GetConfigVersion, getTimeout, query("GET /configs/:name/versions/:version"))
+	api.GET("/configs/:name/meta", configHandler.GetConfigMeta, getTimeout, query("GET /configs/:name/meta"))
+	api.GET("/configs/:name/storage", configHandler.GetConfigStorage, getTimeout, query("GET /configs/:name/storage"))
+	api.GET("/configs/:name/diff", configHandler.DiffVersions, getTimeout, query("GET /configs/:name/diff"))
+	api.GET("/configs/:name/evaluate", configHandler.EvaluateConfig, getTimeout, query("GET /configs/:name/evaluate"))
+	api.POST("/configs/:name/validate", configHandler.ValidateConfig, getTimeout, query("POST /configs/:name/validate"))
+	api.GET("/configs/:name/versions/:version/schema", configHandler.GetVersionSchema, getTimeout, query("GET /configs/:name/versions/:version/schema"))
+	api.POST("/configs/:name/versions/:version/protect", configHandler.ProtectVersion, writeTimeout, query("POST /configs/:name/versions/:version/protect"))
+	api.POST("/configs/:name/versions/:version/publish", configHandler.PublishVersion, writeTimeout, query("POST /configs/:name/versions/:version/publish"))
+	api.GET("/configs/:name/versions", configHandler.ListVersions, listTimeout, query("GET /configs/:name/versions"))
+	api.GET("/configs/:name/duplicates", configHandler.ListDuplicateVersions, listTimeout, query("GET /configs/:name/duplicates"))
+	api.PUT("/configs/:name/sensitive", configHandler.SetSensitive, writeTimeout, query("PUT /configs/:name/sensitive"))
+	api.GET("/configs/:name/tags", configHandler.GetConfigTags, getTimeout, query("GET /configs/:name/tags"))
+	api.PUT("/configs/:name/tags", configHandler.SetConfigTags, writeTimeout, query("PUT /configs/:name/tags"))
+	api.DELETE("/configs/:name/tags/:key", configHandler.DeleteConfigTag, writeTimeout, query("DELETE /configs/:name/tags/:key"))
+
+	// Export endpoints
+	api.GET("/export", configHandler.ExportConfigs, listTimeout, query("GET /export"))
+	api.POST("/import", configHandler.ImportConfigs, listTimeout, query("POST /import"))
+	api.GET("/export/env", configHandler.ExportEnvironment, listTimeout, query("GET /export/env"))
+
+	// JSON-RPC 2.0 endpoint for legacy clients
+	root.POST("/rpc", configHandler.RPC, readiness.Gate(), appmiddleware.CacheControl(appmiddleware.CacheNoStore), listTimeout, query("POST /rpc"))
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
@@ -101,57 +257,258 @@ func main() {
 		port = "8080"
 	}
 
-	// Start server
+	// Serve until SIGINT/SIGTERM, then drain in-flight requests; the deferred cleanup stops the
+	// background jobs and closes the database once the server has stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background jobs start once the schema is in place; the deferred stops run before the
+	// database is closed
+	var stopJobs []func()
+	defer func() {
+		for i := len(stopJobs) - 1; i >= 0; i-- {
+			stopJobs[i]()
+		}
+	}()
+
+	// Startup work touching the database runs once the listener is bound, /ready reporting
+	// migrating meanwhile
+	startup := func() error {
+		if err := migrateDatabase(driver, db); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+		log.Println("Database migrations applied successfully")
+
+		// Optionally self-heal current_version drift before serving
+		if os.Getenv("REPAIR_ON_STARTUP") == "true" {
+			report, err := configService.RepairCurrentVersions()
+			if err != nil {
+				return fmt.Errorf("failed to repair configurations: %w", err)
+			}
+			for _, issue := range report.Issues {
+				log.Printf("Integrity check: %s current_version %d -> %d (repaired: %t)",
+					issue.Name, issue.PreviousVersion, issue.CurrentVersion, issue.Repaired)
+			}
+		}
+
+		// Optionally report version gaps before serving
+		if os.Getenv("CHECK_CONTIGUITY_ON_STARTUP") == "true" {
+			report, err := configService.CheckVersionContiguity()
+			if err != nil {
+				return fmt.Errorf("failed to check version contiguity: %w", err)
+			}
+			for _, gap := range report.Gaps {
+				log.Printf("Contiguity check: %s current_version %d missing %v beyond %v",
+					gap.Name, gap.CurrentVersion, gap.Missing, gap.Beyond)
+			}
+		}
+
+		// Keep the access log within its retention window
+		stopJobs = append(stopJobs, configService.StartAuditPurge(auditPurgeInterval))
+
+		// Keep each configuration's history within its version retention
+		stopJobs = append(stopJobs, configService.StartVersionPruning(versionPruneInterval))
+
+		// Optionally verify stored data against its checksums in the background
+		if interval := checksumVerifyInterval(); interval > 0 {
+			stopJobs = append(stopJobs, configService.StartChecksumVerification(interval))
+			log.Printf("Verifying data checksums every %s", interval)
+		}
+
+		// Optionally back up the configuration archive to S3-compatible object storage
+		if backupJob != nil {
+			stopJobs = append(stopJobs, backupJob.Start(backupEvery))
+		}
+
+		readiness.MarkReady()
+		return nil
+	}
+
 	log.Printf("Starting server on port %s", port)
-	if err := e.Start(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	if err := server.Run(ctx, e, ":"+port, shutdownTimeout(), startup); err != nil {
+		log.Fatal("Server failed:", err)
 	}
 }
 
-// runMigrations applies database migrations using golang-migrate
-func runMigrations(dbPath string) error {
-	// Create database file if it doesn't exist
-	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
-		file, err := os.Create(dbPath)
-		if err != nil {
-			return err
-		}
-		if err := file.Close(); err != nil {
-			return err
-		}
+// newValidationService uses the external schema registry when SCHEMA_REGISTRY_URL is set,
+// falling back to the hardcoded schema otherwise
+func newValidationService() (*services.ValidationService, error) {
+	registryURL := os.Getenv("SCHEMA_REGISTRY_URL")
+	if registryURL == "" {
+		return services.NewValidationService()
 	}
 
-	db, err := sql.Open("sqlite3", dbPath)
+	log.Printf("Loading configuration schema from registry %s", registryURL)
+	return services.NewRegistryValidationService(registryURL)
+}
+
+// basePath reads BASE_PATH, normalized to a leading slash and no trailing slash;
+// empty serves routes from the root
+func basePath() string {
+	prefix := strings.Trim(os.Getenv("BASE_PATH"), "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// schemaRefreshInterval reads SCHEMA_REFRESH_INTERVAL (e.g. "5m"); refresh is disabled when unset
+func schemaRefreshInterval() time.Duration {
+	value := os.Getenv("SCHEMA_REFRESH_INTERVAL")
+	if value == "" {
+		return 0
+	}
+
+	interval, err := time.ParseDuration(value)
 	if err != nil {
-		return err
+		log.Printf("Invalid SCHEMA_REFRESH_INTERVAL %q, schema refresh disabled: %v", value, err)
+		return 0
+	}
+
+	return interval
+}
+
+// backupInterval reads BACKUP_INTERVAL (e.g. "6h"); scheduled backups are disabled when unset
+func backupInterval() time.Duration {
+	value := os.Getenv("BACKUP_INTERVAL")
+	if value == "" {
+		return 0
 	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			log.Printf("Failed to close migration database: %v", err)
-		}
-	}()
 
-	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	interval, err := time.ParseDuration(value)
 	if err != nil {
-		return err
+		log.Printf("Invalid BACKUP_INTERVAL %q, scheduled backups disabled: %v", value, err)
+		return 0
 	}
 
-	// Use file source for migrations
-	fileSource, err := (&file.File{}).Open("file://migrations")
+	return interval
+}
+
+// checksumVerifyInterval reads CHECKSUM_VERIFY_INTERVAL (e.g. "24h"); background checksum
+// verification is disabled when unset
+func checksumVerifyInterval() time.Duration {
+	value := os.Getenv("CHECKSUM_VERIFY_INTERVAL")
+	if value == "" {
+		return 0
+	}
+
+	interval, err := time.ParseDuration(value)
 	if err != nil {
-		return err
+		log.Printf("Invalid CHECKSUM_VERIFY_INTERVAL %q, checksum verification disabled: %v", value, err)
+		return 0
+	}
+
+	return interval
+}
+
+// shutdownTimeout reads SHUTDOWN_TIMEOUT (e.g. "25s"), how long shutdown waits for in-flight
+// requests to complete; defaults to defaultShutdownTimeout
+func shutdownTimeout() time.Duration {
+	value := os.Getenv("SHUTDOWN_TIMEOUT")
+	if value == "" {
+		return defaultShutdownTimeout
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Printf("Invalid SHUTDOWN_TIMEOUT %q, using %s", value, defaultShutdownTimeout)
+		return defaultShutdownTimeout
+	}
+
+	return timeout
+}
+
+// readAfterWriteWindow reads READ_AFTER_WRITE_WINDOW (e.g. "2s"), the window after a write during
+// which reads of the same configuration go to the primary; disabled when unset
+func readAfterWriteWindow() time.Duration {
+	value := os.Getenv("READ_AFTER_WRITE_WINDOW")
+	if value == "" {
+		return 0
 	}
 
-	m, err := migrate.NewWithInstance("file", fileSource, "sqlite3", driver)
+	window, err := time.ParseDuration(value)
 	if err != nil {
-		return err
+		log.Printf("Invalid READ_AFTER_WRITE_WINDOW %q, read-after-write routing disabled: %v", value, err)
+		return 0
+	}
+
+	return window
+}
+
+// auditRetentionDays reads AUDIT_RETENTION_DAYS; access log entries are kept forever when unset
+func auditRetentionDays() int {
+	value := os.Getenv("AUDIT_RETENTION_DAYS")
+	if value == "" {
+		return 0
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		log.Printf("Invalid AUDIT_RETENTION_DAYS %q, access log entries are kept forever", value)
+		return 0
+	}
+	return parsed
+}
+
+// versionRetention reads VERSION_RETENTION_COUNT, how many published versions scheduled pruning
+// keeps per configuration; every version is kept when unset. Pruned histories no longer start
+// at version 1, which ENFORCE_VERSION_CONTIGUITY refuses as a gap, so the two cannot be combined.
+func versionRetention() int {
+	value := os.Getenv("VERSION_RETENTION_COUNT")
+	if value == "" {
+		return 0
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		log.Printf("Invalid VERSION_RETENTION_COUNT %q, every version is kept", value)
+		return 0
 	}
+	if os.Getenv("ENFORCE_VERSION_CONTIGUITY") == "true" {
+		log.Printf("VERSION_RETENTION_COUNT is ignored with ENFORCE_VERSION_CONTIGUITY, which refuses writes to pruned histories")
+		return 0
+	}
+	return parsed
+}
 
-	// Apply migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return err
+// maxNameLength reads MAX_NAME_LENGTH, the longest accepted configuration name (default 100)
+func maxNameLength() int {
+	value := os.Getenv("MAX_NAME_LENGTH")
+	if value == "" {
+		return handlers.DefaultMaxNameLength
 	}
 
-	log.Println("Database migrations applied successfully")
-	return nil
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		log.Printf("Invalid MAX_NAME_LENGTH %q, using %d", value, handlers.DefaultMaxNameLength)
+		return handlers.DefaultMaxNameLength
+	}
+	return parsed
+}
+
+// bodyLogConfig reads LOG_BODIES_MAX_BYTES (default 4096) and LOG_BODIES_NAMES, a comma-separated
+// list of configuration name glob patterns that limits which configurations are logged
+func bodyLogConfig(configService *services.ConfigService) appmiddleware.BodyLogConfig {
+	maxBytes := 4096
+	if value := os.Getenv("LOG_BODIES_MAX_BYTES"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Printf("Invalid LOG_BODIES_MAX_BYTES %q, using %d: %v", value, maxBytes, err)
+		} else {
+			maxBytes = parsed
+		}
+	}
+
+	var patterns []string
+	for _, pattern := range strings.Split(os.Getenv("LOG_BODIES_NAMES"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return appmiddleware.BodyLogConfig{
+		MaxBytes:     maxBytes,
+		NamePatterns: patterns,
+		IsSensitive:  configService.IsSensitive,
+	}
 }
@@ -0,0 +1,224 @@
+// Command smoke runs the create, get, update, list, rollback, and delete flow against a live
+// instance and exits non-zero on the first failed step. It is meant as a post-deploy check.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// smokeClient calls the API and decodes the standard response envelope
+type smokeClient struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// stepResult is one entry of the JSON report printed when the run ends
+type stepResult struct {
+	Step       string `json:"step"`
+	Passed     bool   `json:"passed"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// report is the JSON document written to stdout
+type report struct {
+	BaseURL string       `json:"base_url"`
+	Config  string       `json:"config"`
+	Passed  bool         `json:"passed"`
+	Steps   []stepResult `json:"steps"`
+}
+
+func main() {
+	baseURL := flag.String("base-url", "http://localhost:8080/api/v1", "API base URL, including the /api/v1 prefix")
+	apiKey := flag.String("api-key", "", "API key sent in the X-API-Key header")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout for each request")
+	flag.Parse()
+
+	client := &smokeClient{
+		baseURL: strings.TrimRight(*baseURL, "/"),
+		apiKey:  *apiKey,
+		http:    &http.Client{Timeout: *timeout},
+	}
+
+	// A unique name keeps runs against a shared instance from colliding
+	name := fmt.Sprintf("smoke-%d", time.Now().UnixNano())
+	result := report{BaseURL: client.baseURL, Config: name, Passed: true}
+
+	for _, step := range smokeSteps(client, name) {
+		start := time.Now()
+		err := step.run()
+		entry := stepResult{Step: step.name, Passed: err == nil, DurationMS: time.Since(start).Milliseconds()}
+		if err != nil {
+			entry.Error = err.Error()
+			result.Passed = false
+		}
+		result.Steps = append(result.Steps, entry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "smoke test failed at %s: %v\n", step.name, err)
+			break
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "writing report: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !result.Passed {
+		os.Exit(1)
+	}
+}
+
+// smokeStep is one named stage of the flow
+type smokeStep struct {
+	name string
+	run  func() error
+}
+
+// smokeSteps builds the flow for one configuration; later steps rely on the earlier ones
+func smokeSteps(client *smokeClient, name string) []smokeStep {
+	path := "/configs/" + name
+
+	return []smokeStep{
+		{"create", func() error {
+			var created models.ConfigurationCreated
+			body := map[string]interface{}{"name": name, "data": models.ConfigData{MaxLimit: 1000, Enabled: true}}
+			if err := client.do(http.MethodPost, "/configs", body, http.StatusCreated, &created); err != nil {
+				return err
+			}
+			return expect("version", created.Version, 1)
+		}},
+		{"get", func() error {
+			var latest models.ConfigurationData
+			if err := client.do(http.MethodGet, path, nil, http.StatusOK, &latest); err != nil {
+				return err
+			}
+			if err := expect("version", latest.Version, 1); err != nil {
+				return err
+			}
+			return expect("max_limit", latest.ConfigData.MaxLimit, 1000)
+		}},
+		{"update", func() error {
+			var updated models.ConfigurationUpdated
+			body := map[string]interface{}{"data": models.ConfigData{MaxLimit: 2000, Enabled: false}}
+			if err := client.do(http.MethodPut, path, body, http.StatusOK, &updated); err != nil {
+				return err
+			}
+			return expect("version", updated.Version, 2)
+		}},
+		{"list", func() error {
+			var list models.VersionList
+			if err := client.do(http.MethodGet, path+"/versions", nil, http.StatusOK, &list); err != nil {
+				return err
+			}
+			if err := expect("current_version", list.CurrentVersion, 2); err != nil {
+				return err
+			}
+			return expect("number of versions", len(list.Versions), 2)
+		}},
+		{"rollback", func() error {
+			var rollback models.ConfigurationRollback
+			body := models.RollbackConfigRequest{TargetVersion: 1}
+			if err := client.do(http.MethodPost, path+"/rollback", body, http.StatusOK, &rollback); err != nil {
+				return err
+			}
+			if err := expect("new_version", rollback.NewVersion, 3); err != nil {
+				return err
+			}
+
+			var latest models.ConfigurationData
+			if err := client.do(http.MethodGet, path, nil, http.StatusOK, &latest); err != nil {
+				return err
+			}
+			return expect("max_limit after rollback", latest.ConfigData.MaxLimit, 1000)
+		}},
+		{"delete", func() error {
+			var deleted models.ConfigurationsDeleted
+			if err := client.do(http.MethodDelete, "/configs?confirm=true&name_prefix="+name, nil, http.StatusOK, &deleted); err != nil {
+				return err
+			}
+			if err := expect("deleted", deleted.Deleted, 1); err != nil {
+				return err
+			}
+			return client.do(http.MethodGet, path, nil, http.StatusNotFound, nil)
+		}},
+	}
+}
+
+// do sends a request, checks the status code, and decodes the envelope's data into out
+func (sc *smokeClient) do(method, path string, body interface{}, wantStatus int, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, sc.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if sc.apiKey != "" {
+		req.Header.Set("X-API-Key", sc.apiKey)
+	}
+
+	resp, err := sc.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s: reading response: %w", method, path, err)
+	}
+
+	if resp.StatusCode != wantStatus {
+		return fmt.Errorf("%s %s: expected status %d, got %d: %s", method, path, wantStatus, resp.StatusCode, raw)
+	}
+
+	var envelope struct {
+		Success bool            `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return fmt.Errorf("%s %s: response is not JSON: %w", method, path, err)
+	}
+	wantSuccess := wantStatus < http.StatusBadRequest
+	if envelope.Success != wantSuccess {
+		return fmt.Errorf("%s %s: expected success=%t, got %s", method, path, wantSuccess, raw)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return fmt.Errorf("%s %s: decoding data: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// expect compares a response field against its expected value
+func expect(field string, got, want int) error {
+	if got != want {
+		return fmt.Errorf("expected %s %d, got %d", field, want, got)
+	}
+	return nil
+}
@@ -15,9 +15,75 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/configs": {
+        "/admin/audit/purge": {
             "post": {
-                "description": "Validates and creates a new configuration with version 1. The request must include a name and JSON data matching the schema.",
+                "description": "Deletes access log entries older than AUDIT_RETENTION_DAYS and reports how many were removed. Entries inside the window are never deleted. The same purge runs in the background every hour.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Purge access log entries past the retention window",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No retention is configured",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/backups": {
+            "get": {
+                "description": "Returns the most recent scheduled uploads of the configuration archive to object storage, newest first, with their size, outcome and number of attempts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List scheduled backups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/budgets": {
+            "get": {
+                "description": "Returns every per-configuration budget, ordered by name. Configurations without a budget are unrestricted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List configuration budgets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/budgets/{name}": {
+            "put": {
+                "description": "Limits the requests per minute addressing a configuration and the size of data written to it. Requests over budget get 429 and oversized writes 413, both with CONFIG_BUDGET_EXCEEDED. A zero limit leaves that dimension unrestricted. The configuration need not exist yet.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,23 +91,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "configurations"
+                    "admin"
                 ],
-                "summary": "Create a new configuration",
+                "summary": "Set a configuration budget",
                 "parameters": [
                     {
-                        "description": "Configuration data",
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget limits",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateConfigRequest"
+                            "$ref": "#/definitions/models.SetConfigBudgetRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
@@ -51,32 +124,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
                     }
                 }
-            }
-        },
-        "/api/v1/configs/{name}": {
-            "get": {
-                "description": "Returns the latest configuration data for the given name.",
+            },
+            "delete": {
+                "description": "Removes the budget of a configuration, leaving it unrestricted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "configurations"
+                    "admin"
                 ],
-                "summary": "Get the latest version of a configuration",
+                "summary": "Remove a configuration budget",
                 "parameters": [
                     {
                         "type": "string",
@@ -100,19 +159,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates the configuration data and increments the version number.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/configs/{name}/versions/{version}/raw": {
+            "get": {
+                "description": "Returns the exact value stored for a version, before decoding, with its encoding and size. For encoded (compressed) rows the decoded data is included as well, or the decode error if decoding fails. For diagnosing corruption and encoding mismatches.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "configurations"
+                    "admin"
                 ],
-                "summary": "Update an existing configuration",
+                "summary": "Get a version's data as stored",
                 "parameters": [
                     {
                         "type": "string",
@@ -122,13 +180,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated configuration data",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateConfigRequest"
-                        }
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -149,19 +205,33 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    }
+                }
+            }
+        },
+        "/admin/contiguity": {
+            "get": {
+                "description": "Reports every configuration whose versions do not run contiguously from 1 to current_version, with the missing version numbers and any versions numbered above current_version. Nothing is changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Check that version numbers are contiguous",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/configs/{name}/rollback": {
-            "post": {
-                "description": "Reverts the configuration to the specified version and increments the current version.",
+        "/admin/defaults/{name}": {
+            "put": {
+                "description": "Stores the payload GET /api/v1/configs/{name}?default=true serves while the configuration does not exist. Without a name it sets the global default, used for names without a default of their own. The data must match the schema.",
                 "consumes": [
                     "application/json"
                 ],
@@ -169,24 +239,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "configurations"
+                    "admin"
                 ],
-                "summary": "Rollback configuration to a previous version",
+                "summary": "Set a default configuration",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Configuration name",
+                        "description": "Configuration name; omit for the global default",
                         "name": "name",
-                        "in": "path",
-                        "required": true
+                        "in": "path"
                     },
                     {
-                        "description": "Target version to rollback to",
+                        "description": "Default data",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RollbackConfigRequest"
+                            "$ref": "#/definitions/models.SetDefaultConfigRequest"
                         }
                     }
                 ],
@@ -203,32 +272,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/configs/{name}/versions": {
-            "get": {
-                "description": "Returns a list of all version numbers and their creation timestamps for the specified configuration name.",
+            },
+            "delete": {
+                "description": "Removes the default stored for a name, or the global default when no name is given.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "configurations"
+                    "admin"
                 ],
-                "summary": "List all versions of a configuration",
+                "summary": "Remove a default configuration",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Configuration name",
+                        "description": "Configuration name; omit for the global default",
                         "name": "name",
-                        "in": "path",
-                        "required": true
+                        "in": "path"
                     }
                 ],
                 "responses": {
@@ -247,30 +313,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/configs/{name}/versions/{version}": {
+        "/admin/repair": {
+            "post": {
+                "description": "Detects configurations whose current_version has no matching version row and resets it to the highest version present. Reports every issue found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Repair configurations whose current version is missing",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/schema-violations": {
             "get": {
-                "description": "Returns the configuration data for the specified version.",
+                "description": "Validates one page of configurations, ordered by name, against the active schema and lists every version that fails with its validation errors. Checks current versions only unless versions=all. Use before tightening a schema to find data to fix first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "configurations"
+                    "admin"
                 ],
-                "summary": "Get a specific version of a configuration",
+                "summary": "Find versions that fail the active schema",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Configuration name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "description": "latest (default) or all",
+                        "name": "versions",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Version number",
-                        "name": "version",
-                        "in": "path",
-                        "required": true
+                        "description": "Configurations scanned per page (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Configurations to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -285,72 +375,2265 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/admin/schema/impact": {
+            "post": {
+                "description": "Validates the current version of every configuration against the supplied schema without activating it, and reports how many pass and which fail with their validation errors. Use it to plan a schema change: fix the failing configurations first, then activate the schema.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report the impact of a candidate schema",
+                "parameters": [
+                    {
+                        "description": "Candidate schema",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemaImpactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.CreateConfigRequest": {
-            "type": "object",
-			"properties": {
-                "name": {
-                    "type": "string",
-                    "example": "feature-toggle-new"
-                },
-                "data": {
-                    "type": "object",
-                    "properties": {
-                        "max_limit": {
-                            "type": "integer",
-                            "maximum": 1000,
-                            "minimum": 1,
-                            "example": 500
-                        },
-                        "enabled": {
-                            "type": "boolean",
-                            "example": true
+        },
+        "/admin/verify-checksums": {
+            "post": {
+                "description": "Recomputes the checksum of every stored version's data and reports each version whose data no longer matches the checksum recorded when it was written, to detect disk or replication corruption. Data is read in throttled batches. Nothing is changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Verify stored data against its checksums",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     }
                 }
-            },
-            "example": {
-                "name": "feature-toggle-new",
-                "data": {
-                    "max_limit": 500,
-                    "enabled": true
-                }
-            }
-        },
-        "models.ErrorDetail": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "details": {},
-                "message": {
-                    "type": "string"
-                }
             }
         },
-        "models.ErrorResponse": {
-            "type": "object",
+        "/api/v1/configs": {
+            "get": {
+                "description": "Returns every configuration with its current version and timestamps, sorted server-side. Repeated tag=key:value filters list only the configurations carrying all of the given tags.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "List configurations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sort field: name, created_at, updated_at or version (default name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc (default asc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of configurations to return (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of configurations to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list soft-deleted configurations, with their deleted_at",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag filter written key:value; repeat to require several tags",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Validates and creates a new configuration with version 1. The request must include a name and JSON data matching the schema. With schema_hash set, the create only happens if the active schema has that hash; otherwise 409 SCHEMA_MISMATCH reports the active hash.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Create a new configuration",
+                "parameters": [
+                    {
+                        "description": "Configuration data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft-deletes every configuration whose name starts with name_prefix in one transaction; their versions are kept and each can be restored. Requires confirm=true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Bulk delete configurations by name prefix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name prefix of the configurations to delete",
+                        "name": "name_prefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Must be true to perform the deletion",
+                        "name": "confirm",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/all": {
+            "get": {
+                "description": "Returns every configuration's latest version and data, ordered by name, read with a single query. With format=jsonl (or Accept: application/x-ndjson) the configurations are streamed as JSON Lines, one object per line, as they are read.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Get the latest data of every configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json (default) or jsonl",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/batch": {
+            "post": {
+                "description": "Validates and creates each configuration of the array at version 1, in a single transaction, and reports per item whether it was created or the error that prevented it, for up to 1000 items. By default every valid item is created and 207 reports the failed ones; with atomic=true a single failure creates nothing and the valid items report BATCH_ROLLED_BACK. With dry_run=true every item is checked, existing names included, but nothing is created: 200 reports that every item would be created, 207 lists the ones that would fail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Create many configurations",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Create either every item or none",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report what would be created without creating anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Configurations to create",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BatchCreateItem"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run: every item would be created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Every item created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Some items failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/sync": {
+            "post": {
+                "description": "Takes the version the client last saw of each configuration and, in one round trip, returns the configurations with a newer current version, the configurations the client does not know yet, and the known names that no longer exist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Delta sync of many configurations",
+                "parameters": [
+                    {
+                        "description": "Last-seen version per configuration",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncConfigsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}": {
+            "get": {
+                "description": "Returns the latest configuration data for the given name. With apply_defaults=true, the active schema's default values fill in missing optional keys; this is a read-time overlay and does not change what is stored. Responses carry an ETag of the version and data served, which changes on every update or rollback; a matching If-None-Match returns 304.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Get the latest version of a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Merge schema defaults into missing keys",
+                        "name": "apply_defaults",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Serve the stored default (version 0) if the configuration does not exist",
+                        "name": "default",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Serve the newest unpublished draft when one is pending",
+                        "name": "include_drafts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), original (the text as authored), flat (dot-notation map) or env (export lines)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Validator of the version served"
+                            },
+                            "X-Config-Checksum": {
+                                "type": "string",
+                                "description": "SHA-256 of the canonical data"
+                            },
+                            "X-Config-Default": {
+                                "type": "string",
+                                "description": "name or global when a stored default was served"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Latest version unchanged since the given ETag"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the configuration data and increments the version number. With create_if_missing=true (or the AUTO_CREATE_ON_UPDATE policy) a configuration that does not exist is created at version 1 and 201 is returned instead of 404. With expected_version in the body the update only applies while that is the current version, and fails with 409 VERSION_CONFLICT otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Update an existing configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create the configuration when it does not exist",
+                        "name": "create_if_missing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Store the update as a draft that takes effect once published",
+                        "name": "draft",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Updated configuration data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Draft created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft-deletes a configuration: it is hidden from reads, listings and writes, but its row and versions are kept for audit and it can be restored. A configuration with a protected version cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Delete a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Applies a patch to the data of the current version and stores the result as a new version, validated against the schema like an update. Send a JSON Patch (RFC 6902) with Content-Type application/json-patch+json, or a JSON Merge Patch (RFC 7386) with Content-Type application/merge-patch+json. A JSON Patch that does not apply to the current data, e.g. removing a missing member or failing a test operation, is rejected with 409 PATCH_CONFLICT.",
+                "consumes": [
+                    "application/json-patch+json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Partially update a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON Patch operations or merge patch",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/diff": {
+            "get": {
+                "description": "Returns a structured diff of the data of two versions, for example to see what a rollback changed. Keys are dot-notation paths, so nested changes are reported at the leaf; added keys exist only in to, removed keys only in from, and changed keys carry the old (from) and new (to) values. With format=unified the diff is instead a text/plain unified diff of both versions' data pretty-printed with sorted keys, empty when they are identical.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Diff two versions of a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to diff from",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to diff to",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "unified"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/duplicates": {
+            "get": {
+                "description": "Groups the versions of a configuration by data checksum and returns the clusters of versions sharing identical content.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "List versions with identical data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/evaluate": {
+            "get": {
+                "description": "Returns the latest enabled and max_limit values in a flat body without the standard envelope, for flag consumers. For a gradual rollout (rollout_percent), enabled is true only when the key falls in the rollout's hash buckets; without a key only a full rollout is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Evaluate a configuration as a feature flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stable identifier of the subject, such as a user id",
+                        "name": "key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FlagEvaluation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/meta": {
+            "get": {
+                "description": "Returns the name, current version, timestamps and flags of a configuration without its data. Cheaper than fetching the latest version, since no version row is read.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Get configuration metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/prune": {
+            "post": {
+                "description": "Deletes every published version older than the keep most recent ones, in one transaction. The current version, the redo target, protected versions and drafts are never deleted, and the remaining versions keep their numbers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Prune a configuration's old versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of most recent published versions to keep, at least 1",
+                        "name": "keep",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/redo": {
+            "post": {
+                "description": "Restores the version that was current before the latest rollback or undo, creating a new version. Only valid until the configuration is edited again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Redo the latest rollback of a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/rename": {
+            "post": {
+                "description": "Moves a configuration to a new name together with its full version history, access log and squash log, in one transaction. Fails if the new name is already taken.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Rename a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RenameConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/restore": {
+            "post": {
+                "description": "Makes a soft-deleted configuration visible again at the version it was deleted at. Restoring a configuration that is not deleted fails with 409 CONFIG_NOT_DELETED.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Restore a deleted configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/rollback": {
+            "post": {
+                "description": "Reverts the configuration to the specified version and increments the current version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Rollback configuration to a previous version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target version to rollback to",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RollbackConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/sensitive": {
+            "put": {
+                "description": "Marks or unmarks a configuration as sensitive. Reads and writes of sensitive configurations are recorded in the access log when access logging is enabled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Flag a configuration as sensitive",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sensitive flag",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetSensitiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/squash": {
+            "post": {
+                "description": "Deletes every version below keep_from and renumbers the remaining versions from 1, updating the current version, in one transaction. Version numbers change, so this requires confirm=true and is recorded with the X-Actor caller in the squash log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Squash a configuration's history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Earliest version to keep; it becomes version 1",
+                        "name": "keep_from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Must be true to perform the squash",
+                        "name": "confirm",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caller recorded in the squash log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/storage": {
+            "get": {
+                "description": "Returns the total bytes stored across every version of a configuration, with the version count and average bytes per version, for chargeback and finding storage-heavy configurations.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Get a configuration's storage footprint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/tags": {
+            "get": {
+                "description": "Returns the key/value tags of a configuration, an empty object when it has none.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Get a configuration's tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Sets key/value tags on a configuration for grouping and filtering (see the tag filter of GET /api/v1/configs). Keys it already has get the new value; its other tags are kept. Returns all of the configuration's tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Tag a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags to set",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/tags/{key}": {
+            "delete": {
+                "description": "Removes the tag with the given key from a configuration.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Remove a tag from a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/undo": {
+            "post": {
+                "description": "Rolls the configuration back to the version immediately before the current one, creating a new version.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Undo the last change to a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/validate": {
+            "post": {
+                "description": "Validates candidate data against the schema the configuration uses and reports whether it is valid, with field errors localized per Accept-Language. Runs every check a new version goes through, including the agreement of status and enabled. Nothing is written and no version is created; invalid data still returns 200, while data over the configuration's size budget is refused with 413 as a write would be.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Validate data against a configuration's schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Candidate data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateConfigRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language for validation messages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/versions": {
+            "get": {
+                "description": "Returns a list of all version numbers and their creation timestamps for the specified configuration name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "List all versions of a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include unpublished drafts",
+                        "name": "include_drafts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include each version's data",
+                        "name": "include_data",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include each version's age by the server's clock",
+                        "name": "include_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return an empty list instead of 404 when the configuration does not exist",
+                        "name": "missing_ok",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of versions to return (default all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of versions to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/versions/{version}": {
+            "get": {
+                "description": "Returns the configuration data for the specified version. Responses carry a strong ETag built from the data checksum and are revalidated (Cache-Control: no-cache), since squashing renumbers versions; a matching If-None-Match returns 304.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Get a specific version of a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), original (the text as authored), flat (dot-notation map) or env (export lines)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Serve the version even if it is an unpublished draft",
+                        "name": "include_drafts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Strong validator of the version"
+                            },
+                            "X-Config-Checksum": {
+                                "type": "string",
+                                "description": "SHA-256 of the canonical data"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Version unchanged since the given ETag"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/versions/{version}/protect": {
+            "post": {
+                "description": "Marks a version as protected, for example a compliance-certified release. Squashing and bulk deletes refuse with 409 VERSION_PROTECTED rather than remove or renumber a protected version. Protection is permanent; protecting a protected version again succeeds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Protect a version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/versions/{version}/publish": {
+            "post": {
+                "description": "Approves a draft created with PUT ?draft=true, making it the current version served as the latest. Publishing a version that is not a draft fails with 409 VERSION_NOT_DRAFT, and a draft older than the current version with 409 DRAFT_SUPERSEDED.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Publish a draft version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs/{name}/versions/{version}/schema": {
+            "get": {
+                "description": "Returns the schema that was active when the specified version was created, identified by its hash.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Get the schema a version was created under",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/configs:exists": {
+            "post": {
+                "description": "Returns a map of configuration name to whether it exists, for up to 1000 names in one request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Check which configurations exist",
+                "parameters": [
+                    {
+                        "description": "Names to check",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ConfigsExistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/diff": {
+            "get": {
+                "description": "Returns a structured diff of the data of two configurations, for example the same logical configuration stored per environment. Each side is its latest version unless a_version or b_version is given. Keys are dot-notation paths; added keys exist only in b, removed keys only in a, and changed keys carry the old (a) and new (b) values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configurations"
+                ],
+                "summary": "Compare two configurations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the first configuration",
+                        "name": "a",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the second configuration",
+                        "name": "b",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version of a (default latest)",
+                        "name": "a_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version of b (default latest)",
+                        "name": "b_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/export": {
+            "get": {
+                "description": "Streams an archive of every live configuration with all of its versions, data and timestamps, for backups and for moving configurations between environments with the import endpoint. Configurations are written as they are read, so large datasets are never held in memory; a failure after the first configuration is sent leaves the document truncated. Soft-deleted configurations are not exported.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export every configuration with its full history",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExportArchive"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/export/env": {
+            "get": {
+                "description": "Returns an object mapping every configuration name to its latest data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export the effective configuration of the environment",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/import": {
+            "post": {
+                "description": "Recreates the configurations of an archive produced by GET /api/v1/export, keeping their version numbers, statuses and timestamps, in a single transaction. mode says what happens to a name that already exists, live or soft-deleted: fail (default) rejects the import with 409, skip keeps the existing configuration, overwrite replaces it and its history. Every version is validated against the active schema first; if any fails, nothing is imported and each failing version is listed in details.invalid_versions. With dry_run=true nothing is written: the result tells what the import would do, and instead of the first conflicting configuration, a 409 IMPORT_CONFLICTS lists all of them in details.conflicts. Version numbers repeated within a configuration are rejected up front with 400 DUPLICATE_VERSION_IN_IMPORT listing them in details.duplicates; with require_contiguous=true, missing version numbers are rejected with 400 VERSION_GAP_IN_IMPORT listing them in details.gaps.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Import configurations from an export archive",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "fail (default), skip or overwrite",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report what would be imported without writing anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Refuse configurations whose versions do not run from 1 without gaps",
+                        "name": "require_contiguous",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Export archive",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ExportArchive"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rpc": {
+            "post": {
+                "description": "Accepts a single JSON-RPC 2.0 request or a batch (array) of requests. Supported methods: createConfig, updateConfig, rollbackConfig, getLatestConfig, getConfigVersion, listVersions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rpc"
+                ],
+                "summary": "JSON-RPC 2.0 endpoint",
+                "parameters": [
+                    {
+                        "description": "JSON-RPC request or batch",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RPCRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RPCResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "All requests were notifications"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.BatchCreateItem": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "feature_toggle"
+                }
+            }
+        },
+        "models.ConfigsExistRequest": {
+            "type": "object",
+            "properties": {
+                "names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "feature-toggle",
+                        "rate-limits"
+                    ]
+                }
+            }
+        },
+        "models.CreateConfigRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "data_encoding": {
+                    "description": "DataEncoding, when \"base64\", means data is a string holding base64-encoded JSON",
+                    "type": "string",
+                    "example": "base64"
+                },
+                "format": {
+                    "description": "Format names the format of data given as a string (json, yaml or toml); detected when omitted",
+                    "type": "string",
+                    "example": "yaml"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "feature_toggle"
+                },
+                "schema_hash": {
+                    "description": "SchemaHash, when set, makes the create conditional on the server's active schema having this hash",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                }
+            }
+        },
+        "models.ErrorDetail": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {},
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
             "properties": {
                 "error": {
                     "$ref": "#/definitions/models.ErrorDetail"
                 },
+                "request_id": {
+                    "description": "RequestID correlates a failed service call with the server logs",
+                    "type": "string"
+                },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
+        "models.ExportArchive": {
+            "type": "object",
+            "properties": {
+                "configurations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExportedConfiguration"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "format_version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExportedConfiguration": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current_version": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sensitive": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExportedVersion"
+                    }
+                }
+            }
+        },
+        "models.ExportedVersion": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "format": {
+                    "type": "string"
+                },
+                "original": {
+                    "type": "string"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FlagEvaluation": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "max_limit": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rollout_percent": {
+                    "description": "RolloutPercent and Bucket are set for gradual rollouts; Bucket (0-99) only when a key was given",
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RPCError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RPCRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "jsonrpc": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.RPCResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/models.RPCError"
+                },
+                "id": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "jsonrpc": {
+                    "type": "string"
+                },
+                "result": {}
+            }
+        },
+        "models.RenameConfigRequest": {
+            "type": "object",
+            "properties": {
+                "new_name": {
+                    "type": "string",
+                    "example": "checkout-limits"
+                }
+            }
+        },
         "models.RollbackConfigRequest": {
             "type": "object",
             "properties": {
@@ -360,6 +2643,57 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SchemaImpactRequest": {
+            "type": "object",
+            "properties": {
+                "schema": {
+                    "type": "object"
+                }
+            }
+        },
+        "models.SetConfigBudgetRequest": {
+            "type": "object",
+            "properties": {
+                "max_data_bytes": {
+                    "description": "MaxDataBytes caps the size of data written to the configuration; 0 is unlimited",
+                    "type": "integer",
+                    "example": 4096
+                },
+                "max_requests_per_minute": {
+                    "description": "MaxRequestsPerMinute caps requests addressing the configuration; 0 is unlimited",
+                    "type": "integer",
+                    "example": 600
+                }
+            }
+        },
+        "models.SetDefaultConfigRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                }
+            }
+        },
+        "models.SetSensitiveRequest": {
+            "type": "object",
+            "properties": {
+                "sensitive": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.SetTagsRequest": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "models.SuccessResponse": {
             "type": "object",
             "properties": {
@@ -372,37 +2706,63 @@ const docTemplate = `{
                 }
             }
         },
-        "models.UpdateConfigRequest": {
+        "models.SyncConfigsRequest": {
             "type": "object",
-			"properties": {
-                "data": {
+            "properties": {
+                "known": {
                     "type": "object",
-                    "properties": {
-                        "max_limit": {
-                            "type": "integer",
-                            "maximum": 1000,
-                            "minimum": 1,
-                            "example": 800
-                        },
-                        "enabled": {
-                            "type": "boolean",
-                            "example": false
-                        }
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "feature-toggle": 3,
+                        "rate-limits": 1
                     }
                 }
             }
+        },
+        "models.UpdateConfigRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "data_encoding": {
+                    "description": "DataEncoding, when \"base64\", means data is a string holding base64-encoded JSON",
+                    "type": "string",
+                    "example": "base64"
+                },
+                "expected_version": {
+                    "description": "ExpectedVersion, when set, makes the update apply only while it is the current version",
+                    "type": "integer",
+                    "example": 3
+                },
+                "format": {
+                    "description": "Format names the format of data given as a string (json, yaml or toml); detected when omitted",
+                    "type": "string",
+                    "example": "yaml"
+                }
+            }
+        },
+        "models.ValidateConfigRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                }
+            }
         }
     }
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "",
+	Version:          "1.0.0",
 	Host:             "",
 	BasePath:         "",
 	Schemes:          []string{},
-	Title:            "",
-	Description:      "",
+	Title:            "Configuration Management API",
+	Description:      "API for managing versioned configurations with validation and rollback capabilities.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
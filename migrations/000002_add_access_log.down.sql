@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_access_log_config_created;
+DROP TABLE IF EXISTS access_log;
+
+ALTER TABLE configurations DROP COLUMN sensitive;
@@ -0,0 +1,12 @@
+ALTER TABLE configurations ADD COLUMN sensitive INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE access_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    configuration_name TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for "who accessed config X" audit queries
+CREATE INDEX idx_access_log_config_created ON access_log(configuration_name, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE versions DROP COLUMN schema_hash;
+
+DROP TABLE schemas;
@@ -0,0 +1,7 @@
+CREATE TABLE schemas (
+    hash TEXT PRIMARY KEY,
+    schema_json TEXT NOT NULL,
+    created_at TEXT DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE versions ADD COLUMN schema_hash TEXT REFERENCES schemas(hash);
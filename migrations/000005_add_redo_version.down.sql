@@ -0,0 +1 @@
+ALTER TABLE configurations DROP COLUMN redo_version;
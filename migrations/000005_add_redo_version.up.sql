@@ -0,0 +1,2 @@
+-- Version superseded by the latest rollback; cleared by any other write
+ALTER TABLE configurations ADD COLUMN redo_version INTEGER;
@@ -0,0 +1,2 @@
+ALTER TABLE versions DROP COLUMN original_data;
+ALTER TABLE versions DROP COLUMN format;
@@ -0,0 +1,3 @@
+-- Format the version was authored in and its original text; json_data keeps the canonical JSON
+ALTER TABLE versions ADD COLUMN format TEXT NOT NULL DEFAULT 'json';
+ALTER TABLE versions ADD COLUMN original_data TEXT;
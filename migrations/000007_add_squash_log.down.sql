@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_squash_log_config_created;
+DROP TABLE IF EXISTS squash_log;
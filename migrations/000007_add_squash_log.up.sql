@@ -0,0 +1,13 @@
+-- Audit trail of history squashes, which delete and renumber versions
+CREATE TABLE squash_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    configuration_name TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    keep_from INTEGER NOT NULL,
+    removed_versions INTEGER NOT NULL,
+    previous_current_version INTEGER NOT NULL,
+    new_current_version INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_squash_log_config_created ON squash_log(configuration_name, created_at DESC);
@@ -0,0 +1,3 @@
+UPDATE versions SET json_data = (SELECT data FROM version_blobs WHERE id = versions.blob_id) WHERE blob_id IS NOT NULL;
+ALTER TABLE versions DROP COLUMN blob_id;
+DROP TABLE IF EXISTS version_blobs;
@@ -0,0 +1,10 @@
+-- Content-addressed version data: identical data (in canonical form) is stored once and shared.
+-- Existing json_data is moved into blobs by storage.Migrate, since SQLite cannot hash in SQL;
+-- json_data stays as the fallback for rows not yet backfilled.
+CREATE TABLE version_blobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hash TEXT NOT NULL UNIQUE,
+    data TEXT NOT NULL
+);
+
+ALTER TABLE versions ADD COLUMN blob_id INTEGER REFERENCES version_blobs(id);
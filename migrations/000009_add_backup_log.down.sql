@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_backup_log_created;
+DROP TABLE IF EXISTS backup_log;
@@ -0,0 +1,12 @@
+-- Outcome of each scheduled backup upload to object storage
+CREATE TABLE backup_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    object_key TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    error TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_backup_log_created ON backup_log(created_at DESC);
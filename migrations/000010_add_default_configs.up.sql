@@ -0,0 +1,7 @@
+-- Payloads served by GET ?default=true for configurations that do not exist yet;
+-- the row named '*' is the global default used when a name has none of its own
+CREATE TABLE default_configs (
+    name TEXT PRIMARY KEY,
+    json_data TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
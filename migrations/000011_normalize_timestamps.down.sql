@@ -0,0 +1,8 @@
+-- Restore the "YYYY-MM-DD HH:MM:SS.SSS" UTC form of timestamps
+UPDATE configurations SET created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f', created_at), created_at), updated_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f', updated_at), updated_at);
+UPDATE versions SET created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f', created_at), created_at);
+UPDATE access_log SET created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f', created_at), created_at);
+UPDATE schemas SET created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f', created_at), created_at);
+UPDATE squash_log SET created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f', created_at), created_at);
+UPDATE backup_log SET created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f', created_at), created_at);
+UPDATE default_configs SET updated_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f', updated_at), updated_at);
@@ -0,0 +1,9 @@
+-- Rewrite every stored timestamp as UTC RFC3339 with a nine-digit fraction, the one format the
+-- application writes. Values SQLite cannot parse are left as they are.
+UPDATE configurations SET created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%f', created_at) || '000000Z', created_at), updated_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%f', updated_at) || '000000Z', updated_at);
+UPDATE versions SET created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%f', created_at) || '000000Z', created_at);
+UPDATE access_log SET created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%f', created_at) || '000000Z', created_at);
+UPDATE schemas SET created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%f', created_at) || '000000Z', created_at);
+UPDATE squash_log SET created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%f', created_at) || '000000Z', created_at);
+UPDATE backup_log SET created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%f', created_at) || '000000Z', created_at);
+UPDATE default_configs SET updated_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%f', updated_at) || '000000Z', updated_at);
@@ -0,0 +1,8 @@
+-- Per-configuration limits set by operators for configurations known to be abused;
+-- a zero limit leaves that dimension unrestricted
+CREATE TABLE config_budgets (
+    name TEXT PRIMARY KEY,
+    max_requests_per_minute INTEGER NOT NULL DEFAULT 0,
+    max_data_bytes INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME NOT NULL
+);
@@ -0,0 +1,2 @@
+-- Protected versions (e.g. compliance-certified releases) survive every cleanup operation
+ALTER TABLE versions ADD COLUMN protected INTEGER NOT NULL DEFAULT 0;
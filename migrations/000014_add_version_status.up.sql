@@ -0,0 +1,2 @@
+-- Drafts wait for approval above current_version and only become active once published
+ALTER TABLE versions ADD COLUMN status TEXT NOT NULL DEFAULT 'published';
@@ -0,0 +1 @@
+ALTER TABLE configurations DROP COLUMN deleted_at;
@@ -0,0 +1,2 @@
+-- Deleted configurations keep their rows and versions for audit; deleted_at marks them hidden
+ALTER TABLE configurations ADD COLUMN deleted_at TEXT;
@@ -0,0 +1,10 @@
+-- Key/value labels grouping configurations (e.g. team=payments); a configuration has at most one value per key
+CREATE TABLE tags (
+    configuration_name TEXT NOT NULL REFERENCES configurations(name),
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (configuration_name, key)
+);
+
+-- Index for filtering configurations by tag
+CREATE INDEX idx_tags_key_value ON tags(key, value);
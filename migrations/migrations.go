@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations so the server and the tests
+// apply exactly the same files
+package migrations
+
+import "embed"
+
+// FS holds the numbered up/down migration files
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS config_budgets;
+DROP TABLE IF EXISTS default_configs;
+DROP TABLE IF EXISTS backup_log;
+DROP TABLE IF EXISTS squash_log;
+DROP TABLE IF EXISTS access_log;
+DROP TABLE IF EXISTS versions;
+DROP TABLE IF EXISTS version_blobs;
+DROP TABLE IF EXISTS schemas;
+DROP TABLE IF EXISTS configurations;
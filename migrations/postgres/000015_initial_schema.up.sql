@@ -0,0 +1,89 @@
+-- Baseline equal to SQLite migrations 000001-000015. Timestamps are stored as text in the
+-- application's UTC RFC3339 format and flags as integers, exactly as on SQLite, and text
+-- sorts bytewise (COLLATE "C") so listings order the same on both backends.
+CREATE TABLE configurations (
+    name TEXT COLLATE "C" PRIMARY KEY,
+    current_version INTEGER NOT NULL,
+    created_at TEXT COLLATE "C" NOT NULL,
+    updated_at TEXT COLLATE "C" NOT NULL,
+    sensitive INTEGER NOT NULL DEFAULT 0,
+    redo_version INTEGER,
+    deleted_at TEXT COLLATE "C"
+);
+
+CREATE TABLE schemas (
+    hash TEXT PRIMARY KEY,
+    schema_json TEXT NOT NULL,
+    created_at TEXT COLLATE "C"
+);
+
+CREATE TABLE version_blobs (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    hash TEXT NOT NULL UNIQUE,
+    data TEXT NOT NULL
+);
+
+CREATE TABLE versions (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    configuration_name TEXT COLLATE "C" NOT NULL REFERENCES configurations(name),
+    version_number INTEGER NOT NULL,
+    json_data TEXT NOT NULL,
+    created_at TEXT COLLATE "C" NOT NULL,
+    schema_hash TEXT REFERENCES schemas(hash),
+    format TEXT NOT NULL DEFAULT 'json',
+    original_data TEXT,
+    blob_id BIGINT REFERENCES version_blobs(id),
+    protected INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'published',
+    UNIQUE (configuration_name, version_number)
+);
+
+CREATE INDEX idx_versions_config_created ON versions(configuration_name, created_at DESC);
+
+CREATE TABLE access_log (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    configuration_name TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    created_at TEXT COLLATE "C" NOT NULL
+);
+
+CREATE INDEX idx_access_log_config_created ON access_log(configuration_name, created_at DESC);
+
+CREATE TABLE squash_log (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    configuration_name TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    keep_from INTEGER NOT NULL,
+    removed_versions INTEGER NOT NULL,
+    previous_current_version INTEGER NOT NULL,
+    new_current_version INTEGER NOT NULL,
+    created_at TEXT COLLATE "C" NOT NULL
+);
+
+CREATE INDEX idx_squash_log_config_created ON squash_log(configuration_name, created_at DESC);
+
+CREATE TABLE backup_log (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    object_key TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    error TEXT,
+    created_at TEXT COLLATE "C" NOT NULL
+);
+
+CREATE INDEX idx_backup_log_created ON backup_log(created_at DESC);
+
+CREATE TABLE default_configs (
+    name TEXT COLLATE "C" PRIMARY KEY,
+    json_data TEXT NOT NULL,
+    updated_at TEXT COLLATE "C" NOT NULL
+);
+
+CREATE TABLE config_budgets (
+    name TEXT COLLATE "C" PRIMARY KEY,
+    max_requests_per_minute INTEGER NOT NULL DEFAULT 0,
+    max_data_bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TEXT COLLATE "C" NOT NULL
+);
@@ -0,0 +1,9 @@
+-- Key/value labels grouping configurations (e.g. team=payments); a configuration has at most one value per key
+CREATE TABLE tags (
+    configuration_name TEXT COLLATE "C" NOT NULL REFERENCES configurations(name),
+    key TEXT COLLATE "C" NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (configuration_name, key)
+);
+
+CREATE INDEX idx_tags_key_value ON tags(key, value);
@@ -0,0 +1 @@
+ALTER TABLE versions DROP COLUMN created_by;
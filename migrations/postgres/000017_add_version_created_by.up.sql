@@ -0,0 +1,2 @@
+-- Who created each version, from the X-Actor header; NULL for versions written before it was tracked
+ALTER TABLE versions ADD COLUMN created_by TEXT;
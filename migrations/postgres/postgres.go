@@ -0,0 +1,10 @@
+// Package postgres embeds the schema migrations for the PostgreSQL backend. Its numbering
+// follows the SQLite migrations, starting from a baseline equal to their combined schema.
+package postgres
+
+import "embed"
+
+// FS holds the numbered up/down migration files
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,557 @@
+package handlers
+
+import (
+	"net/http"
+
+	"config-manager/src/models"
+	"config-manager/src/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RepairCurrentVersions handles POST /admin/repair
+//
+//	@Summary		Repair configurations whose current version is missing
+//	@Description	Detects configurations whose current_version has no matching version row and resets it to the highest version present. Reports every issue found.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse	"OK"
+//	@Router			/admin/repair [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration integrity repair completed",
+//	  "data": {
+//	    "repaired": 1,
+//	    "issues": [
+//	      {"name": "feature-toggle", "previous_version": 7, "current_version": 5, "repaired": true}
+//	    ]
+//	  }
+//	}
+func (ch *ConfigHandler) RepairCurrentVersions(c echo.Context) error {
+	report, err := ch.configService.RepairCurrentVersions()
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration integrity repair completed",
+		Data:    report,
+	})
+}
+
+// CheckVersionContiguity handles GET /admin/contiguity
+//
+//	@Summary		Check that version numbers are contiguous
+//	@Description	Reports every configuration whose versions do not run contiguously from 1 to current_version, with the missing version numbers and any versions numbered above current_version. Nothing is changed.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse	"OK"
+//	@Router			/admin/contiguity [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "contiguous": false,
+//	    "gaps": [
+//	      {"name": "imported-limits", "current_version": 5, "missing": [2, 3], "beyond": []}
+//	    ]
+//	  }
+//	}
+func (ch *ConfigHandler) CheckVersionContiguity(c echo.Context) error {
+	report, err := ch.configService.CheckVersionContiguity()
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// VerifyChecksums handles POST /admin/verify-checksums
+//
+//	@Summary		Verify stored data against its checksums
+//	@Description	Recomputes the checksum of every stored version's data and reports each version whose data no longer matches the checksum recorded when it was written, to detect disk or replication corruption. Data is read in throttled batches. Nothing is changed.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse	"OK"
+//	@Router			/admin/verify-checksums [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "intact": false,
+//	    "checked_blobs": 42,
+//	    "unchecked_versions": 0,
+//	    "mismatches": [
+//	      {"name": "payment-limits", "version": 3, "stored_checksum": "9f86d0...", "computed_checksum": "2c26b4..."}
+//	    ],
+//	    "checked_at": "2025-09-15T12:00:00Z"
+//	  }
+//	}
+func (ch *ConfigHandler) VerifyChecksums(c echo.Context) error {
+	report, err := ch.configService.VerifyChecksums()
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// PurgeAuditLog handles POST /admin/audit/purge
+//
+//	@Summary		Purge access log entries past the retention window
+//	@Description	Deletes access log entries older than AUDIT_RETENTION_DAYS and reports how many were removed. Entries inside the window are never deleted. The same purge runs in the background every hour.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse	"OK"
+//	@Failure		409	{object}	models.ErrorResponse	"No retention is configured"
+//	@Router			/admin/audit/purge [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Audit log purged",
+//	  "data": {
+//	    "retention_days": 90,
+//	    "cutoff": "2025-06-09T12:00:00Z",
+//	    "removed": 1250
+//	  }
+//	}
+func (ch *ConfigHandler) PurgeAuditLog(c echo.Context) error {
+	result, err := ch.configService.PurgeAuditLog()
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Audit log purged",
+		Data:    result,
+	})
+}
+
+// ListBackups handles GET /admin/backups
+//
+//	@Summary		List scheduled backups
+//	@Description	Returns the most recent scheduled uploads of the configuration archive to object storage, newest first, with their size, outcome and number of attempts.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse	"OK"
+//	@Router			/admin/backups [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "backups": [
+//	      {"id": 2, "object_key": "config-backups/config-archive-20250907T130000Z.json", "size_bytes": 5120, "status": "succeeded", "attempts": 1, "created_at": "2025-09-07T13:00:00Z"},
+//	      {"id": 1, "object_key": "config-backups/config-archive-20250907T120000Z.json", "size_bytes": 5080, "status": "failed", "attempts": 3, "error": "object store returned status 503: ", "created_at": "2025-09-07T12:00:00Z"}
+//	    ]
+//	  }
+//	}
+func (ch *ConfigHandler) ListBackups(c echo.Context) error {
+	backups, err := ch.configService.ListBackups()
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    backups,
+	})
+}
+
+// defaultViolationScanLimit bounds a schema violation scan when no limit is given
+const defaultViolationScanLimit = 100
+
+// GetRawVersionData handles GET /admin/configs/{name}/versions/{version}/raw
+//
+//	@Summary		Get a version's data as stored
+//	@Description	Returns the exact value stored for a version, before decoding, with its encoding and size. For encoded (compressed) rows the decoded data is included as well, or the decode error if decoding fails. For diagnosing corruption and encoding mismatches.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			version	path		int		true	"Version number"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/admin/configs/{name}/versions/{version}/raw [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "version": 3,
+//	    "blob_hash": "5d41402abc4b2a76b9719d911017c592ae2d0e1a2c7ed7d6dc2cdb0b5a2bc1f3",
+//	    "encoding": "gzip",
+//	    "stored": "gzip:H4sIAAAAAAAA/6pWys1MzsnMS1WyMjQwqAUEAAD//w==",
+//	    "stored_bytes": 48,
+//	    "decoded": "{\"max_limit\": 100, \"enabled\": true}"
+//	  }
+//	}
+func (ch *ConfigHandler) GetRawVersionData(c echo.Context) error {
+	version, errDetail := parseVersionParam(c.Param("version"))
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	name := c.Param("name")
+	raw, err := ch.configService.GetRawVersionData(name, version)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionRead)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    raw,
+	})
+}
+
+// FindSchemaViolations handles GET /admin/schema-violations
+//
+//	@Summary		Find versions that fail the active schema
+//	@Description	Validates one page of configurations, ordered by name, against the active schema and lists every version that fails with its validation errors. Checks current versions only unless versions=all. Use before tightening a schema to find data to fix first.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			versions	query		string	false	"latest (default) or all"
+//	@Param			limit		query		int		false	"Configurations scanned per page (default 100)"
+//	@Param			offset		query		int		false	"Configurations to skip"
+//	@Success		200			{object}	models.SuccessResponse	"OK"
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Router			/admin/schema-violations [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "schema_hash": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+//	    "checked_versions": 2,
+//	    "violations": [
+//	      {"name": "legacy-limits", "version": 4, "validation_errors": [{"field": "max_limit", "error": "Must be less than or equal to 1000", "type": "number_lte"}]}
+//	    ],
+//	    "pagination": {"total": 2, "limit": 100, "offset": 0, "next": null, "prev": null}
+//	  }
+//	}
+func (ch *ConfigHandler) FindSchemaViolations(c echo.Context) error {
+	scope := c.QueryParam("versions")
+	if scope != "" && scope != "latest" && scope != "all" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_VERSIONS_SCOPE",
+				Message: "versions must be latest or all",
+				Details: map[string]string{"versions": scope},
+			},
+		})
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return invalidPagination(c, err)
+	}
+	if limit == 0 {
+		limit = defaultViolationScanLimit
+	}
+
+	report, err := ch.configService.FindSchemaViolations(scope == "all", limit, offset)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+	setPaginationLinks(c, report.Pagination)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// SchemaImpact handles POST /admin/schema/impact
+//
+//	@Summary		Report the impact of a candidate schema
+//	@Description	Validates the current version of every configuration against the supplied schema without activating it, and reports how many pass and which fail with their validation errors. Use it to plan a schema change: fix the failing configurations first, then activate the schema.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.SchemaImpactRequest	true	"Candidate schema"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Router			/admin/schema/impact [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "schema_hash": "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+//	    "active_schema_hash": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+//	    "checked": 2,
+//	    "passing": 1,
+//	    "failing": 1,
+//	    "violations": [
+//	      {"name": "legacy-limits", "version": 4, "validation_errors": [{"field": "max_limit", "error": "Must be less than or equal to 1000", "type": "number_lte"}]}
+//	    ]
+//	  }
+//	}
+func (ch *ConfigHandler) SchemaImpact(c echo.Context) error {
+	var req models.SchemaImpactRequest
+	if err := c.Bind(&req); err != nil || len(req.Schema) == 0 {
+		details := map[string]string{}
+		if err != nil {
+			details["parse_error"] = err.Error()
+		}
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON with a schema object",
+				Details: details,
+			},
+		})
+	}
+
+	report, err := ch.configService.SchemaImpact(string(req.Schema))
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// SetDefaultConfig handles PUT /admin/defaults and PUT /admin/defaults/{name}
+//
+//	@Summary		Set a default configuration
+//	@Description	Stores the payload GET /api/v1/configs/{name}?default=true serves while the configuration does not exist. Without a name it sets the global default, used for names without a default of their own. The data must match the schema.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string	false	"Configuration name; omit for the global default"
+//	@Param			body	body		models.SetDefaultConfigRequest	true	"Default data"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		422		{object}	models.ErrorResponse
+//	@Router			/admin/defaults/{name} [put]
+//
+//	@Example request
+//	{
+//	  "data": {"max_limit": 100, "enabled": false}
+//	}
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Default configuration set successfully",
+//	  "data": {"name": "feature-toggle", "updated_at": "2025-09-07T12:00:00Z"}
+//	}
+func (ch *ConfigHandler) SetDefaultConfig(c echo.Context) error {
+	name, errDetail := ch.defaultConfigName(c)
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	var req models.SetDefaultConfigRequest
+	if err := c.Bind(&req); err != nil || len(req.Data) == 0 {
+		details := map[string]string{}
+		if err != nil {
+			details["parse_error"] = err.Error()
+		}
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON with a data object",
+				Details: details,
+			},
+		})
+	}
+
+	defaultConfig, err := ch.configService.SetDefaultConfig(name, string(req.Data))
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Default configuration set successfully",
+		Data:    defaultConfig,
+	})
+}
+
+// DeleteDefaultConfig handles DELETE /admin/defaults and DELETE /admin/defaults/{name}
+//
+//	@Summary		Remove a default configuration
+//	@Description	Removes the default stored for a name, or the global default when no name is given.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			name	path		string	false	"Configuration name; omit for the global default"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/admin/defaults/{name} [delete]
+func (ch *ConfigHandler) DeleteDefaultConfig(c echo.Context) error {
+	name, errDetail := ch.defaultConfigName(c)
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	if err := ch.configService.DeleteDefaultConfig(name); err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Default configuration removed successfully",
+	})
+}
+
+// defaultConfigName returns the configuration name a default route targets, or the global
+// default key when the route has no name
+func (ch *ConfigHandler) defaultConfigName(c echo.Context) (string, *models.ErrorDetail) {
+	name := c.Param("name")
+	if name == "" {
+		return storage.GlobalDefaultName, nil
+	}
+
+	if errDetail := ch.invalidConfigName(name); errDetail != nil {
+		return "", errDetail
+	}
+
+	return name, nil
+}
+
+// ListConfigBudgets handles GET /admin/budgets
+//
+//	@Summary		List configuration budgets
+//	@Description	Returns every per-configuration budget, ordered by name. Configurations without a budget are unrestricted.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse	"OK"
+//	@Router			/admin/budgets [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "budgets": [
+//	      {"name": "feature-toggle", "max_requests_per_minute": 600, "max_data_bytes": 4096, "updated_at": "2025-09-07T12:00:00Z"}
+//	    ]
+//	  }
+//	}
+func (ch *ConfigHandler) ListConfigBudgets(c echo.Context) error {
+	budgets, err := ch.configService.ListConfigBudgets()
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    budgets,
+	})
+}
+
+// SetConfigBudget handles PUT /admin/budgets/{name}
+//
+//	@Summary		Set a configuration budget
+//	@Description	Limits the requests per minute addressing a configuration and the size of data written to it. Requests over budget get 429 and oversized writes 413, both with CONFIG_BUDGET_EXCEEDED. A zero limit leaves that dimension unrestricted. The configuration need not exist yet.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string							true	"Configuration name"
+//	@Param			body	body		models.SetConfigBudgetRequest	true	"Budget limits"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Router			/admin/budgets/{name} [put]
+//
+//	@Example request
+//	{
+//	  "max_requests_per_minute": 600,
+//	  "max_data_bytes": 4096
+//	}
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration budget set successfully",
+//	  "data": {"name": "feature-toggle", "max_requests_per_minute": 600, "max_data_bytes": 4096, "updated_at": "2025-09-07T12:00:00Z"}
+//	}
+func (ch *ConfigHandler) SetConfigBudget(c echo.Context) error {
+	name := c.Param("name")
+	if errDetail := ch.invalidConfigName(name); errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	var req models.SetConfigBudgetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+	if req.MaxRequestsPerMinute < 0 || req.MaxDataBytes < 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_BUDGET",
+				Message: "max_requests_per_minute and max_data_bytes must be non-negative; 0 is unlimited",
+			},
+		})
+	}
+
+	budget, err := ch.configService.SetConfigBudget(name, req.MaxRequestsPerMinute, req.MaxDataBytes)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration budget set successfully",
+		Data:    budget,
+	})
+}
+
+// DeleteConfigBudget handles DELETE /admin/budgets/{name}
+//
+//	@Summary		Remove a configuration budget
+//	@Description	Removes the budget of a configuration, leaving it unrestricted.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/admin/budgets/{name} [delete]
+func (ch *ConfigHandler) DeleteConfigBudget(c echo.Context) error {
+	if err := ch.configService.DeleteConfigBudget(c.Param("name")); err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration budget removed successfully",
+	})
+}
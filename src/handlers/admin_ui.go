@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// adminUIPage is the single-page admin UI that talks to the JSON API
+//
+//go:embed ui/index.html
+var adminUIPage []byte
+
+// AdminUI handles GET /admin/ui
+//
+// AdminUI serves the embedded admin page for browsing configurations, viewing
+// version history, diffing versions, and creating or updating configurations.
+func AdminUI(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMETextHTMLCharsetUTF8, adminUIPage)
+}
@@ -1,8 +1,18 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"math"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"config-manager/src/models"
 	"config-manager/src/services"
@@ -11,22 +21,39 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// DefaultMaxNameLength is the configuration name length limit unless MAX_NAME_LENGTH overrides it
+const DefaultMaxNameLength = 100
+
 // ConfigHandler handles HTTP requests for configuration management
 type ConfigHandler struct {
-	configService *services.ConfigService
+	configService      *services.ConfigService
+	maxNameLength      int
+	autoCreateOnUpdate bool
 }
 
 // NewConfigHandler creates a new configuration handler
 func NewConfigHandler(configService *services.ConfigService) *ConfigHandler {
 	return &ConfigHandler{
 		configService: configService,
+		maxNameLength: DefaultMaxNameLength,
 	}
 }
 
+// SetMaxNameLength sets the maximum accepted configuration name length
+func (ch *ConfigHandler) SetMaxNameLength(maxLength int) {
+	ch.maxNameLength = maxLength
+}
+
+// SetAutoCreateOnUpdate makes every update create a configuration that does not exist yet,
+// as ?create_if_missing=true does for a single request
+func (ch *ConfigHandler) SetAutoCreateOnUpdate(enabled bool) {
+	ch.autoCreateOnUpdate = enabled
+}
+
 // CreateConfig handles POST /api/v1/configs
 //
 //	@Summary		Create a new configuration
-//	@Description	Validates and creates a new configuration with version 1. The request must include a name and JSON data matching the schema.
+//	@Description	Validates and creates a new configuration with version 1. The request must include a name and JSON data matching the schema. With schema_hash set, the create only happens if the active schema has that hash; otherwise 409 SCHEMA_MISMATCH reports the active hash.
 //	@Tags			configurations
 //	@Accept			json
 //	@Produce		json
@@ -81,22 +108,39 @@ func (ch *ConfigHandler) CreateConfig(c echo.Context) error {
 	}
 
 	// Validate configuration name pattern
-	if !isValidConfigName(req.Name) {
+	if errDetail := ch.invalidConfigName(req.Name); errDetail != nil {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Error: models.ErrorDetail{
-				Code:    "INVALID_CONFIG_NAME",
-				Message: "Configuration name contains invalid characters",
-				Details: map[string]string{
-					"provided_name":   req.Name,
-					"allowed_pattern": "^[a-zA-Z0-9_-]+$",
-				},
-			},
+			Error:   *errDetail,
 		})
 	}
 
-	// Create configuration
-	config, err := ch.configService.CreateConfig(req.Name, string(req.Data))
+	data, errDetail := decodeData(req.DataEncoding, req.Data)
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+	req.Data = data
+
+	// Refuse data built against a different schema than the active one
+	if req.SchemaHash != "" {
+		if err := ch.configService.VerifyActiveSchema(req.SchemaHash); err != nil {
+			return ch.handleError(c, err)
+		}
+	}
+
+	// Create configuration, from text when data is a string in some format
+	var config *models.Configuration
+	var err error
+	if text, ok := configText(req.Data); ok {
+		config, err = ch.configService.CreateConfigFromText(req.Name, req.Format, text, actorFromRequest(c))
+	} else if req.Format != "" && req.Format != services.FormatJSON {
+		return invalidFormatData(c, req.Format)
+	} else {
+		config, err = ch.configService.CreateConfig(req.Name, string(req.Data), actorFromRequest(c))
+	}
 	if err != nil {
 		return ch.handleError(c, err)
 	}
@@ -115,15 +159,20 @@ func (ch *ConfigHandler) CreateConfig(c echo.Context) error {
 // UpdateConfig handles PUT /api/v1/configs/{name}
 //
 //	@Summary		Update an existing configuration
-//	@Description	Updates the configuration data and increments the version number.
+//	@Description	Updates the configuration data and increments the version number. With create_if_missing=true (or the AUTO_CREATE_ON_UPDATE policy) a configuration that does not exist is created at version 1 and 201 is returned instead of 404. With expected_version in the body the update only applies while that is the current version, and fails with 409 VERSION_CONFLICT otherwise.
 //	@Tags			configurations
 //	@Accept			json
 //	@Produce		json
-//	@Param			name	path		string	true	"Configuration name"
-//	@Param			body	body		models.UpdateConfigRequest	true	"Updated configuration data"
+//	@Param			name				path		string	true	"Configuration name"
+//	@Param			create_if_missing	query		bool	false	"Create the configuration when it does not exist"
+//	@Param			draft				query		bool	false	"Store the update as a draft that takes effect once published"
+//	@Param			body				body		models.UpdateConfigRequest	true	"Updated configuration data"
 //	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Success		201		{object}	models.SuccessResponse	"Created"
+//	@Success		202		{object}	models.SuccessResponse	"Draft created"
 //	@Failure		400		{object}	models.ErrorResponse
 //	@Failure		404		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse
 //	@Failure		422		{object}	models.ErrorResponse
 //	@Router			/api/v1/configs/{name} [put]
 //
@@ -157,12 +206,81 @@ func (ch *ConfigHandler) UpdateConfig(c echo.Context) error {
 		})
 	}
 
-	// Update configuration
-	config, err := ch.configService.UpdateConfig(name, string(req.Data))
+	data, errDetail := decodeData(req.DataEncoding, req.Data)
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+	req.Data = data
+
+	var expectedVersion int
+	if req.ExpectedVersion != nil {
+		if *req.ExpectedVersion < 1 {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    "INVALID_VERSION_NUMBER",
+					Message: "expected_version must be a positive integer",
+					Details: map[string]int{"expected_version": *req.ExpectedVersion},
+				},
+			})
+		}
+		expectedVersion = *req.ExpectedVersion
+	}
+
+	if c.QueryParam("draft") == "true" {
+		return ch.updateAsDraft(c, name, req, expectedVersion)
+	}
+
+	// Only names valid for a create may be created here; existing configurations with older
+	// names are still updated
+	createIfMissing := ch.autoCreateOnUpdate || c.QueryParam("create_if_missing") == "true"
+	nameErr := ch.invalidConfigName(name)
+	opts := services.UpdateOptions{
+		CreateIfMissing: createIfMissing && nameErr == nil,
+		ExpectedVersion: expectedVersion,
+		Actor:           actorFromRequest(c),
+	}
+
+	// Update configuration, from text when data is a string in some format
+	var config *models.Configuration
+	var created bool
+	var err error
+	text, isText := configText(req.Data)
+	switch {
+	case !isText && req.Format != "" && req.Format != services.FormatJSON:
+		return invalidFormatData(c, req.Format)
+	case isText:
+		config, created, err = ch.configService.UpdateConfigFromTextWithOptions(name, req.Format, text, opts)
+	default:
+		config, created, err = ch.configService.UpdateConfigWithOptions(name, string(req.Data), opts)
+	}
 	if err != nil {
+		if createIfMissing && nameErr != nil && isConfigNotFoundError(err) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   *nameErr,
+			})
+		}
 		return ch.handleError(c, err)
 	}
 
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	if created {
+		return c.JSON(http.StatusCreated, models.SuccessResponse{
+			Success: true,
+			Message: "Configuration created successfully",
+			Data: models.ConfigurationCreated{
+				Name:      config.Name,
+				Version:   config.CurrentVersion,
+				CreatedAt: config.CreatedAt,
+			},
+		})
+	}
+
 	return c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
 		Message: "Configuration updated successfully",
@@ -174,6 +292,111 @@ func (ch *ConfigHandler) UpdateConfig(c echo.Context) error {
 	})
 }
 
+// updateAsDraft stores an update as a draft and responds 202: the change is accepted but only
+// takes effect once the draft is published
+func (ch *ConfigHandler) updateAsDraft(c echo.Context, name string, req models.UpdateConfigRequest, expectedVersion int) error {
+	var draft *models.DraftCreated
+	var err error
+	if text, ok := configText(req.Data); ok {
+		draft, err = ch.configService.UpdateConfigAsDraftFromText(name, req.Format, text, expectedVersion, actorFromRequest(c))
+	} else if req.Format != "" && req.Format != services.FormatJSON {
+		return invalidFormatData(c, req.Format)
+	} else {
+		draft, err = ch.configService.UpdateConfigAsDraft(name, string(req.Data), expectedVersion, actorFromRequest(c))
+	}
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	return c.JSON(http.StatusAccepted, models.SuccessResponse{
+		Success: true,
+		Message: "Draft created; publish it to make it active",
+		Data:    draft,
+	})
+}
+
+// PatchConfig handles PATCH /api/v1/configs/{name}
+//
+//	@Summary		Partially update a configuration
+//	@Description	Applies a patch to the data of the current version and stores the result as a new version, validated against the schema like an update. Send a JSON Patch (RFC 6902) with Content-Type application/json-patch+json, or a JSON Merge Patch (RFC 7386) with Content-Type application/merge-patch+json. A JSON Patch that does not apply to the current data, e.g. removing a missing member or failing a test operation, is rejected with 409 PATCH_CONFLICT.
+//	@Tags			configurations
+//	@Accept			application/json-patch+json
+//	@Accept			application/merge-patch+json
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			body	body		object	true	"JSON Patch operations or merge patch"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse
+//	@Failure		415		{object}	models.ErrorResponse
+//	@Failure		422		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name} [patch]
+//
+//	@Example request
+//	[
+//	  {"op": "replace", "path": "/max_limit", "value": 500}
+//	]
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration patched successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "version": 3,
+//	    "updated_at": "2025-09-07T12:10:00Z"
+//	  }
+//	}
+func (ch *ConfigHandler) PatchConfig(c echo.Context) error {
+	name := c.Param("name")
+
+	patchType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if patchType != services.PatchTypeJSONPatch && patchType != services.PatchTypeMergePatch {
+		return c.JSON(http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "UNSUPPORTED_PATCH_TYPE",
+				Message: "Content-Type must be application/json-patch+json or application/merge-patch+json",
+				Details: map[string]interface{}{
+					"content_type": c.Request().Header.Get(echo.HeaderContentType),
+					"supported":    []string{services.PatchTypeJSONPatch, services.PatchTypeMergePatch},
+				},
+			},
+		})
+	}
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body could not be read",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+
+	config, err := ch.configService.PatchConfig(name, patchType, patch, actorFromRequest(c))
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration patched successfully",
+		Data: models.ConfigurationUpdated{
+			Name:      config.Name,
+			Version:   config.CurrentVersion,
+			UpdatedAt: config.UpdatedAt,
+		},
+	})
+}
+
 // RollbackConfig handles POST /api/v1/configs/{name}/rollback
 //
 //	@Summary		Rollback configuration to a previous version
@@ -186,6 +409,7 @@ func (ch *ConfigHandler) UpdateConfig(c echo.Context) error {
 //	@Success		200		{object}	models.SuccessResponse	"OK"
 //	@Failure		400		{object}	models.ErrorResponse
 //	@Failure		404		{object}	models.ErrorResponse
+//	@Failure		410		{object}	models.ErrorResponse
 //	@Router			/api/v1/configs/{name}/rollback [post]
 //
 //	@Example request
@@ -206,16 +430,11 @@ func (ch *ConfigHandler) UpdateConfig(c echo.Context) error {
 func (ch *ConfigHandler) RollbackConfig(c echo.Context) error {
 	name := c.Param("name")
 
-	var req models.RollbackConfigRequest
-
-	if err := c.Bind(&req); err != nil {
+	req, errDetail := decodeRollbackRequest(c)
+	if errDetail != nil {
 		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
-			Error: models.ErrorDetail{
-				Code:    "INVALID_REQUEST_FORMAT",
-				Message: "Request body must be valid JSON",
-				Details: map[string]string{"parse_error": err.Error()},
-			},
+			Error:   *errDetail,
 		})
 	}
 
@@ -234,11 +453,13 @@ func (ch *ConfigHandler) RollbackConfig(c echo.Context) error {
 	}
 
 	// Rollback configuration
-	config, err := ch.configService.RollbackConfig(name, req.TargetVersion)
+	config, err := ch.configService.RollbackConfig(name, req.TargetVersion, actorFromRequest(c))
 	if err != nil {
 		return ch.handleError(c, err)
 	}
 
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
 	return c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
 		Message: "Configuration rolled back successfully",
@@ -251,172 +472,2354 @@ func (ch *ConfigHandler) RollbackConfig(c echo.Context) error {
 	})
 }
 
-// GetLatestConfig handles GET /api/v1/configs/{name}
+// UndoConfig handles POST /api/v1/configs/{name}/undo
 //
-//	@Summary		Get the latest version of a configuration
-//	@Description	Returns the latest configuration data for the given name.
+//	@Summary		Undo the last change to a configuration
+//	@Description	Rolls the configuration back to the version immediately before the current one, creating a new version.
 //	@Tags			configurations
 //	@Produce		json
 //	@Param			name	path		string	true	"Configuration name"
 //	@Success		200		{object}	models.SuccessResponse	"OK"
 //	@Failure		404		{object}	models.ErrorResponse
-//	@Router			/api/v1/configs/{name} [get]
+//	@Failure		409		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/undo [post]
 //
 //	@Example response 200
 //	{
 //	  "success": true,
+//	  "message": "Configuration change undone successfully",
 //	  "data": {
 //	    "name": "feature-toggle",
-//	    "version": 3,
-//	    "data": {"max_limit": 200, "enabled": false},
-//	    "updated_at": "2025-09-07T12:10:00Z"
+//	    "new_version": 4,
+//	    "target_version": 2,
+//	    "rolled_back_at": "2025-09-07T12:15:00Z"
 //	  }
 //	}
-func (ch *ConfigHandler) GetLatestConfig(c echo.Context) error {
+func (ch *ConfigHandler) UndoConfig(c echo.Context) error {
 	name := c.Param("name")
 
-	configData, err := ch.configService.GetLatestConfig(name)
+	config, targetVersion, err := ch.configService.UndoConfig(name, actorFromRequest(c))
 	if err != nil {
 		return ch.handleError(c, err)
 	}
 
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
 	return c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Data:    configData,
+		Message: "Configuration change undone successfully",
+		Data: models.ConfigurationRollback{
+			Name:          config.Name,
+			NewVersion:    config.CurrentVersion,
+			TargetVersion: targetVersion,
+			RolledBackAt:  config.UpdatedAt,
+		},
 	})
 }
 
-// GetConfigVersion handles GET /api/v1/configs/{name}/versions/{version}
+// RedoConfig handles POST /api/v1/configs/{name}/redo
 //
-//	@Summary		Get a specific version of a configuration
-//	@Description	Returns the configuration data for the specified version.
+//	@Summary		Redo the latest rollback of a configuration
+//	@Description	Restores the version that was current before the latest rollback or undo, creating a new version. Only valid until the configuration is edited again.
 //	@Tags			configurations
 //	@Produce		json
 //	@Param			name	path		string	true	"Configuration name"
-//	@Param			version	path		int		true	"Version number"
 //	@Success		200		{object}	models.SuccessResponse	"OK"
-//	@Failure		400		{object}	models.ErrorResponse
 //	@Failure		404		{object}	models.ErrorResponse
-//	@Router			/api/v1/configs/{name}/versions/{version} [get]
+//	@Failure		409		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/redo [post]
 //
 //	@Example response 200
 //	{
 //	  "success": true,
+//	  "message": "Configuration rollback redone successfully",
 //	  "data": {
 //	    "name": "feature-toggle",
-//	    "version": 1,
-//	    "data": {"max_limit": 100, "enabled": true},
-//	    "created_at": "2025-09-07T12:00:00Z"
+//	    "new_version": 5,
+//	    "target_version": 3,
+//	    "rolled_back_at": "2025-09-07T12:20:00Z"
 //	  }
 //	}
-func (ch *ConfigHandler) GetConfigVersion(c echo.Context) error {
+func (ch *ConfigHandler) RedoConfig(c echo.Context) error {
 	name := c.Param("name")
-	versionStr := c.Param("version")
-
-	version, err := strconv.Atoi(versionStr)
-	if err != nil || version < 1 {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Success: false,
-			Error: models.ErrorDetail{
-				Code:    "INVALID_VERSION_NUMBER",
-				Message: "Version number must be positive integer",
-			},
-		})
-	}
 
-	configData, err := ch.configService.GetConfigVersion(name, version)
+	config, targetVersion, err := ch.configService.RedoConfig(name, actorFromRequest(c))
 	if err != nil {
 		return ch.handleError(c, err)
 	}
 
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
 	return c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Data:    configData,
+		Message: "Configuration rollback redone successfully",
+		Data: models.ConfigurationRollback{
+			Name:          config.Name,
+			NewVersion:    config.CurrentVersion,
+			TargetVersion: targetVersion,
+			RolledBackAt:  config.UpdatedAt,
+		},
 	})
 }
 
-// ListVersions handles GET /api/v1/configs/{name}/versions
+// GetLatestConfig handles GET /api/v1/configs/{name}
 //
-//	@Summary		List all versions of a configuration
-//	@Description	Returns a list of all version numbers and their creation timestamps for the specified configuration name.
+//	@Summary		Get the latest version of a configuration
+//	@Description	Returns the latest configuration data for the given name. With apply_defaults=true, the active schema's default values fill in missing optional keys; this is a read-time overlay and does not change what is stored. Responses carry an ETag of the version and data served, which changes on every update or rollback; a matching If-None-Match returns 304.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name			path		string	true	"Configuration name"
+//	@Param			If-None-Match	header		string	false	"ETag from a previous response"
+//	@Param			apply_defaults	query		bool	false	"Merge schema defaults into missing keys"
+//	@Param			default			query		bool	false	"Serve the stored default (version 0) if the configuration does not exist"
+//	@Param			include_drafts	query		bool	false	"Serve the newest unpublished draft when one is pending"
+//	@Param			format			query		string	false	"json (default), original (the text as authored), flat (dot-notation map) or env (export lines)"
+//	@Success		200				{object}	models.SuccessResponse	"OK"
+//	@Success		304				"Latest version unchanged since the given ETag"
+//	@Header			200				{string}	ETag	"Validator of the version served"
+//	@Header			200				{string}	X-Config-Default	"name or global when a stored default was served"
+//	@Header			200				{string}	X-Config-Checksum	"SHA-256 of the canonical data"
+//	@Failure		404				{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name} [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "version": 3,
+//	    "data": {"max_limit": 200, "enabled": false},
+//	    "updated_at": "2025-09-07T12:10:00Z"
+//	  }
+//	}
+func (ch *ConfigHandler) GetLatestConfig(c echo.Context) error {
+	name := c.Param("name")
+
+	var configData *models.ConfigurationData
+	var err error
+	applyDefaults := c.QueryParam("apply_defaults") == "true"
+	if c.QueryParam("include_drafts") == "true" {
+		configData, err = ch.configService.GetLatestConfigWithDrafts(name, applyDefaults)
+	} else if applyDefaults {
+		configData, err = ch.configService.GetLatestConfigWithDefaults(name)
+	} else {
+		configData, err = ch.configService.GetLatestConfig(name)
+	}
+	if err != nil && isConfigNotFoundError(err) && c.QueryParam("default") == "true" {
+		return ch.respondWithDefault(c, name, err)
+	}
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionRead)
+
+	// The ETag names the version served, so it changes with every update or rollback
+	if setVersionETag(c, configData) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return respondWithFormat(c, configData)
+}
+
+// EvaluateConfig handles GET /api/v1/configs/{name}/evaluate
+//
+//	@Summary		Evaluate a configuration as a feature flag
+//	@Description	Returns the latest enabled and max_limit values in a flat body without the standard envelope, for flag consumers. For a gradual rollout (rollout_percent), enabled is true only when the key falls in the rollout's hash buckets; without a key only a full rollout is enabled.
 //	@Tags			configurations
 //	@Produce		json
 //	@Param			name	path		string	true	"Configuration name"
-//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Param			key		query		string	false	"Stable identifier of the subject, such as a user id"
+//	@Success		200		{object}	models.FlagEvaluation	"OK"
 //	@Failure		404		{object}	models.ErrorResponse
-//	@Router			/api/v1/configs/{name}/versions [get]
+//	@Router			/api/v1/configs/{name}/evaluate [get]
 //
 //	@Example response 200
 //	{
-//	  "success": true,
-//	  "data": [
-//	    {"version": 1, "created_at": "2025-09-07T12:00:00Z"},
-//	    {"version": 2, "created_at": "2025-09-07T12:05:00Z"},
-//	    {"version": 3, "created_at": "2025-09-07T12:10:00Z"}
-//	  ]
+//	  "name": "feature-toggle",
+//	  "version": 3,
+//	  "enabled": true,
+//	  "max_limit": 1000
 //	}
-func (ch *ConfigHandler) ListVersions(c echo.Context) error {
+func (ch *ConfigHandler) EvaluateConfig(c echo.Context) error {
 	name := c.Param("name")
 
-	versionList, err := ch.configService.ListVersions(name)
+	evaluation, err := ch.configService.EvaluateConfig(name, models.EvaluationContext{Key: c.QueryParam("key")})
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionRead)
+
+	return c.JSON(http.StatusOK, evaluation)
+}
+
+// GetConfigMeta handles GET /api/v1/configs/{name}/meta
+//
+//	@Summary		Get configuration metadata
+//	@Description	Returns the name, current version, timestamps and flags of a configuration without its data. Cheaper than fetching the latest version, since no version row is read.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/meta [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "current_version": 3,
+//	    "created_at": "2025-09-07T12:00:00Z",
+//	    "updated_at": "2025-09-07T12:10:00Z",
+//	    "sensitive": false
+//	  }
+//	}
+func (ch *ConfigHandler) GetConfigMeta(c echo.Context) error {
+	meta, err := ch.configService.GetConfigMeta(c.Param("name"))
 	if err != nil {
 		return ch.handleError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
-		Data:    versionList,
+		Data:    meta,
 	})
 }
 
-// handleError converts service errors to appropriate HTTP responses
-func (ch *ConfigHandler) handleError(c echo.Context, err error) error {
-	switch {
-	case isConfigAlreadyExistsError(err):
-		return c.JSON(http.StatusConflict, models.ErrorResponse{
-			Success: false,
-			Error: models.ErrorDetail{
-				Code:    "CONFIG_ALREADY_EXISTS",
-				Message: err.Error(),
-			},
-		})
-	case isConfigNotFoundError(err):
-		return c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Success: false,
-			Error: models.ErrorDetail{
-				Code:    "CONFIG_NOT_FOUND",
-				Message: err.Error(),
-			},
-		})
-	case isVersionNotFoundError(err):
-		return c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Success: false,
-			Error: models.ErrorDetail{
-				Code:    "VERSION_NOT_FOUND",
-				Message: err.Error(),
-			},
-		})
-	case services.IsSchemaValidationError(err):
-		schemaErr := err.(*services.SchemaValidationError)
-		return c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{
+// CompareConfigs handles GET /api/v1/diff
+//
+//	@Summary		Compare two configurations
+//	@Description	Returns a structured diff of the data of two configurations, for example the same logical configuration stored per environment. Each side is its latest version unless a_version or b_version is given. Keys are dot-notation paths; added keys exist only in b, removed keys only in a, and changed keys carry the old (a) and new (b) values.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			a			query		string	true	"Name of the first configuration"
+//	@Param			b			query		string	true	"Name of the second configuration"
+//	@Param			a_version	query		int		false	"Version of a (default latest)"
+//	@Param			b_version	query		int		false	"Version of b (default latest)"
+//	@Success		200			{object}	models.SuccessResponse	"OK"
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse
+//	@Router			/api/v1/diff [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "a": {"name": "checkout-staging", "version": 7},
+//	    "b": {"name": "checkout-prod", "version": 4},
+//	    "identical": false,
+//	    "added": {},
+//	    "removed": {"rollout_percent": 50},
+//	    "changed": {"max_limit": {"old": 100, "new": 500}}
+//	  }
+//	}
+func (ch *ConfigHandler) CompareConfigs(c echo.Context) error {
+	nameA, nameB := c.QueryParam("a"), c.QueryParam("b")
+	if nameA == "" || nameB == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.ErrorDetail{
-				Code:    "SCHEMA_VALIDATION_FAILED",
-				Message: schemaErr.Message,
-				Details: map[string][]services.ValidationError{
-					"validation_errors": schemaErr.Errors,
+				Code:    "MISSING_REQUIRED_FIELD",
+				Message: "Missing required query parameters: a and b",
+				Details: map[string][]string{
+					"required_fields": {"a", "b"},
 				},
 			},
 		})
-	default:
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Error: models.ErrorDetail{
-				Code:    "INTERNAL_SERVER_ERROR",
-				Message: "An unexpected error occurred",
-			},
+	}
+
+	var versions [2]int
+	for i, param := range []string{"a_version", "b_version"} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		version, errDetail := parseVersionParam(value)
+		if errDetail != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   *errDetail,
+			})
+		}
+		versions[i] = version
+	}
+
+	comparison, err := ch.configService.CompareConfigs(nameA, versions[0], nameB, versions[1])
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(nameA, actorFromRequest(c), models.AccessActionRead)
+	ch.configService.LogAccess(nameB, actorFromRequest(c), models.AccessActionRead)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    comparison,
+	})
+}
+
+// DiffVersions handles GET /api/v1/configs/{name}/diff
+//
+//	@Summary		Diff two versions of a configuration
+//	@Description	Returns a structured diff of the data of two versions, for example to see what a rollback changed. Keys are dot-notation paths, so nested changes are reported at the leaf; added keys exist only in to, removed keys only in from, and changed keys carry the old (from) and new (to) values. With format=unified the diff is instead a text/plain unified diff of both versions' data pretty-printed with sorted keys, empty when they are identical.
+//	@Tags			configurations
+//	@Produce		json
+//	@Produce		plain
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			from	query		int		true	"Version to diff from"
+//	@Param			to		query		int		true	"Version to diff to"
+//	@Param			format	query		string	false	"Response format"	Enums(json, unified)
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/diff [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "from": 1,
+//	    "to": 3,
+//	    "identical": false,
+//	    "added": {"rollout_percent": 25},
+//	    "removed": {},
+//	    "changed": {"max_limit": {"old": 100, "new": 500}}
+//	  }
+//	}
+func (ch *ConfigHandler) DiffVersions(c echo.Context) error {
+	name := c.Param("name")
+
+	fromParam, toParam := c.QueryParam("from"), c.QueryParam("to")
+	if fromParam == "" || toParam == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "MISSING_REQUIRED_FIELD",
+				Message: "Missing required query parameters: from and to",
+				Details: map[string][]string{
+					"required_fields": {"from", "to"},
+				},
+			},
+		})
+	}
+
+	var versions [2]int
+	for i, value := range []string{fromParam, toParam} {
+		version, errDetail := parseVersionParam(value)
+		if errDetail != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   *errDetail,
+			})
+		}
+		versions[i] = version
+	}
+
+	switch format := c.QueryParam("format"); format {
+	case "", services.FormatJSON:
+		diff, err := ch.configService.DiffVersions(name, versions[0], versions[1])
+		if err != nil {
+			return ch.handleError(c, err)
+		}
+		ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionRead)
+
+		return c.JSON(http.StatusOK, models.SuccessResponse{
+			Success: true,
+			Data:    diff,
+		})
+	case "unified":
+		diff, err := ch.configService.UnifiedDiffVersions(name, versions[0], versions[1])
+		if err != nil {
+			return ch.handleError(c, err)
+		}
+		ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionRead)
+
+		return c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, []byte(diff))
+	default:
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_RESPONSE_FORMAT",
+				Message: "format must be json or unified",
+				Details: map[string]string{"format": format},
+			},
+		})
+	}
+}
+
+// GetConfigStorage handles GET /api/v1/configs/{name}/storage
+//
+//	@Summary		Get a configuration's storage footprint
+//	@Description	Returns the total bytes stored across every version of a configuration, with the version count and average bytes per version, for chargeback and finding storage-heavy configurations.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/storage [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "version_count": 3,
+//	    "total_bytes": 96,
+//	    "average_bytes_per_version": 32
+//	  }
+//	}
+func (ch *ConfigHandler) GetConfigStorage(c echo.Context) error {
+	footprint, err := ch.configService.GetStorageFootprint(c.Param("name"))
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    footprint,
+	})
+}
+
+// ValidateConfig handles POST /api/v1/configs/{name}/validate
+//
+//	@Summary		Validate data against a configuration's schema
+//	@Description	Validates candidate data against the schema the configuration uses and reports whether it is valid, with field errors localized per Accept-Language. Runs every check a new version goes through, including the agreement of status and enabled. Nothing is written and no version is created; invalid data still returns 200, while data over the configuration's size budget is refused with 413 as a write would be.
+//	@Tags			configurations
+//	@Accept			json
+//	@Produce		json
+//	@Param			name			path		string							true	"Configuration name"
+//	@Param			body			body		models.ValidateConfigRequest	true	"Candidate data"
+//	@Param			Accept-Language	header		string							false	"Language for validation messages"
+//	@Success		200				{object}	models.SuccessResponse	"OK"
+//	@Failure		400				{object}	models.ErrorResponse
+//	@Failure		404				{object}	models.ErrorResponse
+//	@Failure		413				{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/validate [post]
+//
+//	@Example request
+//	{
+//	  "data": {"max_limit": "lots", "enabled": true}
+//	}
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "schema_hash": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+//	    "valid": false,
+//	    "validation_errors": [
+//	      {"field": "max_limit", "error": "Invalid type. Expected: integer, given: string", "type": "invalid_type"}
+//	    ]
+//	  }
+//	}
+func (ch *ConfigHandler) ValidateConfig(c echo.Context) error {
+	name := c.Param("name")
+
+	var req models.ValidateConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+
+	if len(req.Data) == 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "MISSING_REQUIRED_FIELD",
+				Message: "Missing required field: data",
+				Details: map[string][]string{
+					"required_fields": {"data"},
+				},
+			},
+		})
+	}
+
+	hash, err := ch.configService.ValidateForConfig(name, string(req.Data))
+	result := models.ValidationResult{
+		Name:             name,
+		SchemaHash:       hash,
+		Valid:            err == nil,
+		ValidationErrors: []models.SchemaViolationError{},
+	}
+	if err != nil {
+		validationErr, ok := err.(*services.SchemaValidationError)
+		if !ok {
+			return ch.handleError(c, err)
+		}
+		result.UnknownFields = validationErr.UnknownFields()
+		localized := services.LocalizeSchemaValidationError(validationErr, c.Request().Header.Get("Accept-Language"))
+		for _, fieldErr := range localized.Errors {
+			result.ValidationErrors = append(result.ValidationErrors, models.SchemaViolationError{
+				Field: fieldErr.Field,
+				Error: fieldErr.Error,
+				Type:  fieldErr.Type,
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// respondWithDefault serves the stored default for a configuration that does not exist, marked
+// by the X-Config-Default header; without an applicable default the original not-found error stands
+func (ch *ConfigHandler) respondWithDefault(c echo.Context, name string, notFound error) error {
+	configData, fromGlobal, err := ch.configService.GetDefaultConfig(name)
+	if err != nil {
+		if isDefaultConfigNotFoundError(err) {
+			return ch.handleError(c, notFound)
+		}
+		return ch.handleError(c, err)
+	}
+
+	scope := "name"
+	if fromGlobal {
+		scope = "global"
+	}
+	c.Response().Header().Set("X-Config-Default", scope)
+	return respondWithFormat(c, configData)
+}
+
+// GetConfigVersion handles GET /api/v1/configs/{name}/versions/{version}
+//
+//	@Summary		Get a specific version of a configuration
+//	@Description	Returns the configuration data for the specified version. Responses carry a strong ETag built from the data checksum and are revalidated (Cache-Control: no-cache), since squashing renumbers versions; a matching If-None-Match returns 304.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name			path		string	true	"Configuration name"
+//	@Param			version			path		int		true	"Version number"
+//	@Param			If-None-Match	header		string	false	"ETag from a previous response"
+//	@Param			format			query		string	false	"json (default), original (the text as authored), flat (dot-notation map) or env (export lines)"
+//	@Param			include_drafts	query		bool	false	"Serve the version even if it is an unpublished draft"
+//	@Success		200				{object}	models.SuccessResponse	"OK"
+//	@Success		304				"Version unchanged since the given ETag"
+//	@Header			200				{string}	ETag	"Strong validator of the version"
+//	@Header			200				{string}	X-Config-Checksum	"SHA-256 of the canonical data"
+//	@Failure		400				{object}	models.ErrorResponse
+//	@Failure		404				{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/versions/{version} [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "version": 1,
+//	    "data": {"max_limit": 100, "enabled": true},
+//	    "created_at": "2025-09-07T12:00:00Z"
+//	  }
+//	}
+func (ch *ConfigHandler) GetConfigVersion(c echo.Context) error {
+	name := c.Param("name")
+
+	version, errDetail := parseVersionParam(c.Param("version"))
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	var configData *models.ConfigurationData
+	var err error
+	if c.QueryParam("include_drafts") == "true" {
+		configData, err = ch.configService.GetConfigVersionWithDrafts(name, version)
+	} else {
+		configData, err = ch.configService.GetConfigVersion(name, version)
+	}
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionRead)
+
+	// A version never changes once written, so it can be cached forever
+	if setVersionETag(c, configData) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return respondWithFormat(c, configData)
+}
+
+// GetVersionSchema handles GET /api/v1/configs/{name}/versions/{version}/schema
+//
+//	@Summary		Get the schema a version was created under
+//	@Description	Returns the schema that was active when the specified version was created, identified by its hash.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			version	path		int		true	"Version number"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/versions/{version}/schema [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "version": 1,
+//	    "schema_hash": "3b7f0c...",
+//	    "schema": {"type": "object", "properties": {"max_limit": {"type": "integer", "minimum": 0}}}
+//	  }
+//	}
+func (ch *ConfigHandler) GetVersionSchema(c echo.Context) error {
+	name := c.Param("name")
+
+	version, errDetail := parseVersionParam(c.Param("version"))
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	versionSchema, err := ch.configService.GetVersionSchema(name, version)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    versionSchema,
+	})
+}
+
+// ListVersions handles GET /api/v1/configs/{name}/versions
+//
+//	@Summary		List all versions of a configuration
+//	@Description	Returns a list of all version numbers and their creation timestamps for the specified configuration name.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name			path		string	true	"Configuration name"
+//	@Param			include_drafts	query		bool	false	"Include unpublished drafts"
+//	@Param			include_data	query		bool	false	"Include each version's data"
+//	@Param			include_age		query		bool	false	"Include each version's age by the server's clock"
+//	@Param			missing_ok		query		bool	false	"Return an empty list instead of 404 when the configuration does not exist"
+//	@Param			limit			query		int		false	"Maximum number of versions to return (default all)"
+//	@Param			offset			query		int		false	"Number of versions to skip"
+//	@Success		200				{object}	models.SuccessResponse	"OK"
+//	@Failure		400				{object}	models.ErrorResponse
+//	@Failure		404				{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/versions [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": [
+//	    {"version": 1, "created_at": "2025-09-07T12:00:00Z"},
+//	    {"version": 2, "created_at": "2025-09-07T12:05:00Z"},
+//	    {"version": 3, "created_at": "2025-09-07T12:10:00Z"}
+//	  ]
+//	}
+func (ch *ConfigHandler) ListVersions(c echo.Context) error {
+	name := c.Param("name")
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return invalidPagination(c, err)
+	}
+
+	includeData := c.QueryParam("include_data") == "true"
+	versionList, err := ch.configService.ListVersions(name, services.ListVersionsOptions{
+		IncludeDrafts: c.QueryParam("include_drafts") == "true",
+		IncludeData:   includeData,
+		IncludeAge:    c.QueryParam("include_age") == "true",
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil && isConfigNotFoundError(err) && c.QueryParam("missing_ok") == "true" {
+		versionList = &models.VersionList{
+			Name:       name,
+			Versions:   []models.VersionInfo{},
+			Pagination: &models.Pagination{Total: 0, Limit: limit, Offset: offset},
+		}
+		err = nil
+	}
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+	setPaginationLinks(c, versionList.Pagination)
+
+	// Listing with data reads every version, so it is audited like reading one
+	if includeData && len(versionList.Versions) > 0 {
+		ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionRead)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    versionList,
+	})
+}
+
+// ListDuplicateVersions handles GET /api/v1/configs/{name}/duplicates
+//
+//	@Summary		List versions with identical data
+//	@Description	Groups the versions of a configuration by data checksum and returns the clusters of versions sharing identical content.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/duplicates [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "clusters": [
+//	      {"checksum": "5d41402abc4b2a76b9719d911017c592...", "versions": [1, 3, 4]}
+//	    ]
+//	  }
+//	}
+func (ch *ConfigHandler) ListDuplicateVersions(c echo.Context) error {
+	name := c.Param("name")
+
+	duplicates, err := ch.configService.FindDuplicateVersions(name)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    duplicates,
+	})
+}
+
+// ProtectVersion handles POST /api/v1/configs/{name}/versions/{version}/protect
+//
+//	@Summary		Protect a version
+//	@Description	Marks a version as protected, for example a compliance-certified release. Squashing and bulk deletes refuse with 409 VERSION_PROTECTED rather than remove or renumber a protected version. Protection is permanent; protecting a protected version again succeeds.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			version	path		int		true	"Version number"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/versions/{version}/protect [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Version protected successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "version": 3,
+//	    "protected": true
+//	  }
+//	}
+func (ch *ConfigHandler) ProtectVersion(c echo.Context) error {
+	name := c.Param("name")
+
+	version, errDetail := parseVersionParam(c.Param("version"))
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	protection, err := ch.configService.ProtectVersion(name, version)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Version protected successfully",
+		Data:    protection,
+	})
+}
+
+// PublishVersion handles POST /api/v1/configs/{name}/versions/{version}/publish
+//
+//	@Summary		Publish a draft version
+//	@Description	Approves a draft created with PUT ?draft=true, making it the current version served as the latest. Publishing a version that is not a draft fails with 409 VERSION_NOT_DRAFT, and a draft older than the current version with 409 DRAFT_SUPERSEDED.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			version	path		int		true	"Version number"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/versions/{version}/publish [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Version published successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "version": 4,
+//	    "previous_version": 3,
+//	    "published_at": "2025-09-07T12:15:00Z"
+//	  }
+//	}
+func (ch *ConfigHandler) PublishVersion(c echo.Context) error {
+	name := c.Param("name")
+
+	version, errDetail := parseVersionParam(c.Param("version"))
+	if errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	published, err := ch.configService.PublishVersion(name, version)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Version published successfully",
+		Data:    published,
+	})
+}
+
+// SetSensitive handles PUT /api/v1/configs/{name}/sensitive
+//
+//	@Summary		Flag a configuration as sensitive
+//	@Description	Marks or unmarks a configuration as sensitive. Reads and writes of sensitive configurations are recorded in the access log when access logging is enabled.
+//	@Tags			configurations
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			body	body		models.SetSensitiveRequest	true	"Sensitive flag"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/sensitive [put]
+//
+//	@Example request
+//	{
+//	  "sensitive": true
+//	}
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration sensitivity updated successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "sensitive": true
+//	  }
+//	}
+func (ch *ConfigHandler) SetSensitive(c echo.Context) error {
+	name := c.Param("name")
+
+	var req models.SetSensitiveRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+
+	sensitivity, err := ch.configService.SetSensitive(name, req.Sensitive)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration sensitivity updated successfully",
+		Data:    sensitivity,
+	})
+}
+
+// SetConfigTags handles PUT /api/v1/configs/{name}/tags
+//
+//	@Summary		Tag a configuration
+//	@Description	Sets key/value tags on a configuration for grouping and filtering (see the tag filter of GET /api/v1/configs). Keys it already has get the new value; its other tags are kept. Returns all of the configuration's tags.
+//	@Tags			configurations
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			body	body		models.SetTagsRequest	true	"Tags to set"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/tags [put]
+//
+//	@Example request
+//	{
+//	  "tags": {"team": "payments", "env": "prod"}
+//	}
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration tags updated successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "tags": {"env": "prod", "owner": "alice", "team": "payments"}
+//	  }
+//	}
+func (ch *ConfigHandler) SetConfigTags(c echo.Context) error {
+	name := c.Param("name")
+
+	var req models.SetTagsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+	if len(req.Tags) == 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "MISSING_REQUIRED_FIELD",
+				Message: "Missing required field: tags",
+				Details: map[string][]string{
+					"required_fields": {"tags"},
+				},
+			},
+		})
+	}
+
+	tags, err := ch.configService.SetTags(name, req.Tags)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration tags updated successfully",
+		Data:    tags,
+	})
+}
+
+// GetConfigTags handles GET /api/v1/configs/{name}/tags
+//
+//	@Summary		Get a configuration's tags
+//	@Description	Returns the key/value tags of a configuration, an empty object when it has none.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/tags [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "tags": {"env": "prod", "team": "payments"}
+//	  }
+//	}
+func (ch *ConfigHandler) GetConfigTags(c echo.Context) error {
+	name := c.Param("name")
+
+	tags, err := ch.configService.GetTags(name)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    tags,
+	})
+}
+
+// DeleteConfigTag handles DELETE /api/v1/configs/{name}/tags/{key}
+//
+//	@Summary		Remove a tag from a configuration
+//	@Description	Removes the tag with the given key from a configuration.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			key		path		string	true	"Tag key"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/tags/{key} [delete]
+func (ch *ConfigHandler) DeleteConfigTag(c echo.Context) error {
+	name := c.Param("name")
+
+	if err := ch.configService.RemoveTag(name, c.Param("key")); err != nil {
+		return ch.handleError(c, err)
+	}
+
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration tag removed successfully",
+	})
+}
+
+// mimeApplicationJSONLines is the content type of JSON Lines responses
+const mimeApplicationJSONLines = "application/x-ndjson"
+
+// ListAllLatestConfigs handles GET /api/v1/configs/all
+//
+//	@Summary		Get the latest data of every configuration
+//	@Description	Returns every configuration's latest version and data, ordered by name, read with a single query. With format=jsonl (or Accept: application/x-ndjson) the configurations are streamed as JSON Lines, one object per line, as they are read.
+//	@Tags			configurations
+//	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Param			format	query		string	false	"json (default) or jsonl"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/all [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": [
+//	    {"name": "feature-toggle", "version": 3, "config_data": {"max_limit": 200, "enabled": false}, "created_at": "2025-09-07T12:10:00Z", "format": "json"},
+//	    {"name": "rate-limits", "version": 1, "config_data": {"max_limit": 1000, "enabled": true}, "created_at": "2025-09-07T12:00:00Z", "format": "json"}
+//	  ]
+//	}
+func (ch *ConfigHandler) ListAllLatestConfigs(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" && c.Request().Header.Get(echo.HeaderAccept) == mimeApplicationJSONLines {
+		format = "jsonl"
+	}
+
+	switch format {
+	case "", "json":
+		configs, err := ch.configService.ListLatestConfigs()
+		if err != nil {
+			return ch.handleError(c, err)
+		}
+		actor := actorFromRequest(c)
+		for _, config := range configs {
+			ch.configService.LogAccess(config.Name, actor, models.AccessActionRead)
+		}
+		return c.JSON(http.StatusOK, models.SuccessResponse{
+			Success: true,
+			Data:    configs,
+		})
+	case "jsonl":
+		return ch.streamLatestConfigs(c)
+	default:
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_RESPONSE_FORMAT",
+				Message: "format must be json or jsonl",
+				Details: map[string]string{"format": format},
+			},
+		})
+	}
+}
+
+// streamLatestConfigs writes every configuration's latest data as JSON Lines while it is read.
+// Once the first line is sent the status can no longer change, so a later failure ends the
+// stream early and is only logged; clients detect it as a truncated body.
+func (ch *ConfigHandler) streamLatestConfigs(c echo.Context) error {
+	res := c.Response()
+	encoder := json.NewEncoder(res)
+	var streamed []string
+	started := false
+
+	err := ch.configService.EachLatestConfig(func(data models.ConfigurationData) error {
+		if !started {
+			res.Header().Set(echo.HeaderContentType, mimeApplicationJSONLines)
+			res.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+		res.Flush()
+		streamed = append(streamed, data.Name)
+		return nil
+	})
+
+	// Accesses are recorded once the read is done; SQLite cannot write while the query is open
+	actor := actorFromRequest(c)
+	for _, name := range streamed {
+		ch.configService.LogAccess(name, actor, models.AccessActionRead)
+	}
+
+	if err != nil && !started {
+		return ch.handleError(c, err)
+	}
+	if err != nil {
+		log.Printf("Streaming latest configurations stopped early: %v", err)
+		return nil
+	}
+
+	if !started {
+		res.Header().Set(echo.HeaderContentType, mimeApplicationJSONLines)
+		res.WriteHeader(http.StatusOK)
+	}
+	return nil
+}
+
+// ListConfigs handles GET /api/v1/configs
+//
+//	@Summary		List configurations
+//	@Description	Returns every configuration with its current version and timestamps, sorted server-side. Repeated tag=key:value filters list only the configurations carrying all of the given tags.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			sort	query		string	false	"Sort field: name, created_at, updated_at or version (default name)"
+//	@Param			order	query		string	false	"Sort order: asc or desc (default asc)"
+//	@Param			limit	query		int		false	"Maximum number of configurations to return (default 50, max 200)"
+//	@Param			offset	query		int		false	"Number of configurations to skip"
+//	@Param			include_deleted	query	bool	false	"Also list soft-deleted configurations, with their deleted_at"
+//	@Param			tag		query		[]string	false	"Tag filter written key:value; repeat to require several tags"	collectionFormat(multi)
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "configurations": [
+//	      {"name": "rate-limits", "current_version": 4, "created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-09T08:30:00Z"},
+//	      {"name": "feature-toggle", "current_version": 2, "created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-08T10:00:00Z"}
+//	    ],
+//	    "pagination": {"total": 5, "limit": 2, "offset": 0, "next": "/api/v1/configs?limit=2&offset=2", "prev": null}
+//	  }
+//	}
+func (ch *ConfigHandler) ListConfigs(c echo.Context) error {
+	sortField := c.QueryParam("sort")
+	if sortField == "" {
+		sortField = "name"
+	}
+
+	order := c.QueryParam("order")
+	if order != "" && order != "asc" && order != "desc" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_SORT_ORDER",
+				Message: "order must be asc or desc",
+				Details: map[string]string{"order": order},
+			},
+		})
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return invalidPagination(c, err)
+	}
+	if limit > maxConfigPageSize {
+		return invalidPagination(c, fmt.Errorf("limit must be at most %d", maxConfigPageSize))
+	}
+	if limit == 0 {
+		limit = defaultConfigPageSize
+	}
+
+	tags, err := services.ParseTagSelectors(c.QueryParams()["tag"])
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	includeDeleted := c.QueryParam("include_deleted") == "true"
+	configs, err := ch.configService.ListConfigs(sortField, order == "desc", includeDeleted, tags, limit, offset)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+	setPaginationLinks(c, configs.Pagination)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    configs,
+	})
+}
+
+// SquashConfig handles POST /api/v1/configs/{name}/squash
+//
+//	@Summary		Squash a configuration's history
+//	@Description	Deletes every version below keep_from and renumbers the remaining versions from 1, updating the current version, in one transaction. Version numbers change, so this requires confirm=true and is recorded with the X-Actor caller in the squash log.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name		path		string	true	"Configuration name"
+//	@Param			keep_from	query		int		true	"Earliest version to keep; it becomes version 1"
+//	@Param			confirm		query		bool	true	"Must be true to perform the squash"
+//	@Param			X-Actor		header		string	false	"Caller recorded in the squash log"
+//	@Success		200			{object}	models.SuccessResponse	"OK"
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Failure		404			{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/squash [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration history squashed successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "keep_from": 5,
+//	    "removed_versions": 4,
+//	    "previous_version": 9,
+//	    "current_version": 5
+//	  }
+//	}
+func (ch *ConfigHandler) SquashConfig(c echo.Context) error {
+	name := c.Param("name")
+
+	keepFrom, err := strconv.Atoi(c.QueryParam("keep_from"))
+	if err != nil || keepFrom < 2 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_KEEP_FROM",
+				Message: "keep_from must be an integer of at least 2",
+				Details: map[string]string{"keep_from": c.QueryParam("keep_from")},
+			},
+		})
+	}
+
+	if c.QueryParam("confirm") != "true" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "CONFIRMATION_REQUIRED",
+				Message: "Squashing renumbers versions and requires confirm=true",
+				Details: map[string]interface{}{"name": name, "keep_from": keepFrom},
+			},
+		})
+	}
+
+	result, err := ch.configService.SquashConfig(name, keepFrom, actorFromRequest(c))
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration history squashed successfully",
+		Data:    result,
+	})
+}
+
+// PruneVersions handles POST /api/v1/configs/{name}/prune
+//
+//	@Summary		Prune a configuration's old versions
+//	@Description	Deletes every published version older than the keep most recent ones, in one transaction. The current version, the redo target, protected versions and drafts are never deleted, and the remaining versions keep their numbers.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Param			keep	query		int		true	"Number of most recent published versions to keep, at least 1"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/prune [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration versions pruned successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "keep": 3,
+//	    "current_version": 9,
+//	    "removed_versions": [1, 2, 3, 4, 5, 6]
+//	  }
+//	}
+func (ch *ConfigHandler) PruneVersions(c echo.Context) error {
+	name := c.Param("name")
+
+	keep, err := strconv.Atoi(c.QueryParam("keep"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_KEEP",
+				Message: "keep must be an integer of at least 1",
+				Details: map[string]string{"keep": c.QueryParam("keep")},
+			},
+		})
+	}
+
+	result, err := ch.configService.PruneVersions(name, keep)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration versions pruned successfully",
+		Data:    result,
+	})
+}
+
+// RenameConfig handles POST /api/v1/configs/{name}/rename
+//
+//	@Summary		Rename a configuration
+//	@Description	Moves a configuration to a new name together with its full version history, access log and squash log, in one transaction. Fails if the new name is already taken.
+//	@Tags			configurations
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string						true	"Configuration name"
+//	@Param			body	body		models.RenameConfigRequest	true	"New name"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/rename [post]
+//
+//	@Example request
+//	{
+//	  "new_name": "checkout-limits"
+//	}
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration renamed successfully",
+//	  "data": {
+//	    "name": "checkout-limits",
+//	    "current_version": 4,
+//	    "created_at": "2025-09-07T12:00:00Z",
+//	    "updated_at": "2025-09-07T12:15:00Z",
+//	    "sensitive": false
+//	  }
+//	}
+func (ch *ConfigHandler) RenameConfig(c echo.Context) error {
+	name := c.Param("name")
+
+	var req models.RenameConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+
+	if req.NewName == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "MISSING_REQUIRED_FIELD",
+				Message: "Missing required field: new_name",
+				Details: map[string][]string{
+					"required_fields": {"new_name"},
+				},
+			},
+		})
+	}
+
+	if errDetail := ch.invalidConfigName(req.NewName); errDetail != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   *errDetail,
+		})
+	}
+
+	meta, err := ch.configService.RenameConfig(name, req.NewName)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration renamed successfully",
+		Data:    meta,
+	})
+}
+
+// SyncConfigs handles POST /api/v1/configs/sync
+//
+//	@Summary		Delta sync of many configurations
+//	@Description	Takes the version the client last saw of each configuration and, in one round trip, returns the configurations with a newer current version, the configurations the client does not know yet, and the known names that no longer exist.
+//	@Tags			configurations
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.SyncConfigsRequest	true	"Last-seen version per configuration"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/sync [post]
+//
+//	@Example request
+//	{
+//	  "known": {"feature-toggle": 3, "rate-limits": 1}
+//	}
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "updated": [
+//	      {"name": "rate-limits", "version": 2, "config_data": {"max_limit": 500, "enabled": true}, "created_at": "2025-09-09T08:30:00Z", "format": "json"}
+//	    ],
+//	    "new": [
+//	      {"name": "checkout", "version": 1, "config_data": {"max_limit": 50, "enabled": false}, "created_at": "2025-09-10T09:00:00Z", "format": "json"}
+//	    ],
+//	    "removed": []
+//	  }
+//	}
+func (ch *ConfigHandler) SyncConfigs(c echo.Context) error {
+	var req models.SyncConfigsRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+
+	result, err := ch.configService.SyncConfigs(req.Known)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	actor := actorFromRequest(c)
+	for _, changed := range [][]models.ConfigurationData{result.Updated, result.New} {
+		for _, config := range changed {
+			ch.configService.LogAccess(config.Name, actor, models.AccessActionRead)
+		}
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// maxExistsNames caps the number of names checked by one ConfigsExist request
+const maxExistsNames = 1000
+
+// ConfigsExist handles POST /api/v1/configs:exists
+//
+//	@Summary		Check which configurations exist
+//	@Description	Returns a map of configuration name to whether it exists, for up to 1000 names in one request.
+//	@Tags			configurations
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.ConfigsExistRequest	true	"Names to check"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs:exists [post]
+//
+//	@Example request
+//	{
+//	  "names": ["feature-toggle", "rate-limits"]
+//	}
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {"feature-toggle": true, "rate-limits": false}
+//	}
+func (ch *ConfigHandler) ConfigsExist(c echo.Context) error {
+	var req models.ConfigsExistRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+
+	if len(req.Names) == 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "MISSING_REQUIRED_FIELD",
+				Message: "Missing required field: names",
+				Details: map[string][]string{
+					"required_fields": {"names"},
+				},
+			},
+		})
+	}
+
+	if len(req.Names) > maxExistsNames {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "TOO_MANY_NAMES",
+				Message: fmt.Sprintf("At most %d names can be checked per request", maxExistsNames),
+				Details: map[string]int{"names": len(req.Names)},
+			},
+		})
+	}
+
+	exists, err := ch.configService.ConfigsExist(req.Names)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    exists,
+	})
+}
+
+// maxBatchItems caps the number of configurations created by one CreateConfigsBatch request
+const maxBatchItems = 1000
+
+// CreateConfigsBatch handles POST /api/v1/configs/batch
+//
+//	@Summary		Create many configurations
+//	@Description	Validates and creates each configuration of the array at version 1, in a single transaction, and reports per item whether it was created or the error that prevented it, for up to 1000 items. By default every valid item is created and 207 reports the failed ones; with atomic=true a single failure creates nothing and the valid items report BATCH_ROLLED_BACK. With dry_run=true every item is checked, existing names included, but nothing is created: 200 reports that every item would be created, 207 lists the ones that would fail.
+//	@Tags			configurations
+//	@Accept			json
+//	@Produce		json
+//	@Param			atomic	query		bool					false	"Create either every item or none"
+//	@Param			dry_run	query		bool					false	"Report what would be created without creating anything"
+//	@Param			body	body		[]models.BatchCreateItem	true	"Configurations to create"
+//	@Success		200		{object}	models.SuccessResponse	"Dry run: every item would be created"
+//	@Success		201		{object}	models.SuccessResponse	"Every item created"
+//	@Success		207		{object}	models.SuccessResponse	"Some items failed"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/batch [post]
+//
+//	@Example request
+//	[
+//	  {"name": "checkout", "data": {"max_limit": 50, "enabled": true}},
+//	  {"name": "bad name", "data": {"max_limit": 10, "enabled": false}}
+//	]
+//	@Example response 207
+//	{
+//	  "success": false,
+//	  "message": "1 of 2 configurations created",
+//	  "data": {
+//	    "atomic": false,
+//	    "created": 1,
+//	    "failed": 1,
+//	    "results": [
+//	      {"index": 0, "name": "checkout", "created": true, "version": 1, "created_at": "2025-09-07T12:00:00Z"},
+//	      {"index": 1, "name": "bad name", "created": false, "error": {"code": "INVALID_CONFIG_NAME", "message": "Configuration name contains invalid characters"}}
+//	    ]
+//	  }
+//	}
+func (ch *ConfigHandler) CreateConfigsBatch(c echo.Context) error {
+	var items []models.BatchCreateItem
+
+	if err := json.NewDecoder(c.Request().Body).Decode(&items); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be a JSON array of configurations",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
+		})
+	}
+
+	if len(items) == 0 {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "MISSING_REQUIRED_FIELD",
+				Message: "At least one configuration is required",
+				Details: map[string][]string{
+					"required_fields": {"name", "data"},
+				},
+			},
+		})
+	}
+
+	if len(items) > maxBatchItems {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "TOO_MANY_ITEMS",
+				Message: fmt.Sprintf("At most %d configurations can be created per request", maxBatchItems),
+				Details: map[string]int{"items": len(items)},
+			},
+		})
+	}
+
+	atomic := c.QueryParam("atomic") == "true"
+	dryRun := c.QueryParam("dry_run") == "true"
+	acceptLanguage := c.Request().Header.Get("Accept-Language")
+	result := models.BatchCreateResult{DryRun: dryRun, Atomic: atomic, Results: make([]models.BatchItemResult, len(items))}
+
+	// Names are checked here like a single create; only items passing them reach the service
+	var valid []models.BatchCreateItem
+	var positions []int
+	for i, item := range items {
+		result.Results[i] = models.BatchItemResult{Index: i, Name: item.Name}
+		if errDetail := ch.invalidBatchItem(item); errDetail != nil {
+			result.Results[i].Error = errDetail
+			continue
+		}
+		valid = append(valid, item)
+		positions = append(positions, i)
+	}
+
+	if atomic && !dryRun && len(valid) < len(items) {
+		for _, i := range positions {
+			_, detail := errorDetailFor(&services.BatchRolledBackError{ConfigName: items[i].Name}, acceptLanguage)
+			result.Results[i].Error = &detail
+		}
+	} else if len(valid) > 0 {
+		configs, itemErrs, err := ch.configService.CreateConfigsBatch(valid, atomic, dryRun, actorFromRequest(c))
+		if err != nil {
+			return ch.handleError(c, err)
+		}
+		for j, i := range positions {
+			if itemErrs[j] != nil {
+				_, detail := errorDetailFor(itemErrs[j], acceptLanguage)
+				result.Results[i].Error = &detail
+				continue
+			}
+			if atomic && len(valid) < len(items) {
+				// A dry run checked the valid items although an invalid one rolls back the batch
+				_, detail := errorDetailFor(&services.BatchRolledBackError{ConfigName: items[i].Name}, acceptLanguage)
+				result.Results[i].Error = &detail
+				continue
+			}
+			result.Results[i].Created = true
+			result.Results[i].Version = configs[j].CurrentVersion
+			result.Results[i].CreatedAt = &configs[j].CreatedAt
+		}
+	}
+
+	for _, itemResult := range result.Results {
+		if itemResult.Created {
+			result.Created++
+		} else {
+			result.Failed++
+		}
+	}
+
+	status := http.StatusCreated
+	message := fmt.Sprintf("%d of %d configurations created", result.Created, len(items))
+	if dryRun {
+		status = http.StatusOK
+		message = fmt.Sprintf("%d of %d configurations would be created", result.Created, len(items))
+	}
+	if result.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	return c.JSON(status, models.SuccessResponse{
+		Success: result.Failed == 0,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// invalidBatchItem returns the error detail for a bulk create item missing its name or data or
+// with a malformed name, or nil when the item can be validated and created
+func (ch *ConfigHandler) invalidBatchItem(item models.BatchCreateItem) *models.ErrorDetail {
+	if item.Name == "" || len(item.Data) == 0 {
+		return &models.ErrorDetail{
+			Code:    "MISSING_REQUIRED_FIELD",
+			Message: "Each configuration requires a name and data",
+			Details: map[string][]string{
+				"required_fields": {"name", "data"},
+			},
+		}
+	}
+	return ch.invalidConfigName(item.Name)
+}
+
+// DeleteConfigs handles DELETE /api/v1/configs
+//
+//	@Summary		Bulk delete configurations by name prefix
+//	@Description	Soft-deletes every configuration whose name starts with name_prefix in one transaction; their versions are kept and each can be restored. Requires confirm=true.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name_prefix	query		string	true	"Name prefix of the configurations to delete"
+//	@Param			confirm		query		bool	true	"Must be true to perform the deletion"
+//	@Success		200			{object}	models.SuccessResponse	"OK"
+//	@Failure		400			{object}	models.ErrorResponse
+//	@Router			/api/v1/configs [delete]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configurations deleted successfully",
+//	  "data": {
+//	    "deleted": 2,
+//	    "names": ["oldsvc-limits", "oldsvc-toggle"]
+//	  }
+//	}
+func (ch *ConfigHandler) DeleteConfigs(c echo.Context) error {
+	prefix := c.QueryParam("name_prefix")
+
+	if prefix == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "MISSING_REQUIRED_FIELD",
+				Message: "Missing required query parameter: name_prefix",
+				Details: map[string][]string{
+					"required_fields": {"name_prefix", "confirm"},
+				},
+			},
+		})
+	}
+
+	if c.QueryParam("confirm") != "true" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "CONFIRMATION_REQUIRED",
+				Message: "Bulk deletion requires confirm=true",
+				Details: map[string]string{"name_prefix": prefix},
+			},
+		})
+	}
+
+	deleted, err := ch.configService.DeleteConfigsByPrefix(prefix)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configurations deleted successfully",
+		Data:    deleted,
+	})
+}
+
+// DeleteConfig handles DELETE /api/v1/configs/{name}
+//
+//	@Summary		Delete a configuration
+//	@Description	Soft-deletes a configuration: it is hidden from reads, listings and writes, but its row and versions are kept for audit and it can be restored. A configuration with a protected version cannot be deleted.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name} [delete]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration deleted successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "current_version": 3,
+//	    "created_at": "2025-09-07T12:00:00Z",
+//	    "updated_at": "2025-09-08T10:00:00Z",
+//	    "deleted_at": "2025-09-09T08:30:00Z"
+//	  }
+//	}
+func (ch *ConfigHandler) DeleteConfig(c echo.Context) error {
+	name := c.Param("name")
+
+	config, err := ch.configService.DeleteConfig(name)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration deleted successfully",
+		Data:    config,
+	})
+}
+
+// RestoreConfig handles POST /api/v1/configs/{name}/restore
+//
+//	@Summary		Restore a deleted configuration
+//	@Description	Makes a soft-deleted configuration visible again at the version it was deleted at. Restoring a configuration that is not deleted fails with 409 CONFIG_NOT_DELETED.
+//	@Tags			configurations
+//	@Produce		json
+//	@Param			name	path		string	true	"Configuration name"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		404		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse
+//	@Router			/api/v1/configs/{name}/restore [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configuration restored successfully",
+//	  "data": {
+//	    "name": "feature-toggle",
+//	    "current_version": 3,
+//	    "created_at": "2025-09-07T12:00:00Z",
+//	    "updated_at": "2025-09-08T10:00:00Z"
+//	  }
+//	}
+func (ch *ConfigHandler) RestoreConfig(c echo.Context) error {
+	name := c.Param("name")
+
+	config, err := ch.configService.RestoreConfig(name)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+	ch.configService.LogAccess(name, actorFromRequest(c), models.AccessActionWrite)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: "Configuration restored successfully",
+		Data:    config,
+	})
+}
+
+// ExportConfigs handles GET /api/v1/export
+//
+//	@Summary		Export every configuration with its full history
+//	@Description	Streams an archive of every live configuration with all of its versions, data and timestamps, for backups and for moving configurations between environments with the import endpoint. Configurations are written as they are read, so large datasets are never held in memory; a failure after the first configuration is sent leaves the document truncated. Soft-deleted configurations are not exported.
+//	@Tags			export
+//	@Produce		json
+//	@Success		200	{object}	models.ExportArchive	"OK"
+//	@Failure		500	{object}	models.ErrorResponse
+//	@Router			/api/v1/export [get]
+//
+//	@Example response 200
+//	{
+//	  "format_version": 1,
+//	  "exported_at": "2025-09-07T12:30:00Z",
+//	  "configurations": [
+//	    {
+//	      "name": "feature-toggle",
+//	      "current_version": 2,
+//	      "created_at": "2025-09-07T12:00:00Z",
+//	      "updated_at": "2025-09-07T12:05:00Z",
+//	      "versions": [
+//	        {"version": 1, "data": {"max_limit": 100, "enabled": true}, "format": "json", "status": "published", "created_at": "2025-09-07T12:00:00Z"},
+//	        {"version": 2, "data": {"max_limit": 200, "enabled": false}, "format": "json", "status": "published", "created_at": "2025-09-07T12:05:00Z"}
+//	      ]
+//	    }
+//	  ]
+//	}
+func (ch *ConfigHandler) ExportConfigs(c echo.Context) error {
+	res := c.Response()
+	exportedAt := time.Now().UTC()
+	started := false
+
+	// The archive is written piecewise so each configuration is encoded and flushed on its own
+	start := func() error {
+		exportedAtJSON, err := json.Marshal(exportedAt)
+		if err != nil {
+			return err
+		}
+		res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res.Header().Set(echo.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="config-export-%s.json"`, exportedAt.Format("20060102T150405Z")))
+		res.WriteHeader(http.StatusOK)
+		started = true
+		_, err = fmt.Fprintf(res, `{"format_version":%d,"exported_at":%s,"configurations":[`, models.ExportArchiveFormat, exportedAtJSON)
+		return err
+	}
+
+	var exported []string
+	err := ch.configService.ExportAll(func(config models.ExportedConfiguration) error {
+		separator := ","
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+			separator = ""
+		}
+		data, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		if _, err := res.Write(append([]byte(separator), data...)); err != nil {
+			return err
+		}
+		res.Flush()
+		exported = append(exported, config.Name)
+		return nil
+	})
+
+	// Accesses are recorded once the read is done; SQLite cannot write while the query is open
+	actor := actorFromRequest(c)
+	for _, name := range exported {
+		ch.configService.LogAccess(name, actor, models.AccessActionRead)
+	}
+
+	if err != nil && !started {
+		return ch.handleError(c, err)
+	}
+	if err != nil {
+		log.Printf("Export stopped early: %v", err)
+		return nil
+	}
+
+	if !started {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	_, err = res.Write([]byte("]}\n"))
+	return err
+}
+
+// ImportConfigs handles POST /api/v1/import
+//
+//	@Summary		Import configurations from an export archive
+//	@Description	Recreates the configurations of an archive produced by GET /api/v1/export, keeping their version numbers, statuses and timestamps, in a single transaction. mode says what happens to a name that already exists, live or soft-deleted: fail (default) rejects the import with 409, skip keeps the existing configuration, overwrite replaces it and its history. Every version is validated against the active schema first; if any fails, nothing is imported and each failing version is listed in details.invalid_versions. With dry_run=true nothing is written: the result tells what the import would do, and instead of the first conflicting configuration, a 409 IMPORT_CONFLICTS lists all of them in details.conflicts. Version numbers repeated within a configuration are rejected up front with 400 DUPLICATE_VERSION_IN_IMPORT listing them in details.duplicates; with require_contiguous=true, missing version numbers are rejected with 400 VERSION_GAP_IN_IMPORT listing them in details.gaps.
+//	@Tags			export
+//	@Accept			json
+//	@Produce		json
+//	@Param			mode	query		string					false	"fail (default), skip or overwrite"
+//	@Param			dry_run	query		bool					false	"Report what would be imported without writing anything"
+//	@Param			require_contiguous	query	bool			false	"Refuse configurations whose versions do not run from 1 without gaps"
+//	@Param			body	body		models.ExportArchive	true	"Export archive"
+//	@Success		200		{object}	models.SuccessResponse	"OK"
+//	@Failure		400		{object}	models.ErrorResponse
+//	@Failure		409		{object}	models.ErrorResponse
+//	@Failure		422		{object}	models.ErrorResponse
+//	@Router			/api/v1/import [post]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "message": "Configurations imported successfully",
+//	  "data": {
+//	    "mode": "skip",
+//	    "created": ["feature-toggle"],
+//	    "overwritten": [],
+//	    "skipped": ["rate-limits"],
+//	    "versions": 2
+//	  }
+//	}
+func (ch *ConfigHandler) ImportConfigs(c echo.Context) error {
+	mode := c.QueryParam("mode")
+	switch mode {
+	case "":
+		mode = storage.ImportModeFail
+	case storage.ImportModeFail, storage.ImportModeSkip, storage.ImportModeOverwrite:
+	default:
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_IMPORT_MODE",
+				Message: "mode must be fail, skip or overwrite",
+				Details: map[string]string{"mode": mode},
+			},
+		})
+	}
+
+	var archive models.ExportArchive
+	if err := c.Bind(&archive); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_REQUEST_FORMAT",
+				Message: "Request body must be valid JSON",
+				Details: map[string]string{"parse_error": err.Error()},
+			},
 		})
 	}
+
+	for _, config := range archive.Configurations {
+		if errDetail := invalidArchivedName(config.Name); errDetail != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   *errDetail,
+			})
+		}
+	}
+
+	opts := services.ImportOptions{
+		Mode:              mode,
+		DryRun:            c.QueryParam("dry_run") == "true",
+		RequireContiguous: c.QueryParam("require_contiguous") == "true",
+	}
+	result, err := ch.configService.ImportArchive(archive, opts)
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	message := "Configurations imported successfully"
+	if opts.DryRun {
+		message = "Configurations would be imported successfully"
+	}
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// ExportEnvironment handles GET /api/v1/export/env
+//
+//	@Summary		Export the effective configuration of the environment
+//	@Description	Returns an object mapping every configuration name to its latest data.
+//	@Tags			export
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse	"OK"
+//	@Router			/api/v1/export/env [get]
+//
+//	@Example response 200
+//	{
+//	  "success": true,
+//	  "data": {
+//	    "feature-toggle": {"max_limit": 200, "enabled": false},
+//	    "rate-limits": {"max_limit": 1000, "enabled": true}
+//	  }
+//	}
+func (ch *ConfigHandler) ExportEnvironment(c echo.Context) error {
+	export, err := ch.configService.ExportEnvironment()
+	if err != nil {
+		return ch.handleError(c, err)
+	}
+
+	actor := actorFromRequest(c)
+	for name := range export {
+		ch.configService.LogAccess(name, actor, models.AccessActionRead)
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    export,
+	})
+}
+
+// handleError converts service errors to appropriate HTTP responses, carrying the request ID
+// so failures can be found in the logs
+//
+// With ?errors=grouped, schema validation errors are returned as a map from field path to
+// messages instead of the default array, for form UIs that attach messages to inputs.
+func (ch *ConfigHandler) handleError(c echo.Context, err error) error {
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+	status, detail := errorDetailFor(err, c.Request().Header.Get("Accept-Language"))
+	if status >= http.StatusInternalServerError {
+		// The response hides the cause, so it is logged under the request ID the client sees
+		slog.Error("service error", "request_id", requestID, "code", detail.Code, "error", err)
+	}
+	if c.QueryParam("errors") == "grouped" {
+		if details, ok := detail.Details.(map[string]interface{}); ok {
+			if validationErrors, ok := details["validation_errors"].([]services.ValidationError); ok {
+				details["validation_errors"] = groupValidationErrors(validationErrors)
+			}
+		}
+	}
+	return c.JSON(status, models.ErrorResponse{
+		Success:   false,
+		Error:     detail,
+		RequestID: requestID,
+	})
+}
+
+// errorDetailFor maps a service error to its HTTP status and error detail,
+// localizing schema validation messages for the given Accept-Language
+func errorDetailFor(err error, acceptLanguage string) (int, models.ErrorDetail) {
+	switch {
+	case isConfigAlreadyExistsError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "CONFIG_ALREADY_EXISTS",
+			Message: err.Error(),
+		}
+	case isConfigNotFoundError(err):
+		return http.StatusNotFound, models.ErrorDetail{
+			Code:    "CONFIG_NOT_FOUND",
+			Message: err.Error(),
+		}
+	case isConfigDeletedError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "CONFIG_DELETED",
+			Message: err.Error(),
+			Details: map[string]string{"name": err.(*storage.ConfigDeletedError).ConfigName},
+		}
+	case services.IsBatchRolledBackError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "BATCH_ROLLED_BACK",
+			Message: err.Error(),
+		}
+	case isConfigNotDeletedError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "CONFIG_NOT_DELETED",
+			Message: err.Error(),
+		}
+	case isVersionNotFoundError(err):
+		return http.StatusNotFound, models.ErrorDetail{
+			Code:    "VERSION_NOT_FOUND",
+			Message: err.Error(),
+		}
+	case isInvalidSortFieldError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "INVALID_SORT_FIELD",
+			Message: err.Error(),
+		}
+	case isDefaultConfigNotFoundError(err):
+		return http.StatusNotFound, models.ErrorDetail{
+			Code:    "DEFAULT_CONFIG_NOT_FOUND",
+			Message: err.Error(),
+		}
+	case isSchemaNotRecordedError(err):
+		return http.StatusNotFound, models.ErrorDetail{
+			Code:    "SCHEMA_NOT_RECORDED",
+			Message: err.Error(),
+		}
+	case isVersionProtectedError(err):
+		protectedErr := err.(*storage.VersionProtectedError)
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "VERSION_PROTECTED",
+			Message: err.Error(),
+			Details: map[string]interface{}{"name": protectedErr.ConfigName, "version": protectedErr.Version},
+		}
+	case isVersionNotDraftError(err):
+		notDraftErr := err.(*storage.VersionNotDraftError)
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "VERSION_NOT_DRAFT",
+			Message: err.Error(),
+			Details: map[string]interface{}{"name": notDraftErr.ConfigName, "version": notDraftErr.Version},
+		}
+	case isDraftSupersededError(err):
+		supersededErr := err.(*storage.DraftSupersededError)
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "DRAFT_SUPERSEDED",
+			Message: err.Error(),
+			Details: map[string]interface{}{
+				"name":            supersededErr.ConfigName,
+				"version":         supersededErr.Version,
+				"current_version": supersededErr.CurrentVersion,
+			},
+		}
+	case isVersionIsDraftError(err):
+		draftErr := err.(*storage.VersionIsDraftError)
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "VERSION_IS_DRAFT",
+			Message: err.Error(),
+			Details: map[string]interface{}{"name": draftErr.ConfigName, "version": draftErr.Version},
+		}
+	case isStaleVersionError(err):
+		staleErr := err.(*storage.StaleVersionError)
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "VERSION_CONFLICT",
+			Message: err.Error(),
+			Details: map[string]interface{}{
+				"expected_version": staleErr.ExpectedVersion,
+				"current_version":  staleErr.CurrentVersion,
+				"retryable":        false,
+			},
+		}
+	case isVersionConflictError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "VERSION_CONFLICT",
+			Message: err.Error(),
+			Details: map[string]bool{"retryable": true},
+		}
+	case isVersionGapError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "VERSION_SEQUENCE_GAP",
+			Message: err.Error(),
+		}
+	case isCannotRedoError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "CANNOT_REDO",
+			Message: err.Error(),
+		}
+	case services.IsSchemaMismatchError(err):
+		mismatch := err.(*services.SchemaMismatchError)
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "SCHEMA_MISMATCH",
+			Message: err.Error(),
+			Details: map[string]string{
+				"expected_schema_hash": mismatch.Expected,
+				"active_schema_hash":   mismatch.Active,
+			},
+		}
+	case services.IsNothingToUndoError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "NOTHING_TO_UNDO",
+			Message: err.Error(),
+		}
+	case services.IsAuditRetentionNotSetError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "AUDIT_RETENTION_NOT_SET",
+			Message: err.Error(),
+		}
+	case services.IsConfigBudgetExceededError(err):
+		budgetErr := err.(*services.ConfigBudgetExceededError)
+		return http.StatusRequestEntityTooLarge, models.ErrorDetail{
+			Code:    "CONFIG_BUDGET_EXCEEDED",
+			Message: err.Error(),
+			Details: map[string]interface{}{"budget": "data_size", "limit": budgetErr.MaxBytes, "size": budgetErr.Size},
+		}
+	case services.IsInvalidRetentionError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "INVALID_KEEP",
+			Message: err.Error(),
+			Details: map[string]int{"keep": err.(*services.InvalidRetentionError).Keep},
+		}
+	case services.IsInvalidArchiveError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "INVALID_ARCHIVE",
+			Message: err.Error(),
+		}
+	case isImportConflictsError(err):
+		conflicts := err.(*storage.ImportConflictsError).Conflicts
+		details := make([]models.ErrorDetail, 0, len(conflicts))
+		for _, conflict := range conflicts {
+			_, detail := errorDetailFor(conflict, acceptLanguage)
+			details = append(details, detail)
+		}
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "IMPORT_CONFLICTS",
+			Message: err.Error(),
+			Details: map[string]interface{}{"conflicts": details},
+		}
+	case services.IsDuplicateVersionError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "DUPLICATE_VERSION_IN_IMPORT",
+			Message: err.Error(),
+			Details: map[string]interface{}{"duplicates": err.(*services.DuplicateVersionError).Duplicates},
+		}
+	case services.IsImportVersionGapError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "VERSION_GAP_IN_IMPORT",
+			Message: err.Error(),
+			Details: map[string]interface{}{"gaps": err.(*services.ImportVersionGapError).Gaps},
+		}
+	case services.IsImportValidationError(err):
+		return http.StatusUnprocessableEntity, models.ErrorDetail{
+			Code:    "IMPORT_VALIDATION_FAILED",
+			Message: err.Error(),
+			Details: map[string]interface{}{"invalid_versions": err.(*services.ImportValidationError).Failures},
+		}
+	case services.IsInvalidPatchError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "INVALID_PATCH",
+			Message: err.Error(),
+		}
+	case services.IsPatchConflictError(err):
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "PATCH_CONFLICT",
+			Message: err.Error(),
+		}
+	case isTagNotFoundError(err):
+		return http.StatusNotFound, models.ErrorDetail{
+			Code:    "TAG_NOT_FOUND",
+			Message: err.Error(),
+		}
+	case services.IsInvalidTagError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "INVALID_TAG",
+			Message: err.Error(),
+			Details: map[string]string{"tag": err.(*services.InvalidTagError).Tag},
+		}
+	case isConfigBudgetNotFoundError(err):
+		return http.StatusNotFound, models.ErrorDetail{
+			Code:    "CONFIG_BUDGET_NOT_FOUND",
+			Message: err.Error(),
+		}
+	case services.IsDuplicateKeyError(err):
+		duplicate := err.(*services.DuplicateKeyError)
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "DUPLICATE_JSON_KEY",
+			Message: err.Error(),
+			Details: map[string]string{"path": duplicate.Path, "key": duplicate.Key},
+		}
+	case services.IsValidationUnavailableError(err):
+		return http.StatusServiceUnavailable, models.ErrorDetail{
+			Code:    "VALIDATION_UNAVAILABLE",
+			Message: err.Error(),
+		}
+	case services.IsInvalidSchemaError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "INVALID_SCHEMA",
+			Message: err.Error(),
+		}
+	case services.IsInvalidFormatError(err):
+		return http.StatusBadRequest, models.ErrorDetail{
+			Code:    "INVALID_CONFIG_FORMAT",
+			Message: err.Error(),
+		}
+	case services.IsSchemaValidationError(err):
+		validationErr := err.(*services.SchemaValidationError)
+		schemaErr := services.LocalizeSchemaValidationError(validationErr, acceptLanguage)
+		// Stray keys are the most common failure, so they get a code and message of their own
+		if fields := validationErr.UnknownFields(); len(fields) > 0 {
+			return http.StatusUnprocessableEntity, models.ErrorDetail{
+				Code:    "UNKNOWN_CONFIG_FIELD",
+				Message: services.UnknownFieldMessage(fields, acceptLanguage),
+				Details: map[string]interface{}{
+					"unknown_fields":    fields,
+					"validation_errors": schemaErr.Errors,
+				},
+			}
+		}
+		return http.StatusUnprocessableEntity, models.ErrorDetail{
+			Code:    "SCHEMA_VALIDATION_FAILED",
+			Message: schemaErr.Message,
+			Details: map[string]interface{}{
+				"validation_errors": schemaErr.Errors,
+			},
+		}
+	default:
+		return http.StatusInternalServerError, models.ErrorDetail{
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "An unexpected error occurred",
+		}
+	}
+}
+
+// groupValidationErrors groups validation messages by field path. Missing required properties
+// are reported on the property's own path rather than on its parent object.
+func groupValidationErrors(validationErrors []services.ValidationError) map[string][]string {
+	grouped := map[string][]string{}
+	for _, validationErr := range validationErrors {
+		path := validationErr.Field
+		if property, ok := validationErr.Details["property"].(string); ok && validationErr.Type == "required" {
+			if path == "(root)" || path == "" {
+				path = property
+			} else {
+				path += "." + property
+			}
+		}
+		grouped[path] = append(grouped[path], validationErr.Error)
+	}
+	return grouped
+}
+
+// setVersionETag sets the ETag of the served representation of configData, built from its
+// version, data checksum, draft status and ?format, along with the checksum header, and reports
+// whether the request's If-None-Match already matches it. Drafts get their own ETag, so
+// publishing one invalidates responses that still report it as a draft.
+func setVersionETag(c echo.Context, configData *models.ConfigurationData) (notModified bool) {
+	tag := fmt.Sprintf("v%d-%s", configData.Version, configData.Checksum)
+	if configData.Draft {
+		tag += "-draft"
+	}
+	if format := c.QueryParam("format"); format != "" && format != services.FormatJSON {
+		tag += "-" + format
+	}
+	etag := `"` + tag + `"`
+	c.Response().Header().Set("ETag", etag)
+	setChecksumHeader(c, configData)
+	return etagMatches(c.Request().Header.Get("If-None-Match"), etag)
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
 }
 
 // Helper functions for error type checking
@@ -430,25 +2833,302 @@ func isConfigNotFoundError(err error) bool {
 	return ok
 }
 
+func isConfigDeletedError(err error) bool {
+	_, ok := err.(*storage.ConfigDeletedError)
+	return ok
+}
+
+func isConfigNotDeletedError(err error) bool {
+	_, ok := err.(*storage.ConfigNotDeletedError)
+	return ok
+}
+
 func isVersionNotFoundError(err error) bool {
 	_, ok := err.(*storage.VersionNotFoundError)
 	return ok
 }
 
-// isValidConfigName validates configuration name pattern
-func isValidConfigName(name string) bool {
-	if len(name) == 0 || len(name) > 100 {
-		return false
+func isVersionGapError(err error) bool {
+	_, ok := err.(*storage.VersionGapError)
+	return ok
+}
+
+func isCannotRedoError(err error) bool {
+	_, ok := err.(*storage.CannotRedoError)
+	return ok
+}
+
+func isInvalidSortFieldError(err error) bool {
+	_, ok := err.(*storage.InvalidSortFieldError)
+	return ok
+}
+
+func isDefaultConfigNotFoundError(err error) bool {
+	_, ok := err.(*storage.DefaultConfigNotFoundError)
+	return ok
+}
+
+func isTagNotFoundError(err error) bool {
+	_, ok := err.(*storage.TagNotFoundError)
+	return ok
+}
+
+func isConfigBudgetNotFoundError(err error) bool {
+	_, ok := err.(*storage.ConfigBudgetNotFoundError)
+	return ok
+}
+
+func isSchemaNotRecordedError(err error) bool {
+	_, ok := err.(*storage.SchemaNotRecordedError)
+	return ok
+}
+
+func isImportConflictsError(err error) bool {
+	_, ok := err.(*storage.ImportConflictsError)
+	return ok
+}
+
+func isVersionProtectedError(err error) bool {
+	_, ok := err.(*storage.VersionProtectedError)
+	return ok
+}
+
+func isVersionNotDraftError(err error) bool {
+	_, ok := err.(*storage.VersionNotDraftError)
+	return ok
+}
+
+func isDraftSupersededError(err error) bool {
+	_, ok := err.(*storage.DraftSupersededError)
+	return ok
+}
+
+func isVersionIsDraftError(err error) bool {
+	_, ok := err.(*storage.VersionIsDraftError)
+	return ok
+}
+
+func isVersionConflictError(err error) bool {
+	_, ok := err.(*storage.VersionConflictError)
+	return ok
+}
+
+func isStaleVersionError(err error) bool {
+	_, ok := err.(*storage.StaleVersionError)
+	return ok
+}
+
+// decodeRollbackRequest strictly decodes the rollback body: unknown fields are rejected and
+// target_version must be present and an integer rather than silently defaulting to 0
+func decodeRollbackRequest(c echo.Context) (models.RollbackConfigRequest, *models.ErrorDetail) {
+	var req models.RollbackConfigRequest
+	var body struct {
+		TargetVersion json.RawMessage `json:"target_version"`
+	}
+
+	decoder := json.NewDecoder(c.Request().Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		return req, &models.ErrorDetail{
+			Code:    "INVALID_REQUEST_FORMAT",
+			Message: "Request body must be valid JSON with only the target_version field",
+			Details: map[string]string{"parse_error": err.Error()},
+		}
+	}
+
+	if len(body.TargetVersion) == 0 || string(body.TargetVersion) == "null" {
+		return req, &models.ErrorDetail{
+			Code:    "MISSING_REQUIRED_FIELD",
+			Message: "Missing required field: target_version",
+			Details: map[string][]string{
+				"required_fields": {"target_version"},
+			},
+		}
+	}
+
+	targetVersion, err := strconv.Atoi(string(body.TargetVersion))
+	if err != nil {
+		return req, &models.ErrorDetail{
+			Code:    "INVALID_VERSION_NUMBER",
+			Message: "Version number must be positive integer",
+			Details: map[string]string{"provided_version": string(body.TargetVersion)},
+		}
+	}
+
+	req.TargetVersion = targetVersion
+	return req, nil
+}
+
+// maxVersionNumber is the highest version number accepted in a path; it fits in 32 bits so
+// parsing behaves the same on every platform
+const maxVersionNumber = math.MaxInt32
+
+// parseVersionParam parses a version path parameter, telling input that is not a number apart
+// from numbers outside 1..maxVersionNumber, including ones too large to parse at all
+func parseVersionParam(value string) (int, *models.ErrorDetail) {
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, &models.ErrorDetail{
+			Code:    "INVALID_VERSION_NUMBER",
+			Message: "Version number must be a positive integer",
+			Details: map[string]string{"provided_version": value, "reason": "not_a_number"},
+		}
+	}
+
+	if err != nil || version < 1 || version > maxVersionNumber {
+		return 0, &models.ErrorDetail{
+			Code:    "INVALID_VERSION_NUMBER",
+			Message: fmt.Sprintf("Version number must be between 1 and %d", maxVersionNumber),
+			Details: map[string]interface{}{
+				"provided_version": value,
+				"reason":           "out_of_range",
+				"minimum_version":  1,
+				"maximum_version":  maxVersionNumber,
+			},
+		}
+	}
+
+	return int(version), nil
+}
+
+// actorFromRequest identifies the caller from the X-Actor header
+func actorFromRequest(c echo.Context) string {
+	if actor := c.Request().Header.Get("X-Actor"); actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
+
+// configNamePattern describes valid configuration names: alphanumeric runs joined by single separators
+const configNamePattern = "^[a-zA-Z0-9]+([_-][a-zA-Z0-9]+)*$"
+
+// Reasons a configuration name is rejected, reported as details.reason of INVALID_CONFIG_NAME
+const (
+	nameEmpty                 = "empty"
+	nameTooLong               = "too_long"
+	nameInvalidCharacter      = "invalid_character"
+	nameNoAlphanumeric        = "no_alphanumeric"
+	nameLeadingSeparator      = "leading_separator"
+	nameTrailingSeparator     = "trailing_separator"
+	nameConsecutiveSeparators = "consecutive_separators"
+)
+
+// nameReasonMessages explains each rejection reason
+var nameReasonMessages = map[string]string{
+	nameEmpty:                 "Configuration name must not be empty",
+	nameTooLong:               "Configuration name is too long",
+	nameInvalidCharacter:      "Configuration name contains invalid characters",
+	nameNoAlphanumeric:        "Configuration name must contain at least one letter or digit",
+	nameLeadingSeparator:      "Configuration name must not start with '-' or '_'",
+	nameTrailingSeparator:     "Configuration name must not end with '-' or '_'",
+	nameConsecutiveSeparators: "Configuration name must not contain consecutive '-' or '_' characters",
+}
+
+// isValidConfigName validates configuration name pattern and length
+func isValidConfigName(name string, maxLength int) bool {
+	reason, _ := configNameProblem(name, maxLength)
+	return reason == ""
+}
+
+// configNameProblem returns why name is not a valid configuration name, or an empty reason
+// for a valid one. Names are ASCII letters, digits and the separators '-' and '_', with at least
+// one letter or digit, no separator at either end and no two separators in a row. For an
+// invalid character, position is its byte offset, and for consecutive separators the offset of
+// the first of them; otherwise it is -1.
+func configNameProblem(name string, maxLength int) (reason string, position int) {
+	if len(name) == 0 {
+		return nameEmpty, -1
+	}
+	if len(name) > maxLength {
+		return nameTooLong, -1
 	}
+	if reason, position := nameCharacterProblem(name); reason != "" {
+		return reason, position
+	}
+
+	isSeparator := func(char byte) bool { return char == '-' || char == '_' }
+	switch {
+	case strings.Trim(name, "-_") == "":
+		return nameNoAlphanumeric, -1
+	case isSeparator(name[0]):
+		return nameLeadingSeparator, -1
+	case isSeparator(name[len(name)-1]):
+		return nameTrailingSeparator, -1
+	}
+	for i := 1; i < len(name); i++ {
+		if isSeparator(name[i]) && isSeparator(name[i-1]) {
+			return nameConsecutiveSeparators, i - 1
+		}
+	}
+
+	return "", -1
+}
 
-	for _, char := range name {
-		if (char < 'a' || char > 'z') &&
-			(char < 'A' || char > 'Z') &&
-			(char < '0' || char > '9') &&
-			char != '_' && char != '-' {
-			return false
+// nameCharacterProblem checks only that name is non-empty and made of ASCII letters, digits,
+// '-' and '_', the rule every stored name has met. For an invalid character, position is its
+// byte offset; otherwise it is -1.
+func nameCharacterProblem(name string) (reason string, position int) {
+	if len(name) == 0 {
+		return nameEmpty, -1
+	}
+	for i := 0; i < len(name); i++ {
+		char := name[i]
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		case char == '-' || char == '_':
+		default:
+			return nameInvalidCharacter, i
 		}
 	}
+	return "", -1
+}
+
+// invalidConfigName returns the INVALID_CONFIG_NAME error detail for name, with the specific
+// reason it was rejected, or nil when name is valid
+func (ch *ConfigHandler) invalidConfigName(name string) *models.ErrorDetail {
+	reason, position := configNameProblem(name, ch.maxNameLength)
+	if reason == "" {
+		return nil
+	}
+
+	details := map[string]interface{}{
+		"provided_name":   name,
+		"reason":          reason,
+		"allowed_pattern": configNamePattern,
+		"max_length":      ch.maxNameLength,
+	}
+	if position >= 0 {
+		details["position"] = position
+	}
+
+	return &models.ErrorDetail{
+		Code:    "INVALID_CONFIG_NAME",
+		Message: nameReasonMessages[reason],
+		Details: details,
+	}
+}
+
+// invalidArchivedName returns the INVALID_CONFIG_NAME error detail for a name in an import
+// archive, or nil when it is acceptable. Archives hold names the server already stored,
+// including ones from before the current shape rules or a lower MAX_NAME_LENGTH, so only the
+// character set is checked.
+func invalidArchivedName(name string) *models.ErrorDetail {
+	reason, position := nameCharacterProblem(name)
+	if reason == "" {
+		return nil
+	}
 
-	return true
+	details := map[string]interface{}{
+		"provided_name": name,
+		"reason":        reason,
+	}
+	if position >= 0 {
+		details["position"] = position
+	}
+
+	return &models.ErrorDetail{
+		Code:    "INVALID_CONFIG_NAME",
+		Message: nameReasonMessages[reason],
+		Details: details,
+	}
 }
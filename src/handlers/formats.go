@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"config-manager/src/models"
+	"config-manager/src/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// formatContentTypes maps the stored data formats to the content types they are served with
+var formatContentTypes = map[string]string{
+	services.FormatJSON: echo.MIMEApplicationJSONCharsetUTF8,
+	services.FormatYAML: "application/yaml",
+	services.FormatTOML: "application/toml",
+}
+
+// configText returns data submitted as a JSON string holding configuration text;
+// ok is false when data is a JSON object
+func configText(data json.RawMessage) (text string, ok bool) {
+	if err := json.Unmarshal(data, &text); err != nil {
+		return "", false
+	}
+	return text, true
+}
+
+// dataEncodingBase64 marks data sent as a string of base64-encoded JSON
+const dataEncodingBase64 = "base64"
+
+// decodeData returns data decoded per the request's data_encoding: unchanged when no encoding
+// is given, or the exact bytes of a base64 string, which must be a JSON object
+func decodeData(encoding string, data json.RawMessage) (json.RawMessage, *models.ErrorDetail) {
+	if encoding == "" {
+		return data, nil
+	}
+	if encoding != dataEncodingBase64 {
+		return nil, &models.ErrorDetail{
+			Code:    "INVALID_DATA_ENCODING",
+			Message: "data_encoding must be base64",
+			Details: map[string]string{"data_encoding": encoding},
+		}
+	}
+
+	encoded, ok := configText(data)
+	if !ok {
+		return nil, &models.ErrorDetail{
+			Code:    "INVALID_DATA_ENCODING",
+			Message: "data must be a string holding base64-encoded JSON",
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, &models.ErrorDetail{
+			Code:    "INVALID_DATA_ENCODING",
+			Message: "data is not valid base64",
+			Details: map[string]string{"decode_error": err.Error()},
+		}
+	}
+	if !json.Valid(decoded) || !bytes.HasPrefix(bytes.TrimSpace(decoded), []byte("{")) {
+		return nil, &models.ErrorDetail{
+			Code:    "INVALID_DATA_ENCODING",
+			Message: "decoded data must be a JSON object",
+		}
+	}
+
+	return decoded, nil
+}
+
+// invalidFormatData responds with 400 INVALID_CONFIG_FORMAT when a non-JSON format is named
+// but data is not a string holding the text
+func invalidFormatData(c echo.Context, format string) error {
+	return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Success: false,
+		Error: models.ErrorDetail{
+			Code:    "INVALID_CONFIG_FORMAT",
+			Message: fmt.Sprintf("data must be a string holding the %s text", format),
+			Details: map[string]string{"format": format},
+		},
+	})
+}
+
+// respondWithFormat serves configData as the standard JSON envelope, as the text it was
+// authored in when ?format=original is requested, as a flat dot-notation map with ?format=flat
+// or as `export KEY=value` lines with ?format=env. Either way the canonical data checksum is
+// sent in X-Config-Checksum, the same value the version ETag is built from.
+func respondWithFormat(c echo.Context, configData *models.ConfigurationData) error {
+	switch c.QueryParam("format") {
+	case "", services.FormatJSON:
+		setChecksumHeader(c, configData)
+		return c.JSON(http.StatusOK, models.SuccessResponse{
+			Success: true,
+			Data:    configData,
+		})
+	case "original":
+		setChecksumHeader(c, configData)
+		if configData.Original == "" {
+			return c.JSON(http.StatusOK, configData.ConfigData)
+		}
+		return c.Blob(http.StatusOK, formatContentTypes[configData.Format], []byte(configData.Original))
+	case "flat", "env":
+		setChecksumHeader(c, configData)
+		data, err := json.Marshal(configData.ConfigData)
+		if err != nil {
+			return err
+		}
+		flat, err := services.FlattenData(data)
+		if err != nil {
+			return err
+		}
+		if c.QueryParam("format") == "env" {
+			return c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, []byte(services.EnvLines(flat)))
+		}
+		return c.JSON(http.StatusOK, flat)
+	default:
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    "INVALID_RESPONSE_FORMAT",
+				Message: "format must be json, original, flat or env",
+				Details: map[string]string{"format": c.QueryParam("format")},
+			},
+		})
+	}
+}
+
+// setChecksumHeader sends the canonical checksum of configData in X-Config-Checksum
+func setChecksumHeader(c echo.Context, configData *models.ConfigurationData) {
+	if configData.Checksum != "" {
+		c.Response().Header().Set("X-Config-Checksum", configData.Checksum)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"config-manager/src/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Page sizes of the configuration listing, which is always paginated
+const (
+	defaultConfigPageSize = 50
+	maxConfigPageSize     = 200
+)
+
+// parsePagination reads the optional limit and offset query parameters; a missing limit means no limit
+func parsePagination(c echo.Context) (limit, offset int, err error) {
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return 0, 0, fmt.Errorf("limit must be a positive integer")
+		}
+	}
+
+	if value := c.QueryParam("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+// invalidPagination responds with 400 INVALID_PAGINATION for a parsePagination error
+func invalidPagination(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Success: false,
+		Error: models.ErrorDetail{
+			Code:    "INVALID_PAGINATION",
+			Message: err.Error(),
+			Details: map[string]string{
+				"limit":  c.QueryParam("limit"),
+				"offset": c.QueryParam("offset"),
+			},
+		},
+	})
+}
+
+// setPaginationLinks fills the next and prev links of p from the current request URL,
+// keeping every other query parameter as sent
+func setPaginationLinks(c echo.Context, p *models.Pagination) {
+	if p == nil {
+		return
+	}
+
+	if p.Offset+p.Limit < p.Total {
+		next := pageURL(c, p.Limit, p.Offset+p.Limit)
+		p.Next = &next
+	}
+
+	if p.Offset > 0 {
+		prev := pageURL(c, p.Limit, max(p.Offset-p.Limit, 0))
+		p.Prev = &prev
+	}
+}
+
+// pageURL rewrites the request URL's limit and offset query parameters
+func pageURL(c echo.Context, limit, offset int) string {
+	u := *c.Request().URL
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
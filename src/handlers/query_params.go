@@ -0,0 +1,46 @@
+package handlers
+
+// QueryParams lists the query parameters each route's handler reads, keyed by method and path
+// as registered on the route's group. Routes declare them with middleware.AllowedQueryParams, so
+// a parameter missing here is rejected under STRICT_QUERY_PARAMS; add new ones alongside the
+// handler reading them.
+var QueryParams = map[string][]string{
+	"GET /configs":                                  {"sort", "order", "limit", "offset", "include_deleted", "tag"},
+	"GET /configs/all":                              {"format"},
+	"GET /diff":                                     {"a", "b", "a_version", "b_version"},
+	"POST /configs":                                 {"errors"},
+	"POST /configs\\:exists":                        {},
+	"POST /configs/sync":                            {},
+	"POST /configs/batch":                           {"atomic", "dry_run"},
+	"DELETE /configs":                               {"name_prefix", "confirm"},
+	"PUT /configs/:name":                            {"errors", "create_if_missing", "draft"},
+	"PATCH /configs/:name":                          {"errors"},
+	"DELETE /configs/:name":                         {},
+	"POST /configs/:name/restore":                   {},
+	"POST /configs/:name/rollback":                  {},
+	"POST /configs/:name/undo":                      {},
+	"POST /configs/:name/redo":                      {},
+	"POST /configs/:name/squash":                    {"keep_from", "confirm"},
+	"POST /configs/:name/prune":                     {"keep"},
+	"POST /configs/:name/rename":                    {},
+	"GET /configs/:name":                            {"apply_defaults", "default", "format", "include_drafts"},
+	"GET /configs/:name/versions/:version":          {"format", "include_drafts"},
+	"GET /configs/:name/meta":                       {},
+	"GET /configs/:name/storage":                    {},
+	"GET /configs/:name/diff":                       {"from", "to", "format"},
+	"GET /configs/:name/evaluate":                   {"key"},
+	"POST /configs/:name/validate":                  {},
+	"GET /configs/:name/versions/:version/schema":   {},
+	"POST /configs/:name/versions/:version/protect": {},
+	"POST /configs/:name/versions/:version/publish": {},
+	"GET /configs/:name/versions":                   {"include_drafts", "include_data", "include_age", "missing_ok", "limit", "offset"},
+	"GET /configs/:name/duplicates":                 {},
+	"PUT /configs/:name/sensitive":                  {},
+	"GET /configs/:name/tags":                       {},
+	"PUT /configs/:name/tags":                       {},
+	"DELETE /configs/:name/tags/:key":               {},
+	"GET /export":                                   {},
+	"POST /import":                                  {"mode", "dry_run", "require_contiguous"},
+	"GET /export/env":                               {},
+	"POST /rpc":                                     {},
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"config-manager/src/models"
+	"config-manager/src/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// rpcParams holds the union of parameters accepted by the JSON-RPC methods
+type rpcParams struct {
+	Name          string          `json:"name"`
+	Data          json.RawMessage `json:"data"`
+	TargetVersion int             `json:"target_version"`
+	Version       int             `json:"version"`
+	IncludeData   bool            `json:"include_data"`
+	IncludeAge    bool            `json:"include_age"`
+	Limit         int             `json:"limit"`
+	Offset        int             `json:"offset"`
+	SchemaHash    string          `json:"schema_hash"`
+}
+
+// RPC handles POST /rpc
+//
+//	@Summary		JSON-RPC 2.0 endpoint
+//	@Description	Accepts a single JSON-RPC 2.0 request or a batch (array) of requests. Supported methods: createConfig, updateConfig, rollbackConfig, getLatestConfig, getConfigVersion, listVersions.
+//	@Tags			rpc
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		models.RPCRequest	true	"JSON-RPC request or batch"
+//	@Success		200		{object}	models.RPCResponse	"OK"
+//	@Success		204		"All requests were notifications"
+//	@Router			/rpc [post]
+//
+//	@Example request
+//	[
+//	  {"jsonrpc": "2.0", "method": "getLatestConfig", "params": {"name": "feature-toggle"}, "id": 1},
+//	  {"jsonrpc": "2.0", "method": "listVersions", "params": {"name": "feature-toggle"}, "id": 2}
+//	]
+//	@Example response 200
+//	[
+//	  {"jsonrpc": "2.0", "result": {"name": "feature-toggle", "version": 3, "config_data": {"max_limit": 200, "enabled": false}}, "id": 1},
+//	  {"jsonrpc": "2.0", "error": {"code": -32000, "message": "CONFIG_NOT_FOUND: Configuration 'feature-toggle' not found", "data": {"code": "CONFIG_NOT_FOUND"}}, "id": 2}
+//	]
+func (ch *ConfigHandler) RPC(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusOK, rpcErrorResponse(nil, models.RPCParseError, "Parse error", nil))
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return c.JSON(http.StatusOK, rpcErrorResponse(nil, models.RPCParseError, "Parse error", nil))
+		}
+		if len(batch) == 0 {
+			return c.JSON(http.StatusOK, rpcErrorResponse(nil, models.RPCInvalidRequest, "Invalid Request", nil))
+		}
+
+		responses := []models.RPCResponse{}
+		for _, raw := range batch {
+			if response := ch.handleRPCRequest(c, raw); response != nil {
+				responses = append(responses, *response)
+			}
+		}
+
+		if len(responses) == 0 {
+			return c.NoContent(http.StatusNoContent)
+		}
+		return c.JSON(http.StatusOK, responses)
+	}
+
+	if !json.Valid(trimmed) {
+		return c.JSON(http.StatusOK, rpcErrorResponse(nil, models.RPCParseError, "Parse error", nil))
+	}
+
+	response := ch.handleRPCRequest(c, trimmed)
+	if response == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// handleRPCRequest executes a single JSON-RPC request; notifications (no id) produce no response
+func (ch *ConfigHandler) handleRPCRequest(c echo.Context, raw json.RawMessage) *models.RPCResponse {
+	var req models.RPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcErrorResponse(nil, models.RPCInvalidRequest, "Invalid Request", nil)
+	}
+
+	var params rpcParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return rpcReply(req.ID, rpcErrorResponse(req.ID, models.RPCInvalidParams, "Invalid params", err.Error()))
+		}
+	}
+
+	result, rpcErr := ch.dispatchRPC(c, req.Method, params)
+	if rpcErr != nil {
+		return rpcReply(req.ID, &models.RPCResponse{JSONRPC: "2.0", Error: rpcErr, ID: req.ID})
+	}
+
+	return rpcReply(req.ID, &models.RPCResponse{JSONRPC: "2.0", Result: result, ID: req.ID})
+}
+
+// dispatchRPC maps a JSON-RPC method onto the corresponding service call
+func (ch *ConfigHandler) dispatchRPC(c echo.Context, method string, params rpcParams) (interface{}, *models.RPCError) {
+	// Calls addressing a configuration count against its request budget, as REST requests do
+	if params.Name != "" {
+		if limit, allowed := ch.configService.AllowRequest(params.Name); !allowed {
+			detail := models.ErrorDetail{
+				Code:    "CONFIG_BUDGET_EXCEEDED",
+				Message: fmt.Sprintf("Configuration '%s' allows at most %d requests per minute", params.Name, limit),
+				Details: map[string]interface{}{"budget": "requests", "limit": limit},
+			}
+			return nil, &models.RPCError{Code: models.RPCServerError, Message: detail.Message, Data: detail}
+		}
+	}
+
+	switch method {
+	case "createConfig":
+		if errDetail := ch.invalidConfigName(params.Name); errDetail != nil {
+			return nil, &models.RPCError{Code: models.RPCInvalidParams, Message: "Invalid params", Data: errDetail.Message}
+		}
+		if params.SchemaHash != "" {
+			if err := ch.configService.VerifyActiveSchema(params.SchemaHash); err != nil {
+				return nil, rpcErrorFor(c, err)
+			}
+		}
+		config, err := ch.configService.CreateConfig(params.Name, string(params.Data), actorFromRequest(c))
+		if err != nil {
+			return nil, rpcErrorFor(c, err)
+		}
+		return models.ConfigurationCreated{Name: config.Name, Version: config.CurrentVersion, CreatedAt: config.CreatedAt}, nil
+	case "updateConfig":
+		config, err := ch.configService.UpdateConfig(params.Name, string(params.Data), actorFromRequest(c))
+		if err != nil {
+			return nil, rpcErrorFor(c, err)
+		}
+		ch.configService.LogAccess(params.Name, actorFromRequest(c), models.AccessActionWrite)
+		return models.ConfigurationUpdated{Name: config.Name, Version: config.CurrentVersion, UpdatedAt: config.UpdatedAt}, nil
+	case "rollbackConfig":
+		if params.TargetVersion < 1 {
+			return nil, &models.RPCError{Code: models.RPCInvalidParams, Message: "Invalid params", Data: "target_version must be positive integer"}
+		}
+		config, err := ch.configService.RollbackConfig(params.Name, params.TargetVersion, actorFromRequest(c))
+		if err != nil {
+			return nil, rpcErrorFor(c, err)
+		}
+		ch.configService.LogAccess(params.Name, actorFromRequest(c), models.AccessActionWrite)
+		return models.ConfigurationRollback{
+			Name:          config.Name,
+			NewVersion:    config.CurrentVersion,
+			TargetVersion: params.TargetVersion,
+			RolledBackAt:  config.UpdatedAt,
+		}, nil
+	case "getLatestConfig":
+		configData, err := ch.configService.GetLatestConfig(params.Name)
+		if err != nil {
+			return nil, rpcErrorFor(c, err)
+		}
+		ch.configService.LogAccess(params.Name, actorFromRequest(c), models.AccessActionRead)
+		return configData, nil
+	case "getConfigVersion":
+		if params.Version < 1 {
+			return nil, &models.RPCError{Code: models.RPCInvalidParams, Message: "Invalid params", Data: "version must be positive integer"}
+		}
+		configData, err := ch.configService.GetConfigVersion(params.Name, params.Version)
+		if err != nil {
+			return nil, rpcErrorFor(c, err)
+		}
+		ch.configService.LogAccess(params.Name, actorFromRequest(c), models.AccessActionRead)
+		return configData, nil
+	case "listVersions":
+		if params.Limit < 0 || params.Offset < 0 {
+			return nil, &models.RPCError{Code: models.RPCInvalidParams, Message: "Invalid params", Data: "limit and offset must be non-negative integers"}
+		}
+		versionList, err := ch.configService.ListVersions(params.Name, services.ListVersionsOptions{
+			IncludeData: params.IncludeData,
+			IncludeAge:  params.IncludeAge,
+			Limit:       params.Limit,
+			Offset:      params.Offset,
+		})
+		if err != nil {
+			return nil, rpcErrorFor(c, err)
+		}
+		// Listing with data reads every version, so it is audited like reading one
+		if params.IncludeData && len(versionList.Versions) > 0 {
+			ch.configService.LogAccess(params.Name, actorFromRequest(c), models.AccessActionRead)
+		}
+		return versionList, nil
+	default:
+		return nil, &models.RPCError{Code: models.RPCMethodNotFound, Message: "Method not found", Data: method}
+	}
+}
+
+// rpcErrorFor derives a JSON-RPC error object from a service error using the REST error mapping
+func rpcErrorFor(c echo.Context, err error) *models.RPCError {
+	status, detail := errorDetailFor(err, c.Request().Header.Get("Accept-Language"))
+	if status == http.StatusInternalServerError {
+		return &models.RPCError{Code: models.RPCInternalError, Message: detail.Message, Data: detail}
+	}
+	return &models.RPCError{Code: models.RPCServerError, Message: detail.Message, Data: detail}
+}
+
+// rpcErrorResponse builds a JSON-RPC error response
+func rpcErrorResponse(id json.RawMessage, code int, message string, data interface{}) *models.RPCResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &models.RPCResponse{
+		JSONRPC: "2.0",
+		Error:   &models.RPCError{Code: code, Message: message, Data: data},
+		ID:      id,
+	}
+}
+
+// rpcReply suppresses the response for notifications, which carry no id
+func rpcReply(id json.RawMessage, response *models.RPCResponse) *models.RPCResponse {
+	if id == nil {
+		return nil
+	}
+	return response
+}
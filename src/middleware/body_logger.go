@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"path"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// redactedValue replaces the data of sensitive configurations in logged bodies
+const redactedValue = "[REDACTED]"
+
+// BodyLogConfig configures request/response body logging for debugging
+type BodyLogConfig struct {
+	// MaxBytes caps the number of bytes logged per body
+	MaxBytes int
+	// NamePatterns limits logging to configuration names matching one of these glob patterns;
+	// an empty list logs every configuration
+	NamePatterns []string
+	// IsSensitive reports whether a configuration's data must be redacted
+	IsSensitive func(name string) bool
+}
+
+// BodyLogger logs the size-capped request and response bodies of mutating endpoints
+func BodyLogger(config BodyLogConfig) echo.MiddlewareFunc {
+	return echomiddleware.BodyDumpWithConfig(echomiddleware.BodyDumpConfig{
+		Skipper: func(c echo.Context) bool {
+			switch c.Request().Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				return false
+			default:
+				return true
+			}
+		},
+		Handler: func(c echo.Context, reqBody, resBody []byte) {
+			name := configNameFor(c, reqBody)
+			if !matchesAny(name, config.NamePatterns) {
+				return
+			}
+
+			switch {
+			case name == "":
+				// Bodies not tied to one configuration (bulk creates, imports, JSON-RPC) may carry
+				// the data of any configuration, sensitive ones included
+				reqBody = redactBody(reqBody)
+				resBody = redactBody(resBody)
+			case config.IsSensitive != nil && config.IsSensitive(name):
+				reqBody = redactRequest(reqBody)
+				resBody = redactData(resBody)
+			}
+
+			log.Printf("%s %s config=%q status=%d request=%s response=%s",
+				c.Request().Method, c.Request().URL.Path, name, c.Response().Status,
+				truncate(reqBody, config.MaxBytes), truncate(resBody, config.MaxBytes))
+		},
+	})
+}
+
+// configNameFor finds the configuration name from the route or, for creates, the request body
+func configNameFor(c echo.Context, reqBody []byte) string {
+	if name := c.Param("name"); name != "" {
+		return name
+	}
+
+	var body struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(reqBody, &body)
+	return body.Name
+}
+
+// matchesAny reports whether name matches one of the glob patterns; no patterns matches everything
+func matchesAny(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redactRequest redacts a request body for a sensitive configuration: the "data" field of a
+// {"data": ...} envelope, or the whole body otherwise, since patch documents and other raw
+// bodies carry the data itself
+func redactRequest(body []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil {
+		if _, ok := fields["data"]; ok {
+			return redactData(body)
+		}
+	}
+	return redactBody(body)
+}
+
+// redactBody replaces a whole non-empty body
+func redactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	redacted, _ := json.Marshal(redactedValue)
+	return redacted
+}
+
+// redactData replaces the top-level "data" field of a JSON object body
+func redactData(body []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+
+	if _, ok := fields["data"]; !ok {
+		return body
+	}
+	fields["data"], _ = json.Marshal(redactedValue)
+
+	redacted, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// truncate caps body at maxBytes, marking truncated output
+func truncate(body []byte, maxBytes int) string {
+	if maxBytes <= 0 || len(body) <= maxBytes {
+		return string(body)
+	}
+	return string(body[:maxBytes]) + "...(truncated)"
+}
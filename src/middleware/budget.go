@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"config-manager/src/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// budgetRetryAfter is the Retry-After sent with a rejected request, the length of a budget window
+const budgetRetryAfter = time.Minute
+
+// ConfigRequestBudget counts requests addressing a configuration by its :name route parameter
+// against that configuration's request budget. allow reports whether the request fits and the
+// per-minute limit; requests over budget are rejected with 429 CONFIG_BUDGET_EXCEEDED so one
+// hot configuration cannot degrade the others. Routes without a name are not counted.
+func ConfigRequestBudget(allow func(name string) (limit int, allowed bool)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			name := c.Param("name")
+			if name == "" {
+				return next(c)
+			}
+
+			limit, allowed := allow(name)
+			if allowed {
+				return next(c)
+			}
+
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(budgetRetryAfter.Seconds())))
+			return c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    "CONFIG_BUDGET_EXCEEDED",
+					Message: fmt.Sprintf("Configuration '%s' allows at most %d requests per minute", name, limit),
+					Details: map[string]interface{}{"budget": "requests", "limit": limit},
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Cache-Control policies for CacheControl
+const (
+	// CacheRevalidate lets clients store a response but revalidate it before every use
+	CacheRevalidate = "no-cache"
+	// CacheNoStore keeps responses out of every cache
+	CacheNoStore = "no-store"
+)
+
+// CacheControl sets the Cache-Control policy of the routes it is attached to. When attached at
+// both group and route level the route's policy wins. Error responses are never stored, so a 404
+// for something that may exist later is not pinned by a CDN.
+func CacheControl(policy string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response()
+			res.Before(func() {
+				if res.Status >= http.StatusBadRequest {
+					res.Header().Set(echo.HeaderCacheControl, CacheNoStore)
+					return
+				}
+				res.Header().Set(echo.HeaderCacheControl, policy)
+			})
+			return next(c)
+		}
+	}
+}
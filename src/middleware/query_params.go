@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+
+	"config-manager/src/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AllowedQueryParams declares the query parameters a route accepts. When strict is set, requests
+// carrying any other parameter are rejected with 400 UNKNOWN_QUERY_PARAM so a typo such as
+// ?limt=10 fails loudly; otherwise unknown parameters are ignored.
+func AllowedQueryParams(strict bool, allowed ...string) echo.MiddlewareFunc {
+	known := make(map[string]bool, len(allowed))
+	for _, param := range allowed {
+		known[param] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !strict {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			var unknown []string
+			for param := range c.QueryParams() {
+				if !known[param] {
+					unknown = append(unknown, param)
+				}
+			}
+			if len(unknown) == 0 {
+				return next(c)
+			}
+			sort.Strings(unknown)
+
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    "UNKNOWN_QUERY_PARAM",
+					Message: "Request contains query parameters this endpoint does not accept",
+					Details: map[string][]string{
+						"unknown": unknown,
+						"allowed": allowed,
+					},
+				},
+			})
+		}
+	}
+}
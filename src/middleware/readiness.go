@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"config-manager/src/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Readiness states reported by /ready
+const (
+	ReadinessMigrating = "migrating"
+	ReadinessReady     = "ready"
+)
+
+// Readiness tracks whether startup work such as database migrations has finished.
+// It starts out migrating; MarkReady flips it once the schema is complete.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// NewReadiness creates a readiness tracker in the migrating state
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// MarkReady records that startup work is complete
+func (r *Readiness) MarkReady() {
+	r.ready.Store(true)
+}
+
+// State returns the current readiness state
+func (r *Readiness) State() string {
+	if r.ready.Load() {
+		return ReadinessReady
+	}
+	return ReadinessMigrating
+}
+
+// Handler serves GET /ready: 200 once ready, 503 while migrating
+func (r *Readiness) Handler(c echo.Context) error {
+	status := http.StatusOK
+	if !r.ready.Load() {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, map[string]string{"status": r.State()})
+}
+
+// Gate rejects requests with 503 SERVICE_NOT_READY and a Retry-After hint until startup work is
+// complete, so nothing reaches a handler while the schema may be incomplete
+func (r *Readiness) Gate() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if r.ready.Load() {
+				return next(c)
+			}
+
+			c.Response().Header().Set("Retry-After", "1")
+			return c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    "SERVICE_NOT_READY",
+					Message: "The service is still starting up; retry shortly",
+					Details: map[string]string{"status": r.State()},
+				},
+			})
+		}
+	}
+}
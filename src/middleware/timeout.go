@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"config-manager/src/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestTimeout bounds the routes it is attached to. A handler still running after timeout gets
+// a 503 REQUEST_TIMEOUT sent in its place, unless it already started its response; whatever it
+// writes afterwards is discarded. The request context carries the same deadline, so work that
+// honors it stops early. The handler always runs to completion before the middleware returns,
+// as Echo reuses the context afterwards.
+func RequestTimeout(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			res := c.Response()
+			writer := &timeoutWriter{ResponseWriter: res.Writer, header: res.Header().Clone()}
+			res.Writer = writer
+
+			done := make(chan error, 1)
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						panicked <- r
+					}
+				}()
+				done <- next(c)
+			}()
+
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+
+			var err error
+			select {
+			case err = <-done:
+			case p := <-panicked:
+				res.Writer = writer.ResponseWriter
+				panic(p)
+			case <-timer.C:
+				writer.timeOut(timeoutBody(timeout))
+				// Wait for the handler: it still holds the context Echo is about to reuse
+				select {
+				case err = <-done:
+				case p := <-panicked:
+					res.Writer = writer.ResponseWriter
+					panic(p)
+				}
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				writer.timeOut(timeoutBody(timeout))
+			}
+			if writer.timedOut {
+				err = nil
+			}
+
+			res.Writer = writer.ResponseWriter
+			if writer.timedOut {
+				res.Status = http.StatusServiceUnavailable
+				res.Size = int64(len(timeoutBody(timeout)))
+				res.Committed = true
+			} else if !res.Committed {
+				writer.commitHeader()
+			}
+			return err
+		}
+	}
+}
+
+// timeoutBody is the REQUEST_TIMEOUT response sent when a handler overruns timeout
+func timeoutBody(timeout time.Duration) []byte {
+	body, _ := json.Marshal(models.ErrorResponse{
+		Success: false,
+		Error: models.ErrorDetail{
+			Code:    "REQUEST_TIMEOUT",
+			Message: "The request did not complete within " + timeout.String(),
+		},
+	})
+	return append(body, '\n')
+}
+
+// timeoutWriter passes a handler's response through until the timeout response is sent in its
+// place. The handler sets headers on its own copy, so it never touches the header map the
+// timeout response is written with.
+type timeoutWriter struct {
+	http.ResponseWriter
+
+	mu       sync.Mutex
+	header   http.Header
+	started  bool
+	timedOut bool
+}
+
+// Header returns the handler's copy of the response headers
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader starts the handler's response, unless the timeout response was sent
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.started {
+		return
+	}
+	w.start()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the handler's response body, or discards it once the timeout response was sent
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return len(b), nil
+	}
+	if !w.started {
+		w.start()
+		w.ResponseWriter.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the handler's response, unless the timeout response was sent
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer
+func (w *timeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// timeOut sends body as a 503 in place of the handler's response, unless the handler already
+// started its response, which then is left to complete
+func (w *timeoutWriter) timeOut(body []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started {
+		return
+	}
+	w.timedOut = true
+	w.started = true
+	header := w.ResponseWriter.Header()
+	header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	header.Set(echo.HeaderContentLength, fmt.Sprint(len(body)))
+	w.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.ResponseWriter.Write(body)
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// commitHeader copies the handler's headers to the response when it returns without having
+// written one, so an error response rendered afterwards keeps them
+func (w *timeoutWriter) commitHeader() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.started {
+		w.start()
+	}
+}
+
+// start copies the handler's headers to the response; the caller holds mu
+func (w *timeoutWriter) start() {
+	w.started = true
+	header := w.ResponseWriter.Header()
+	for key, values := range w.header {
+		header[key] = values
+	}
+}
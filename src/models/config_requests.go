@@ -5,15 +5,81 @@ import "encoding/json"
 // CreateConfigRequest is the request body for creating a configuration
 type CreateConfigRequest struct {
 	Name string          `json:"name" example:"feature_toggle"`
-	Data json.RawMessage `json:"data" swaggertype:"object" example:"{\"max_limit\": 100, \"enabled\": true}"`
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+	// Format names the format of data given as a string (json, yaml or toml); detected when omitted
+	Format string `json:"format,omitempty" example:"yaml"`
+	// DataEncoding, when "base64", means data is a string holding base64-encoded JSON
+	DataEncoding string `json:"data_encoding,omitempty" example:"base64"`
+	// SchemaHash, when set, makes the create conditional on the server's active schema having this hash
+	SchemaHash string `json:"schema_hash,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
 }
 
 // UpdateConfigRequest is the request body for updating a configuration
 type UpdateConfigRequest struct {
-	Data json.RawMessage `json:"data" example: {"max_limit": 100, "enabled": true}`
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+	// Format names the format of data given as a string (json, yaml or toml); detected when omitted
+	Format string `json:"format,omitempty" example:"yaml"`
+	// DataEncoding, when "base64", means data is a string holding base64-encoded JSON
+	DataEncoding string `json:"data_encoding,omitempty" example:"base64"`
+	// ExpectedVersion, when set, makes the update apply only while it is the current version
+	ExpectedVersion *int `json:"expected_version,omitempty" example:"3"`
+}
+
+// ValidateConfigRequest is the request body for validating candidate data against a configuration's schema
+type ValidateConfigRequest struct {
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// SetDefaultConfigRequest is the request body for setting a default configuration
+type SetDefaultConfigRequest struct {
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// SetConfigBudgetRequest is the request body for setting a configuration budget
+type SetConfigBudgetRequest struct {
+	// MaxRequestsPerMinute caps requests addressing the configuration; 0 is unlimited
+	MaxRequestsPerMinute int `json:"max_requests_per_minute" example:"600"`
+	// MaxDataBytes caps the size of data written to the configuration; 0 is unlimited
+	MaxDataBytes int `json:"max_data_bytes" example:"4096"`
+}
+
+// SchemaImpactRequest is the request body for a dry-run check of a candidate schema
+type SchemaImpactRequest struct {
+	Schema json.RawMessage `json:"schema" swaggertype:"object"`
 }
 
 // RollbackConfigRequest is the request body for rolling back a configuration
 type RollbackConfigRequest struct {
 	TargetVersion int `json:"target_version" example:"1"`
 }
+
+// RenameConfigRequest is the request body for renaming a configuration
+type RenameConfigRequest struct {
+	NewName string `json:"new_name" example:"checkout-limits"`
+}
+
+// BatchCreateItem is one configuration in the request body for creating configurations in bulk
+type BatchCreateItem struct {
+	Name string          `json:"name" example:"feature_toggle"`
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// SetSensitiveRequest is the request body for flagging a configuration as sensitive
+type SetSensitiveRequest struct {
+	Sensitive bool `json:"sensitive" example:"true"`
+}
+
+// SetTagsRequest is the request body for tagging a configuration
+type SetTagsRequest struct {
+	Tags map[string]string `json:"tags"`
+}
+
+// SyncConfigsRequest is the request body for delta sync: the version the client last saw of each configuration
+type SyncConfigsRequest struct {
+	Known map[string]int `json:"known" example:"feature-toggle:3,rate-limits:1"`
+}
+
+// ConfigsExistRequest is the request body for checking which configurations exist
+type ConfigsExistRequest struct {
+	Names []string `json:"names" example:"feature-toggle,rate-limits"`
+}
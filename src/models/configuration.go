@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -10,6 +11,8 @@ type Configuration struct {
 	CurrentVersion int       `json:"current_version" db:"current_version"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	// DeletedAt is set on soft-deleted configurations, which are only listed on request
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // Version represents a specific version of configuration data
@@ -19,12 +22,43 @@ type Version struct {
 	VersionNumber     int       `json:"version_number" db:"version_number"`
 	JsonData          string    `json:"json_data" db:"json_data"`
 	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	Protected         bool      `json:"protected" db:"protected"`
+	Status            string    `json:"status" db:"status"`
+	Format            string    `json:"format" db:"format"`
+	// OriginalData is the text as authored, kept for non-canonical formats; empty otherwise
+	OriginalData string `json:"original_data,omitempty" db:"original_data"`
+	// CreatedBy is the actor who created the version; empty for versions from before it was tracked
+	CreatedBy string `json:"created_by,omitempty" db:"created_by"`
 }
 
+// OriginalData is configuration text as authored, before conversion to JSON
+type OriginalData struct {
+	Format string
+	Text   string
+}
+
+// Version statuses: a draft waits for approval and is not served as the latest version until
+// it is published
+const (
+	VersionStatusPublished = "published"
+	VersionStatusDraft     = "draft"
+)
+
+// Access log actions recorded for sensitive configurations
+const (
+	AccessActionRead  = "read"
+	AccessActionWrite = "write"
+)
+
 // ConfigData represents the validated configuration data that must conform to the hardcoded JSON schema
 type ConfigData struct {
-	MaxLimit int  `json:"max_limit"`
-	Enabled  bool `json:"enabled"`
+	MaxLimit int    `json:"max_limit"`
+	Enabled  bool   `json:"enabled"`
+	Status   string `json:"status,omitempty"`
+	// RolloutPercent limits an enabled flag to that share of subjects; nil means everyone
+	RolloutPercent *int `json:"rollout_percent,omitempty"`
+	// RolloutSeed picks the bucketing of subjects; the configuration name when empty
+	RolloutSeed string `json:"rollout_seed,omitempty"`
 }
 
 // SuccessResponse represents the standard success response format
@@ -38,6 +72,8 @@ type SuccessResponse struct {
 type ErrorResponse struct {
 	Success bool        `json:"success"`
 	Error   ErrorDetail `json:"error"`
+	// RequestID correlates a failed service call with the server logs
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // ErrorDetail contains detailed error information
@@ -61,6 +97,24 @@ type ConfigurationUpdated struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// DraftCreated represents the response data for an update stored as a draft
+type DraftCreated struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Status  string `json:"status"`
+	// CurrentVersion is the published version that stays active until the draft is published
+	CurrentVersion int       `json:"current_version"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// VersionPublished represents the response data for publishing a draft
+type VersionPublished struct {
+	Name            string    `json:"name"`
+	Version         int       `json:"version"`
+	PreviousVersion int       `json:"previous_version"`
+	PublishedAt     time.Time `json:"published_at"`
+}
+
 // ConfigurationRollback represents the response data for configuration rollbacks
 type ConfigurationRollback struct {
 	Name          string    `json:"name"`
@@ -75,6 +129,78 @@ type ConfigurationData struct {
 	Version    int        `json:"version"`
 	ConfigData ConfigData `json:"config_data"`
 	CreatedAt  time.Time  `json:"created_at"`
+	// CreatedBy is the actor who created the version, when known
+	CreatedBy string `json:"created_by,omitempty"`
+	// Format is the format the version was authored in
+	Format string `json:"format"`
+	// Draft is set when the version is an unpublished draft, served only on request
+	Draft bool `json:"draft,omitempty"`
+	// Original is the text as authored, served by ?format=original
+	Original string `json:"-"`
+	// Checksum is the canonical data checksum, exposed through response headers
+	Checksum string `json:"-"`
+}
+
+// ValueChange is a value that differs between the two sides of a diff
+type ValueChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// DataDiff is the structured difference between two documents, keyed by dot-notation path
+// (a.b.c, arrays as a.0); a changed object or array is reported per leaf
+type DataDiff struct {
+	Identical bool                   `json:"identical"`
+	Added     map[string]interface{} `json:"added"`
+	Removed   map[string]interface{} `json:"removed"`
+	Changed   map[string]ValueChange `json:"changed"`
+}
+
+// DiffSide identifies the configuration version on one side of a comparison
+type DiffSide struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+}
+
+// ConfigComparison represents the response data for comparing two configurations; added and
+// removed are relative to A, so added keys exist only in B
+type ConfigComparison struct {
+	A DiffSide `json:"a"`
+	B DiffSide `json:"b"`
+	DataDiff
+}
+
+// VersionDiff represents the response data for comparing two versions of a configuration;
+// added and removed are relative to From, so added keys exist only in To
+type VersionDiff struct {
+	Name string `json:"name"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	DataDiff
+}
+
+// EvaluationContext describes who a flag is evaluated for
+type EvaluationContext struct {
+	// Key is a stable identifier of the subject, such as a user id
+	Key string
+}
+
+// FlagEvaluation is the flat result of evaluating a configuration as a feature flag, served
+// without the standard response envelope
+type FlagEvaluation struct {
+	Name     string `json:"name"`
+	Version  int    `json:"version"`
+	Enabled  bool   `json:"enabled"`
+	MaxLimit int    `json:"max_limit"`
+	// RolloutPercent and Bucket are set for gradual rollouts; Bucket (0-99) only when a key was given
+	RolloutPercent *int `json:"rollout_percent,omitempty"`
+	Bucket         *int `json:"bucket,omitempty"`
+}
+
+// ConfigurationList represents the response data for listing configurations
+type ConfigurationList struct {
+	Configurations []Configuration `json:"configurations"`
+	Pagination     *Pagination     `json:"pagination"`
 }
 
 // VersionList represents the response data for listing versions
@@ -82,10 +208,380 @@ type VersionList struct {
 	Name           string        `json:"name"`
 	CurrentVersion int           `json:"current_version"`
 	Versions       []VersionInfo `json:"versions"`
+	Pagination     *Pagination   `json:"pagination"`
 }
 
 // VersionInfo represents version metadata for listing
 type VersionInfo struct {
 	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	Protected bool      `json:"protected"`
+	Status    string    `json:"status"`
+	// Age and AgeSeconds are the time since CreatedAt by the server's clock, only populated when
+	// ages are requested; Age is an ISO-8601 duration
+	Age        string `json:"age,omitempty"`
+	AgeSeconds *int64 `json:"age_seconds,omitempty"`
+	// ConfigData is only populated when version data is requested
+	ConfigData *ConfigData `json:"config_data,omitempty"`
+}
+
+// VersionSchema represents the response data for the schema a version was created under
+type VersionSchema struct {
+	Name       string          `json:"name"`
+	Version    int             `json:"version"`
+	SchemaHash string          `json:"schema_hash"`
+	Schema     json.RawMessage `json:"schema"`
+}
+
+// ConfigurationMeta represents a configuration's metadata without its data
+type ConfigurationMeta struct {
+	Name           string    `json:"name"`
+	CurrentVersion int       `json:"current_version"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Sensitive      bool      `json:"sensitive"`
+}
+
+// StorageFootprint represents the storage a configuration's versions consume
+type StorageFootprint struct {
+	Name         string `json:"name"`
+	VersionCount int    `json:"version_count"`
+	TotalBytes   int64  `json:"total_bytes"`
+	// AverageBytesPerVersion is rounded down; zero for a configuration without versions
+	AverageBytesPerVersion int64 `json:"average_bytes_per_version"`
+}
+
+// DefaultConfig represents the response data for setting a default configuration; Name is "*"
+// for the global default
+type DefaultConfig struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// VersionProtection represents the response data for protecting a version
+type VersionProtection struct {
+	Name      string `json:"name"`
+	Version   int    `json:"version"`
+	Protected bool   `json:"protected"`
+}
+
+// ConfigurationSensitivity represents the response data for flagging a configuration as sensitive
+type ConfigurationSensitivity struct {
+	Name      string `json:"name"`
+	Sensitive bool   `json:"sensitive"`
+}
+
+// ConfigurationTags represents the response data for a configuration's tags
+type ConfigurationTags struct {
+	Name string            `json:"name"`
+	Tags map[string]string `json:"tags"`
+}
+
+// TagSelector selects configurations carrying the tag key with the given value, written key:value
+type TagSelector struct {
+	Key   string
+	Value string
+}
+
+// ConfigurationsDeleted represents the response data for bulk configuration deletion
+type ConfigurationsDeleted struct {
+	Deleted int      `json:"deleted"`
+	Names   []string `json:"names"`
+}
+
+// BatchItemResult reports whether one configuration of a bulk create was created, or why not
+type BatchItemResult struct {
+	Index     int          `json:"index"`
+	Name      string       `json:"name"`
+	Created   bool         `json:"created"`
+	Version   int          `json:"version,omitempty"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+	Error     *ErrorDetail `json:"error,omitempty"`
+}
+
+// BatchCreateResult represents the response data for a bulk create, one result per item in request order
+type BatchCreateResult struct {
+	// DryRun is set when nothing was created and the results only tell what would have been
+	DryRun  bool              `json:"dry_run,omitempty"`
+	Atomic  bool              `json:"atomic"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Results []BatchItemResult `json:"results"`
+}
+
+// CurrentVersionRepair describes a configuration whose current_version pointed at a missing version
+type CurrentVersionRepair struct {
+	Name            string `json:"name"`
+	PreviousVersion int    `json:"previous_version"`
+	CurrentVersion  int    `json:"current_version"`
+	Repaired        bool   `json:"repaired"`
+}
+
+// RepairReport represents the response data for the current_version integrity repair
+type RepairReport struct {
+	Repaired int                    `json:"repaired"`
+	Issues   []CurrentVersionRepair `json:"issues"`
+}
+
+// SyncResult represents the response data for a delta sync
+type SyncResult struct {
+	// Updated holds known configurations whose current version is newer than the client's
+	Updated []ConfigurationData `json:"updated"`
+	// New holds configurations the client did not list
+	New []ConfigurationData `json:"new"`
+	// Removed lists known configurations that no longer exist
+	Removed []string `json:"removed"`
+}
+
+// SquashResult represents the response data for squashing a configuration's history
+type SquashResult struct {
+	Name            string `json:"name"`
+	KeepFrom        int    `json:"keep_from"`
+	RemovedVersions int    `json:"removed_versions"`
+	PreviousVersion int    `json:"previous_version"`
+	CurrentVersion  int    `json:"current_version"`
+}
+
+// VersionGap describes a configuration whose versions are not contiguous from 1 to current_version
+type VersionGap struct {
+	Name           string `json:"name"`
+	CurrentVersion int    `json:"current_version"`
+	// Missing lists version numbers between 1 and current_version with no version row
+	Missing []int `json:"missing"`
+	// Beyond lists versions numbered above current_version
+	Beyond []int `json:"beyond"`
+}
+
+// ContiguityReport represents the response data for the version contiguity check
+type ContiguityReport struct {
+	Contiguous bool         `json:"contiguous"`
+	Gaps       []VersionGap `json:"gaps"`
+}
+
+// ChecksumMismatch is a version whose stored data no longer hashes to the checksum recorded
+// when it was written
+type ChecksumMismatch struct {
+	Name             string `json:"name"`
+	Version          int    `json:"version"`
+	StoredChecksum   string `json:"stored_checksum"`
+	ComputedChecksum string `json:"computed_checksum,omitempty"`
+	// DecodeError is set when the stored data could not be decoded to compute a checksum
+	DecodeError string `json:"decode_error,omitempty"`
+}
+
+// ChecksumReport represents the response data for the checksum integrity check
+type ChecksumReport struct {
+	Intact bool `json:"intact"`
+	// CheckedBlobs counts the stored data blobs whose checksums were recomputed
+	CheckedBlobs int `json:"checked_blobs"`
+	// UncheckedVersions counts versions written before checksums were recorded
+	UncheckedVersions int                `json:"unchecked_versions"`
+	Mismatches        []ChecksumMismatch `json:"mismatches"`
+	CheckedAt         time.Time          `json:"checked_at"`
+}
+
+// ConfigBudget is an operator-set limit on the traffic and data size of one configuration;
+// a zero limit leaves that dimension unrestricted
+type ConfigBudget struct {
+	Name                 string    `json:"name"`
+	MaxRequestsPerMinute int       `json:"max_requests_per_minute"`
+	MaxDataBytes         int       `json:"max_data_bytes"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// ConfigBudgetList represents the response data for listing configuration budgets
+type ConfigBudgetList struct {
+	Budgets []ConfigBudget `json:"budgets"`
+}
+
+// AuditPurgeResult represents the response data for purging the access log
+type AuditPurgeResult struct {
+	RetentionDays int       `json:"retention_days"`
+	Cutoff        time.Time `json:"cutoff"`
+	Removed       int64     `json:"removed"`
+}
+
+// PruneResult represents the response data for pruning a configuration's old versions
+type PruneResult struct {
+	Name           string `json:"name"`
+	Keep           int    `json:"keep"`
+	CurrentVersion int    `json:"current_version"`
+	// RemovedVersions lists the deleted version numbers in ascending order
+	RemovedVersions []int `json:"removed_versions"`
+}
+
+// RawVersionData is the stored form of a version's data, for diagnosing encoding problems
+type RawVersionData struct {
+	Name     string `json:"name"`
+	Version  int    `json:"version"`
+	BlobHash string `json:"blob_hash,omitempty"`
+	// Encoding is "plain" for JSON stored as is or "gzip" for compressed data
+	Encoding    string `json:"encoding"`
+	Stored      string `json:"stored"`
+	StoredBytes int    `json:"stored_bytes"`
+	// Decoded is the data after decoding, set only for encoded rows that decode cleanly
+	Decoded     string `json:"decoded,omitempty"`
+	DecodeError string `json:"decode_error,omitempty"`
+}
+
+// SchemaViolationError is one reason a version fails the active schema
+type SchemaViolationError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+	Type  string `json:"type"`
+}
+
+// SchemaViolation is a version whose data no longer validates against the active schema
+type SchemaViolation struct {
+	Name             string                 `json:"name"`
+	Version          int                    `json:"version"`
+	ValidationErrors []SchemaViolationError `json:"validation_errors"`
+}
+
+// SchemaViolationReport represents the response data for the schema violation scan of one page
+// of configurations
+type SchemaViolationReport struct {
+	SchemaHash      string            `json:"schema_hash"`
+	CheckedVersions int               `json:"checked_versions"`
+	Violations      []SchemaViolation `json:"violations"`
+	Pagination      *Pagination       `json:"pagination"`
+}
+
+// SchemaImpactReport represents the response data for checking a candidate schema against the
+// current version of every configuration without activating it
+type SchemaImpactReport struct {
+	SchemaHash       string            `json:"schema_hash"`
+	ActiveSchemaHash string            `json:"active_schema_hash"`
+	Checked          int               `json:"checked"`
+	Passing          int               `json:"passing"`
+	Failing          int               `json:"failing"`
+	Violations       []SchemaViolation `json:"violations"`
+}
+
+// ValidationResult represents the response data for validating candidate data against a
+// configuration's schema
+type ValidationResult struct {
+	Name             string                 `json:"name"`
+	SchemaHash       string                 `json:"schema_hash"`
+	Valid            bool                   `json:"valid"`
+	UnknownFields    []string               `json:"unknown_fields,omitempty"`
+	ValidationErrors []SchemaViolationError `json:"validation_errors"`
+}
+
+// DuplicateCluster groups versions whose data is identical
+type DuplicateCluster struct {
+	Checksum string `json:"checksum"`
+	Versions []int  `json:"versions"`
+}
+
+// DuplicateVersions represents the response data for listing versions that share identical data
+type DuplicateVersions struct {
+	Name     string             `json:"name"`
+	Clusters []DuplicateCluster `json:"clusters"`
+}
+
+// Backup outcomes recorded in the backup log
+const (
+	BackupStatusSucceeded = "succeeded"
+	BackupStatusFailed    = "failed"
+)
+
+// BackupRecord is one scheduled upload of the configuration archive to object storage
+type BackupRecord struct {
+	ID        int       `json:"id"`
+	ObjectKey string    `json:"object_key"`
+	SizeBytes int       `json:"size_bytes"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupList represents the response data for listing backups
+type BackupList struct {
+	Backups []BackupRecord `json:"backups"`
+}
+
+// BackupArchive is the document uploaded by scheduled backups: the current version of every
+// configuration
+type BackupArchive struct {
+	CreatedAt      time.Time               `json:"created_at"`
+	Configurations []ArchivedConfiguration `json:"configurations"`
+}
+
+// ArchivedConfiguration is the current version of one configuration in a backup archive
+type ArchivedConfiguration struct {
+	Name    string          `json:"name"`
+	Version int             `json:"version"`
+	Format  string          `json:"format"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// ExportArchiveFormat is the format_version of export archives written by this release
+const ExportArchiveFormat = 1
+
+// ExportArchive is the full dump served by GET /api/v1/export: every live configuration with
+// all of its versions, re-importable into another environment
+type ExportArchive struct {
+	FormatVersion  int                     `json:"format_version"`
+	ExportedAt     time.Time               `json:"exported_at"`
+	Configurations []ExportedConfiguration `json:"configurations"`
+}
+
+// ExportedConfiguration is one configuration, its sensitive flag, tags and version history in an
+// export archive
+type ExportedConfiguration struct {
+	Name           string            `json:"name"`
+	CurrentVersion int               `json:"current_version"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	Sensitive      bool              `json:"sensitive,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	Versions       []ExportedVersion `json:"versions"`
+}
+
+// ExportedVersion is one version of a configuration in an export archive
+type ExportedVersion struct {
+	Version   int             `json:"version"`
+	Data      json.RawMessage `json:"data"`
+	Format    string          `json:"format"`
+	Original  string          `json:"original,omitempty"`
+	Status    string          `json:"status"`
+	Protected bool            `json:"protected,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	CreatedBy string          `json:"created_by,omitempty"`
+}
+
+// ImportResult reports what an import did with each configuration of the archive
+type ImportResult struct {
+	// DryRun is set when nothing was written and the result only tells what the import would do
+	DryRun      bool     `json:"dry_run,omitempty"`
+	Mode        string   `json:"mode"`
+	Created     []string `json:"created"`
+	Overwritten []string `json:"overwritten"`
+	Skipped     []string `json:"skipped"`
+	// Versions is the number of versions written
+	Versions int `json:"versions"`
+}
+
+// ImportDuplicateVersions lists the version numbers an archived configuration repeats
+type ImportDuplicateVersions struct {
+	Name     string `json:"name"`
+	Versions []int  `json:"versions"`
+}
+
+// ImportVersionGap is a run of version numbers, From through To, missing from an archived
+// configuration below its highest version
+type ImportVersionGap struct {
+	Name string `json:"name"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// ImportVersionFailure is an archived version whose data fails validation
+type ImportVersionFailure struct {
+	Name    string   `json:"name"`
+	Version int      `json:"version"`
+	Errors  []string `json:"errors"`
 }
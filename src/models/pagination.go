@@ -0,0 +1,11 @@
+package models
+
+// Pagination describes one page of a listing and links to its neighbours
+type Pagination struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// Next and Prev are ready-to-use request URLs, null at the boundaries
+	Next *string `json:"next"`
+	Prev *string `json:"prev"`
+}
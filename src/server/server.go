@@ -0,0 +1,67 @@
+// Package server runs the HTTP server until it is told to stop
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Run serves e on address until ctx is done, then stops accepting connections and waits up to
+// shutdownTimeout for in-flight requests to complete, so writes are not cut off mid-transaction.
+// startup, when not nil, runs once the listener is bound, so probes are already answered while
+// it works; the server stops when it fails. Run returns the error that kept the server from
+// starting, or the shutdown error when requests were still running at the timeout.
+func Run(ctx context.Context, e *echo.Echo, address string, shutdownTimeout time.Duration, startup func() error) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- e.Start(address)
+	}()
+
+	// Startup waits for the listener to be bound
+	for e.ListenerAddr() == nil {
+		select {
+		case err := <-serveErr:
+			return err
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	if startup != nil {
+		if err := startup(); err != nil {
+			if shutdownErr := shutdown(e, shutdownTimeout); shutdownErr != nil {
+				log.Printf("Failed to stop server: %v", shutdownErr)
+			}
+			return fmt.Errorf("startup failed: %w", err)
+		}
+	}
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down, waiting up to %s for in-flight requests", shutdownTimeout)
+	if err := shutdown(e, shutdownTimeout); err != nil {
+		return fmt.Errorf("graceful shutdown did not complete: %w", err)
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Println("Server stopped")
+	return nil
+}
+
+// shutdown stops e, waiting up to timeout for in-flight requests
+func shutdown(e *echo.Echo, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return e.Shutdown(ctx)
+}
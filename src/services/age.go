@@ -0,0 +1,49 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// isoDuration formats d as an ISO-8601 duration with day, hour, minute and second fields, such as
+// P2DT3H4M5S. Sub-second precision is dropped and negative durations, from clock skew between
+// writers, are reported as zero.
+func isoDuration(d time.Duration) string {
+	if d < time.Second {
+		return "PT0S"
+	}
+
+	seconds := int64(d / time.Second)
+	days := seconds / 86400
+	hours := seconds % 86400 / 3600
+	minutes := seconds % 3600 / 60
+	seconds %= 60
+
+	var b strings.Builder
+	b.WriteString("P")
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 {
+		b.WriteString("T")
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if seconds > 0 {
+			fmt.Fprintf(&b, "%dS", seconds)
+		}
+	}
+	return b.String()
+}
+
+// ageSeconds returns whole seconds elapsed from createdAt to now, never negative
+func ageSeconds(createdAt, now time.Time) int64 {
+	if age := now.Sub(createdAt); age > 0 {
+		return int64(age / time.Second)
+	}
+	return 0
+}
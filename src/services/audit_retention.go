@@ -0,0 +1,82 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// AuditRetentionNotSetError is returned when a purge is requested without a retention window
+type AuditRetentionNotSetError struct{}
+
+func (e *AuditRetentionNotSetError) Error() string {
+	return "AUDIT_RETENTION_NOT_SET: No audit retention is configured, so nothing may be purged"
+}
+
+// IsAuditRetentionNotSetError checks if an error is an audit-retention-not-set error
+func IsAuditRetentionNotSetError(err error) bool {
+	_, ok := err.(*AuditRetentionNotSetError)
+	return ok
+}
+
+// SetAuditRetention sets how many days of access log entries are kept; zero or less keeps
+// every entry and disables purging
+func (cs *ConfigService) SetAuditRetention(days int) {
+	if days < 0 {
+		days = 0
+	}
+	cs.auditRetentionDays = days
+}
+
+// PurgeAuditLog deletes access log entries older than the retention window. Entries inside the
+// window are never touched, and without a configured retention nothing is deleted.
+func (cs *ConfigService) PurgeAuditLog() (*models.AuditPurgeResult, error) {
+	if cs.auditRetentionDays <= 0 {
+		return nil, &AuditRetentionNotSetError{}
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -cs.auditRetentionDays)
+	removed, err := cs.store.PurgeAccessLog(cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	if removed > 0 {
+		log.Printf("Purged %d access log entries older than %s", removed, cutoff.Format(time.RFC3339))
+	}
+	return &models.AuditPurgeResult{
+		RetentionDays: cs.auditRetentionDays,
+		Cutoff:        cutoff,
+		Removed:       removed,
+	}, nil
+}
+
+// StartAuditPurge purges the access log now and then every interval until stop is called.
+// It does nothing when no retention is configured.
+func (cs *ConfigService) StartAuditPurge(interval time.Duration) (stop func()) {
+	if cs.auditRetentionDays <= 0 || interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := cs.PurgeAuditLog(); err != nil {
+				log.Printf("Scheduled audit purge failed: %v", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// backupListLimit caps the number of backup log entries returned by ListBackups
+const backupListLimit = 100
+
+// Default retry policy of a backup upload
+const (
+	DefaultBackupAttempts   = 3
+	DefaultBackupRetryDelay = 5 * time.Second
+)
+
+// BackupJob periodically uploads the configuration archive to object storage and records
+// each outcome in the backup log. Failures are retried and logged but never stop the job.
+type BackupJob struct {
+	configService *ConfigService
+	uploader      *S3Uploader
+
+	// maxAttempts and retryDelay bound the retries of a single backup; the delay doubles per retry
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+// NewBackupJob creates a backup job using the default retry policy
+func NewBackupJob(configService *ConfigService, uploader *S3Uploader) *BackupJob {
+	return &BackupJob{
+		configService: configService,
+		uploader:      uploader,
+		maxAttempts:   DefaultBackupAttempts,
+		retryDelay:    DefaultBackupRetryDelay,
+	}
+}
+
+// SetRetryPolicy overrides how often and how quickly a failed upload is retried
+func (bj *BackupJob) SetRetryPolicy(maxAttempts int, retryDelay time.Duration) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	bj.maxAttempts = maxAttempts
+	bj.retryDelay = retryDelay
+}
+
+// RunOnce builds the archive, uploads it with retries and records the outcome
+func (bj *BackupJob) RunOnce() models.BackupRecord {
+	now := time.Now().UTC()
+	record := models.BackupRecord{
+		ObjectKey: "config-backups/config-archive-" + now.Format("20060102T150405Z") + ".json",
+		CreatedAt: now,
+	}
+
+	archive, err := bj.configService.BuildBackupArchive(now)
+	if err == nil {
+		record.SizeBytes = len(archive)
+		delay := bj.retryDelay
+		for record.Attempts < bj.maxAttempts {
+			if record.Attempts > 0 {
+				time.Sleep(delay)
+				delay *= 2
+			}
+			record.Attempts++
+			if err = bj.uploader.PutObject(record.ObjectKey, archive, "application/json"); err == nil {
+				break
+			}
+			log.Printf("Backup upload attempt %d/%d failed: %v", record.Attempts, bj.maxAttempts, err)
+		}
+	}
+
+	if err != nil {
+		record.Status = models.BackupStatusFailed
+		record.Error = err.Error()
+		log.Printf("Backup %s failed: %v", record.ObjectKey, err)
+	} else {
+		record.Status = models.BackupStatusSucceeded
+		log.Printf("Backup %s uploaded (%d bytes)", record.ObjectKey, record.SizeBytes)
+	}
+
+	if err := bj.configService.store.RecordBackup(record); err != nil {
+		log.Printf("Failed to record backup %s: %v", record.ObjectKey, err)
+	}
+	return record
+}
+
+// Start runs a backup every interval until stop is called
+func (bj *BackupJob) Start(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				bj.RunOnce()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// BuildBackupArchive serializes the current version of every configuration
+func (cs *ConfigService) BuildBackupArchive(createdAt time.Time) ([]byte, error) {
+	versions, err := cs.store.ListLatestVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	archive := models.BackupArchive{
+		CreatedAt:      createdAt,
+		Configurations: make([]models.ArchivedConfiguration, 0, len(versions)),
+	}
+	for _, version := range versions {
+		archive.Configurations = append(archive.Configurations, models.ArchivedConfiguration{
+			Name:    version.ConfigurationName,
+			Version: version.VersionNumber,
+			Format:  version.Format,
+			Data:    json.RawMessage(version.JsonData),
+		})
+	}
+
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode backup archive: %w", err)
+	}
+	return data, nil
+}
+
+// ListBackups returns the most recent backups, newest first
+func (cs *ConfigService) ListBackups() (*models.BackupList, error) {
+	backups, err := cs.store.ListBackups(backupListLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BackupList{Backups: backups}, nil
+}
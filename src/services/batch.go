@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"config-manager/src/models"
+	"config-manager/src/storage"
+)
+
+// BatchRolledBackError is reported for an item of an atomic bulk create that was valid
+// but not created because another item failed
+type BatchRolledBackError struct {
+	ConfigName string
+}
+
+func (e *BatchRolledBackError) Error() string {
+	return fmt.Sprintf("BATCH_ROLLED_BACK: Configuration '%s' was not created because another item of the atomic batch failed", e.ConfigName)
+}
+
+// IsBatchRolledBackError checks if an error is a rolled back bulk create item
+func IsBatchRolledBackError(err error) bool {
+	_, ok := err.(*BatchRolledBackError)
+	return ok
+}
+
+// CreateConfigsBatch validates every item like CreateConfig and creates the valid ones at
+// version 1 in a single transaction, returning per item the created configuration or the
+// reason it was not created. With atomic set, any failure leaves the batch uncreated and the
+// valid items report BatchRolledBackError. Every version is recorded as created by actor.
+// A dry run creates nothing and returns what would have been created; it checks every item,
+// even atomically, so that each failure is reported.
+func (cs *ConfigService) CreateConfigsBatch(items []models.BatchCreateItem, atomic, dryRun bool, actor string) ([]*models.Configuration, []error, error) {
+	configs := make([]*models.Configuration, len(items))
+	itemErrs := make([]error, len(items))
+
+	var toCreate []storage.NewConfiguration
+	var positions []int
+	for i, item := range items {
+		jsonData, schemaHash, err := cs.prepareVersionData(item.Name, string(item.Data))
+		if err != nil {
+			itemErrs[i] = err
+			continue
+		}
+		toCreate = append(toCreate, storage.NewConfiguration{Name: item.Name, JSONData: jsonData, SchemaHash: schemaHash, CreatedBy: actor})
+		positions = append(positions, i)
+	}
+
+	if atomic && !dryRun && len(toCreate) < len(items) {
+		return configs, rolledBack(items, itemErrs), nil
+	}
+	if len(toCreate) == 0 {
+		return configs, itemErrs, nil
+	}
+
+	names := make([]string, 0, len(toCreate))
+	for _, item := range toCreate {
+		names = append(names, item.Name)
+	}
+	unlock := cs.lockNames(names)
+	defer unlock()
+
+	created, createErrs, err := cs.store.CreateConfigurationsBatch(toCreate, atomic && !dryRun, dryRun)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Only a dry run gets here with items already failed
+	failed := len(toCreate) < len(items)
+	for j, i := range positions {
+		configs[i] = created[j]
+		itemErrs[i] = createErrs[j]
+		failed = failed || createErrs[j] != nil
+	}
+	if atomic && failed {
+		return configs, rolledBack(items, itemErrs), nil
+	}
+	return configs, itemErrs, nil
+}
+
+// rolledBack fills in BatchRolledBackError for every item without an error of its own
+func rolledBack(items []models.BatchCreateItem, itemErrs []error) []error {
+	for i, err := range itemErrs {
+		if err == nil {
+			itemErrs[i] = &BatchRolledBackError{ConfigName: items[i].Name}
+		}
+	}
+	return itemErrs
+}
+
+// lockNames takes the write lock of every named configuration, in name order so concurrent
+// batches cannot deadlock, and returns the function releasing them all
+func (cs *ConfigService) lockNames(names []string) (unlock func()) {
+	sorted := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	unlocks := make([]func(), 0, len(sorted))
+	for _, name := range sorted {
+		unlocks = append(unlocks, cs.writeLocks.Lock(name))
+	}
+	return func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// budgetWindow is the length of the fixed window request budgets are counted over
+const budgetWindow = time.Minute
+
+// ConfigBudgetExceededError is returned when data written to a configuration exceeds its data
+// size budget; request budgets are enforced by middleware through AllowRequest
+type ConfigBudgetExceededError struct {
+	ConfigName string
+	MaxBytes   int
+	Size       int
+}
+
+func (e *ConfigBudgetExceededError) Error() string {
+	return fmt.Sprintf("CONFIG_BUDGET_EXCEEDED: Configuration '%s' allows at most %d bytes of data, got %d", e.ConfigName, e.MaxBytes, e.Size)
+}
+
+// IsConfigBudgetExceededError checks if an error is a configuration budget error
+func IsConfigBudgetExceededError(err error) bool {
+	_, ok := err.(*ConfigBudgetExceededError)
+	return ok
+}
+
+// budgetTracker caches the stored budgets and counts requests per budgeted configuration.
+// Counts are kept per instance, so each instance enforces the full request budget.
+type budgetTracker struct {
+	mu      sync.Mutex
+	loaded  bool
+	budgets map[string]models.ConfigBudget
+	windows map[string]*requestWindow
+}
+
+// requestWindow counts the requests to one configuration since start
+type requestWindow struct {
+	start time.Time
+	count int
+}
+
+// newBudgetTracker creates a tracker that loads budgets on first use
+func newBudgetTracker() *budgetTracker {
+	return &budgetTracker{windows: make(map[string]*requestWindow)}
+}
+
+// budgetFor returns the budget of name, loading the stored budgets on first use.
+// The caller must hold cs.budgets.mu.
+func (cs *ConfigService) budgetFor(name string) (models.ConfigBudget, bool) {
+	bt := cs.budgets
+	if !bt.loaded {
+		budgets, err := cs.store.ListConfigBudgets()
+		if err != nil {
+			// Budgets protect other tenants; failing to load them must not take down every config
+			log.Printf("Failed to load config budgets, not enforcing them: %v", err)
+			return models.ConfigBudget{}, false
+		}
+		bt.budgets = make(map[string]models.ConfigBudget, len(budgets))
+		for _, budget := range budgets {
+			bt.budgets[budget.Name] = budget
+		}
+		bt.loaded = true
+	}
+
+	budget, ok := bt.budgets[name]
+	return budget, ok
+}
+
+// AllowRequest counts a request addressing name against its request budget and reports
+// whether it is allowed, along with the per-minute limit that applies (0 when unlimited)
+func (cs *ConfigService) AllowRequest(name string) (limit int, allowed bool) {
+	bt := cs.budgets
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+
+	budget, ok := cs.budgetFor(name)
+	if !ok || budget.MaxRequestsPerMinute <= 0 {
+		return 0, true
+	}
+
+	now := time.Now()
+	window, ok := bt.windows[name]
+	if !ok || now.Sub(window.start) >= budgetWindow {
+		window = &requestWindow{start: now}
+		bt.windows[name] = window
+	}
+
+	if window.count >= budget.MaxRequestsPerMinute {
+		return budget.MaxRequestsPerMinute, false
+	}
+	window.count++
+	return budget.MaxRequestsPerMinute, true
+}
+
+// checkDataBudget rejects data larger than the data size budget of name
+func (cs *ConfigService) checkDataBudget(name, jsonData string) error {
+	bt := cs.budgets
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+
+	budget, ok := cs.budgetFor(name)
+	if !ok || budget.MaxDataBytes <= 0 || len(jsonData) <= budget.MaxDataBytes {
+		return nil
+	}
+	return &ConfigBudgetExceededError{ConfigName: name, MaxBytes: budget.MaxDataBytes, Size: len(jsonData)}
+}
+
+// SetConfigBudget stores the budget of a configuration name, which need not exist yet, and
+// enforces it from the next request on; a zero limit leaves that dimension unrestricted
+func (cs *ConfigService) SetConfigBudget(name string, maxRequestsPerMinute, maxDataBytes int) (*models.ConfigBudget, error) {
+	budget, err := cs.store.SetConfigBudget(models.ConfigBudget{
+		Name:                 name,
+		MaxRequestsPerMinute: maxRequestsPerMinute,
+		MaxDataBytes:         maxDataBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cs.resetBudgets()
+	return budget, nil
+}
+
+// DeleteConfigBudget removes the budget of a configuration name, leaving it unrestricted
+func (cs *ConfigService) DeleteConfigBudget(name string) error {
+	if err := cs.store.DeleteConfigBudget(name); err != nil {
+		return err
+	}
+
+	cs.resetBudgets()
+	return nil
+}
+
+// ListConfigBudgets lists every stored budget ordered by configuration name
+func (cs *ConfigService) ListConfigBudgets() (*models.ConfigBudgetList, error) {
+	budgets, err := cs.store.ListConfigBudgets()
+	if err != nil {
+		return nil, err
+	}
+	return &models.ConfigBudgetList{Budgets: budgets}, nil
+}
+
+// resetBudgets drops the cached budgets so they are reloaded on the next check; request
+// counts are kept so changing a budget does not hand out a fresh window
+func (cs *ConfigService) resetBudgets() {
+	cs.budgets.mu.Lock()
+	defer cs.budgets.mu.Unlock()
+	cs.budgets.loaded = false
+}
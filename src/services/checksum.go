@@ -0,0 +1,25 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// DataChecksum returns the SHA-256 checksum of json data in canonical form, so that
+// documents differing only in whitespace or key order share a checksum
+func DataChecksum(jsonData string) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(jsonData), &value); err != nil {
+		return "", fmt.Errorf("failed to parse json data: %w", err)
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize json data: %w", err)
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
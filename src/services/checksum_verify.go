@@ -0,0 +1,82 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// Checksums are verified in batches with a pause in between, so a full pass over a large
+// database leaves room for regular traffic
+const (
+	checksumBatchSize  = 100
+	checksumBatchPause = 100 * time.Millisecond
+)
+
+// VerifyChecksums recomputes the checksum of every stored version's data and reports each
+// version whose data no longer matches the checksum recorded when it was written. Versions
+// stored before checksums were recorded are counted but cannot be verified. Nothing is changed.
+func (cs *ConfigService) VerifyChecksums() (*models.ChecksumReport, error) {
+	report := &models.ChecksumReport{Mismatches: []models.ChecksumMismatch{}}
+
+	var afterID int64
+	for {
+		mismatches, lastID, checked, err := cs.store.VerifyBlobChecksums(afterID, checksumBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		report.CheckedBlobs += checked
+		report.Mismatches = append(report.Mismatches, mismatches...)
+		if checked < checksumBatchSize {
+			break
+		}
+		afterID = lastID
+		time.Sleep(checksumBatchPause)
+	}
+
+	unchecked, err := cs.store.CountUnchecksummedVersions()
+	if err != nil {
+		return nil, err
+	}
+	report.UncheckedVersions = unchecked
+	report.Intact = len(report.Mismatches) == 0
+	report.CheckedAt = time.Now().UTC()
+
+	return report, nil
+}
+
+// StartChecksumVerification verifies checksums every interval, logging any mismatch, until
+// stop is called. The first pass runs one interval after start.
+func (cs *ConfigService) StartChecksumVerification(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+
+			report, err := cs.VerifyChecksums()
+			if err != nil {
+				log.Printf("Scheduled checksum verification failed: %v", err)
+				continue
+			}
+			for _, mismatch := range report.Mismatches {
+				log.Printf("Checksum mismatch: %s version %d stored %s computed %s %s",
+					mismatch.Name, mismatch.Version, mismatch.StoredChecksum, mismatch.ComputedChecksum, mismatch.DecodeError)
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -3,6 +3,10 @@ package services
 import (
 	"encoding/json"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
 
 	"config-manager/src/models"
 	"config-manager/src/storage"
@@ -10,33 +14,93 @@ import (
 
 // ConfigService handles all configuration management business logic
 type ConfigService struct {
-	store             *storage.SQLiteStore
+	store             storage.Store
 	validationService *ValidationService
+
+	// accessLogEnabled records reads and writes of sensitive configurations
+	accessLogEnabled bool
+
+	// auditRetentionDays is how long access log entries are kept; zero keeps them forever
+	auditRetentionDays int
+
+	// versionRetention is how many published versions scheduled pruning keeps per
+	// configuration; zero keeps every version
+	versionRetention int
+
+	// budgets holds the per-configuration request and data size budgets
+	budgets *budgetTracker
+
+	// writeLocks serializes writes to the same configuration within this instance,
+	// ahead of the database transaction; reads never take it
+	writeLocks *keyedMutex
 }
 
 // NewConfigService creates a new configuration service
-func NewConfigService(store *storage.SQLiteStore, validationService *ValidationService) *ConfigService {
+func NewConfigService(store storage.Store, validationService *ValidationService) *ConfigService {
 	return &ConfigService{
 		store:             store,
 		validationService: validationService,
+		budgets:           newBudgetTracker(),
+		writeLocks:        newKeyedMutex(),
 	}
 }
 
+// SetAccessLogEnabled turns access logging of sensitive configurations on or off
+func (cs *ConfigService) SetAccessLogEnabled(enabled bool) {
+	cs.accessLogEnabled = enabled
+}
+
 // CreateConfig creates a new configuration with validation (FR-001, FR-002, FR-003)
 //
 // CreateConfig handles the creation of a new configuration.
 // It validates the input JSON against the hardcoded schema and stores the configuration
-// with version 1 in the database.
+// with version 1 in the database, recorded as created by actor.
 //
 // Returns the created Configuration model or an error if validation/storage fails.
-func (cs *ConfigService) CreateConfig(name string, jsonData string) (*models.Configuration, error) {
+func (cs *ConfigService) CreateConfig(name string, jsonData string, actor string) (*models.Configuration, error) {
+	return cs.createConfig(name, jsonData, nil, actor)
+}
+
+// CreateConfigFromText creates a configuration from text in the given format (json, yaml or
+// toml; detected when empty). The text is converted to JSON for validation and kept as authored.
+func (cs *ConfigService) CreateConfigFromText(name, format, text, actor string) (*models.Configuration, error) {
+	jsonData, original, err := convertText(format, text)
+	if err != nil {
+		return nil, err
+	}
+	return cs.createConfig(name, jsonData, original, actor)
+}
+
+// createConfig validates and stores version 1, recording the original text when given
+func (cs *ConfigService) createConfig(name, jsonData string, original *models.OriginalData, actor string) (*models.Configuration, error) {
+	if err := cs.checkDataBudget(name, jsonData); err != nil {
+		return nil, err
+	}
+
+	if err := checkDuplicateKeys(jsonData); err != nil {
+		return nil, err
+	}
+
 	// Validate JSON against hardcoded schema
 	if err := cs.validationService.ValidateConfigData(jsonData); err != nil {
 		return nil, err
 	}
 
+	jsonData, err := deriveEnabled(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaHash, err := cs.recordActiveSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
 	// Create configuration with version 1
-	config, err := cs.store.CreateConfiguration(name, jsonData)
+	config, err := cs.store.CreateConfiguration(name, jsonData, schemaHash, original, actor)
 	if err != nil {
 		return nil, err
 	}
@@ -47,37 +111,196 @@ func (cs *ConfigService) CreateConfig(name string, jsonData string) (*models.Con
 // UpdateConfig updates an existing configuration with new data (FR-004, FR-005)
 //
 // UpdateConfig validates the new configuration data against the schema and updates
-// the configuration, incrementing the version number.
+// the configuration, incrementing the version number. The new version is recorded as created by actor.
 //
 // Returns the updated Configuration model or an error if validation/storage fails.
-func (cs *ConfigService) UpdateConfig(name string, jsonData string) (*models.Configuration, error) {
+func (cs *ConfigService) UpdateConfig(name string, jsonData string, actor string) (*models.Configuration, error) {
+	config, _, err := cs.updateConfig(name, jsonData, nil, UpdateOptions{Actor: actor})
+	return config, err
+}
+
+// UpdateConfigFromText updates a configuration from text in the given format (json, yaml or
+// toml; detected when empty). The text is converted to JSON for validation and kept as authored.
+func (cs *ConfigService) UpdateConfigFromText(name, format, text, actor string) (*models.Configuration, error) {
+	config, _, err := cs.UpdateConfigFromTextWithOptions(name, format, text, UpdateOptions{Actor: actor})
+	return config, err
+}
+
+// UpdateOptions controls how UpdateConfigWithOptions stores a new version
+type UpdateOptions struct {
+	// CreateIfMissing creates a configuration that does not exist yet at version 1
+	CreateIfMissing bool
+	// ExpectedVersion, when non-zero, makes the update a compare-and-swap: it only applies while
+	// the current version is ExpectedVersion, and fails with storage.StaleVersionError otherwise
+	ExpectedVersion int
+	// Actor is recorded as the creator of the new version
+	Actor string
+}
+
+// UpdateConfigWithOptions updates a configuration like UpdateConfig, as controlled by opts. It
+// reports whether the configuration was created.
+func (cs *ConfigService) UpdateConfigWithOptions(name string, jsonData string, opts UpdateOptions) (*models.Configuration, bool, error) {
+	return cs.updateConfig(name, jsonData, nil, opts)
+}
+
+// UpdateConfigFromTextWithOptions is UpdateConfigWithOptions for text in the given format
+func (cs *ConfigService) UpdateConfigFromTextWithOptions(name, format, text string, opts UpdateOptions) (*models.Configuration, bool, error) {
+	jsonData, original, err := convertText(format, text)
+	if err != nil {
+		return nil, false, err
+	}
+	return cs.updateConfig(name, jsonData, original, opts)
+}
+
+// updateConfig validates and stores a new version, recording the original text when given.
+// With opts.CreateIfMissing a configuration that does not exist is created instead, and the
+// returned flag is true; an update expecting a version never creates.
+func (cs *ConfigService) updateConfig(name, jsonData string, original *models.OriginalData, opts UpdateOptions) (*models.Configuration, bool, error) {
+	jsonData, schemaHash, err := cs.prepareVersionData(name, jsonData)
+	if err != nil {
+		return nil, false, err
+	}
+
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	// Update configuration (creates new version)
+	config, err := cs.store.UpdateConfigurationIfCurrent(name, jsonData, schemaHash, original, opts.ExpectedVersion, opts.Actor)
+	if _, missing := err.(*storage.ConfigNotFoundError); missing && opts.CreateIfMissing && opts.ExpectedVersion == 0 {
+		// Holding the write lock, no create through this instance can slip in between
+		config, err = cs.store.CreateConfiguration(name, jsonData, schemaHash, original, opts.Actor)
+		if err != nil {
+			return nil, false, err
+		}
+		return config, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return config, false, nil
+}
+
+// UpdateConfigAsDraft stores new data for an existing configuration as a draft. The data is
+// validated like an update, but the current version stays active until the draft is published.
+// A non-zero expectedVersion must be the current version, as in UpdateOptions. The draft is
+// recorded as created by actor.
+func (cs *ConfigService) UpdateConfigAsDraft(name string, jsonData string, expectedVersion int, actor string) (*models.DraftCreated, error) {
+	return cs.createDraft(name, jsonData, nil, expectedVersion, actor)
+}
+
+// UpdateConfigAsDraftFromText is UpdateConfigAsDraft for text in the given format
+func (cs *ConfigService) UpdateConfigAsDraftFromText(name, format, text string, expectedVersion int, actor string) (*models.DraftCreated, error) {
+	jsonData, original, err := convertText(format, text)
+	if err != nil {
+		return nil, err
+	}
+	return cs.createDraft(name, jsonData, original, expectedVersion, actor)
+}
+
+// createDraft validates and stores a draft version, recording the original text when given
+func (cs *ConfigService) createDraft(name, jsonData string, original *models.OriginalData, expectedVersion int, actor string) (*models.DraftCreated, error) {
+	jsonData, schemaHash, err := cs.prepareVersionData(name, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	draft, currentVersion, err := cs.store.CreateDraftVersion(name, jsonData, schemaHash, original, expectedVersion, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DraftCreated{
+		Name:           draft.ConfigurationName,
+		Version:        draft.VersionNumber,
+		Status:         draft.Status,
+		CurrentVersion: currentVersion,
+		CreatedAt:      draft.CreatedAt,
+	}, nil
+}
+
+// prepareVersionData runs the checks every new version's data must pass and records the active
+// schema, returning the data to store and the hash of the schema it was validated against
+func (cs *ConfigService) prepareVersionData(name, jsonData string) (string, string, error) {
+	if err := cs.checkDataBudget(name, jsonData); err != nil {
+		return "", "", err
+	}
+
+	if err := checkDuplicateKeys(jsonData); err != nil {
+		return "", "", err
+	}
+
 	// Validate JSON against hardcoded schema
 	if err := cs.validationService.ValidateConfigData(jsonData); err != nil {
-		return nil, err
+		return "", "", err
 	}
 
-	// Update configuration (creates new version)
-	config, err := cs.store.UpdateConfiguration(name, jsonData)
+	jsonData, err := deriveEnabled(jsonData)
+	if err != nil {
+		return "", "", err
+	}
+
+	schemaHash, err := cs.recordActiveSchema()
+	if err != nil {
+		return "", "", err
+	}
+
+	return jsonData, schemaHash, nil
+}
+
+// PublishVersion approves a draft, making it the current version served as the latest.
+// Drafts older than the current version are refused rather than discarding newer changes.
+func (cs *ConfigService) PublishVersion(name string, versionNumber int) (*models.VersionPublished, error) {
+	if versionNumber < 1 {
+		return nil, fmt.Errorf("INVALID_VERSION_NUMBER: Version number must be positive integer")
+	}
+
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	config, previousVersion, err := cs.store.PublishVersion(name, versionNumber)
 	if err != nil {
 		return nil, err
 	}
 
-	return config, nil
+	log.Printf("Published draft version %d of configuration %s", versionNumber, name)
+	return &models.VersionPublished{
+		Name:            config.Name,
+		Version:         config.CurrentVersion,
+		PreviousVersion: previousVersion,
+		PublishedAt:     config.UpdatedAt,
+	}, nil
 }
 
 // RollbackConfig rolls back configuration to a previous version (FR-008, FR-009)
 //
 // RollbackConfig reverts the configuration to the specified previous version and
-// creates a new version entry in the database.
+// creates a new version entry in the database, recorded as created by actor.
 //
 // Returns the rolled-back Configuration model or an error if the version is invalid or not found.
-func (cs *ConfigService) RollbackConfig(name string, targetVersion int) (*models.Configuration, error) {
+func (cs *ConfigService) RollbackConfig(name string, targetVersion int, actor string) (*models.Configuration, error) {
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	return cs.rollbackConfig(name, targetVersion, actor)
+}
+
+// rollbackConfig performs a rollback; callers hold the configuration's write lock
+func (cs *ConfigService) rollbackConfig(name string, targetVersion int, actor string) (*models.Configuration, error) {
 	if targetVersion < 1 {
 		return nil, fmt.Errorf("INVALID_VERSION_NUMBER: Version number must be positive integer")
 	}
 
+	schemaHash, err := cs.recordActiveSchema()
+	if err != nil {
+		return nil, err
+	}
+
 	// Rollback configuration (creates new version with target data)
-	config, err := cs.store.RollbackConfiguration(name, targetVersion)
+	config, err := cs.store.RollbackConfiguration(name, targetVersion, schemaHash, actor)
 	if err != nil {
 		return nil, err
 	}
@@ -85,35 +308,283 @@ func (cs *ConfigService) RollbackConfig(name string, targetVersion int) (*models
 	return config, nil
 }
 
+// UndoConfig rolls a configuration back to the version immediately before the current one
+//
+// UndoConfig is a convenience wrapper over RollbackConfig: it creates a new version with
+// the data of version current-1. Configurations still at version 1 have nothing to undo.
+//
+// Returns the rolled-back Configuration model and the version that was restored.
+func (cs *ConfigService) UndoConfig(name string, actor string) (*models.Configuration, int, error) {
+	// Hold the lock across reading the current version and rolling back
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	current, _, err := cs.store.GetLatestConfiguration(name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if current.CurrentVersion <= 1 {
+		return nil, 0, &NothingToUndoError{ConfigName: name}
+	}
+
+	targetVersion := current.CurrentVersion - 1
+	config, err := cs.rollbackConfig(name, targetVersion, actor)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return config, targetVersion, nil
+}
+
+// RedoConfig reverts the latest rollback or undo
+//
+// RedoConfig creates a new version with the data that was current before the latest
+// rollback. It is only possible until the configuration is edited again.
+//
+// Returns the redone Configuration model and the version that was restored.
+func (cs *ConfigService) RedoConfig(name string, actor string) (*models.Configuration, int, error) {
+	schemaHash, err := cs.recordActiveSchema()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	return cs.store.RedoConfiguration(name, schemaHash, actor)
+}
+
 // GetLatestConfig retrieves the latest version of a configuration (FR-006)
 //
 // GetLatestConfig fetches the most recent configuration data for the given name.
 // Returns a ConfigurationData struct containing the latest config and metadata.
 func (cs *ConfigService) GetLatestConfig(name string) (*models.ConfigurationData, error) {
+	return cs.getLatestConfig(name, false, false)
+}
+
+// GetLatestConfigWithDrafts retrieves the newest pending draft of a configuration, or the
+// latest published version when no draft is pending, optionally overlaying schema defaults
+func (cs *ConfigService) GetLatestConfigWithDrafts(name string, applyDefaults bool) (*models.ConfigurationData, error) {
+	return cs.getLatestConfig(name, applyDefaults, true)
+}
+
+// EvaluateConfig evaluates the latest version of a configuration as a feature flag for evalCtx
+//
+// A flag with a rollout_percent is on only for subjects whose key falls in one of the first
+// rollout_percent of 100 hash buckets, seeded by rollout_seed or else the configuration name.
+func (cs *ConfigService) EvaluateConfig(name string, evalCtx models.EvaluationContext) (*models.FlagEvaluation, error) {
+	configData, err := cs.getLatestConfig(name, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	data := configData.ConfigData
+	evaluation := &models.FlagEvaluation{
+		Name:           configData.Name,
+		Version:        configData.Version,
+		Enabled:        data.Enabled,
+		MaxLimit:       data.MaxLimit,
+		RolloutPercent: data.RolloutPercent,
+	}
+
+	if data.RolloutPercent != nil {
+		seed := data.RolloutSeed
+		if seed == "" {
+			seed = configData.Name
+		}
+		inRollout, bucket := rolloutEnabled(*data.RolloutPercent, seed, evalCtx.Key)
+		evaluation.Enabled = data.Enabled && inRollout
+		evaluation.Bucket = bucket
+	}
+
+	return evaluation, nil
+}
+
+// GetConfigMeta retrieves a configuration's metadata without reading any version data
+func (cs *ConfigService) GetConfigMeta(name string) (*models.ConfigurationMeta, error) {
+	return cs.store.GetConfigurationMeta(name)
+}
+
+// GetStorageFootprint reports the bytes stored across all versions of a configuration
+func (cs *ConfigService) GetStorageFootprint(name string) (*models.StorageFootprint, error) {
+	return cs.store.GetStorageFootprint(name)
+}
+
+// GetLatestConfigWithDefaults retrieves the latest version with the active schema's defaults
+// filled in for missing keys. This is a read-time overlay; the stored data is unchanged.
+func (cs *ConfigService) GetLatestConfigWithDefaults(name string) (*models.ConfigurationData, error) {
+	return cs.getLatestConfig(name, true, false)
+}
+
+// overlayDefaults merges schema defaults into jsonData, keeping the stored data when the
+// merged result would not pass the checks a write has to pass
+func (cs *ConfigService) overlayDefaults(jsonData string) (string, error) {
+	merged, err := cs.validationService.ApplyDefaults(jsonData)
+	if err != nil || merged == jsonData {
+		return merged, err
+	}
+
+	if err := cs.validationService.ValidateConfigData(merged); err != nil {
+		log.Printf("Schema defaults produce invalid data, serving stored data: %v", err)
+		return jsonData, nil
+	}
+	if _, err := deriveEnabled(merged); err != nil {
+		log.Printf("Schema defaults produce invalid data, serving stored data: %v", err)
+		return jsonData, nil
+	}
+	return merged, nil
+}
+
+// getLatestConfig retrieves the latest version, optionally overlaying schema defaults; with
+// includeDrafts the newest draft pending above it is served instead
+func (cs *ConfigService) getLatestConfig(name string, applyDefaults, includeDrafts bool) (*models.ConfigurationData, error) {
 	config, version, err := cs.store.GetLatestConfiguration(name)
 	if err != nil {
 		return nil, err
 	}
 
+	if includeDrafts {
+		draft, err := cs.store.LatestDraftVersion(name)
+		if err != nil {
+			return nil, err
+		}
+		if draft > 0 {
+			version, err = cs.store.GetConfigurationVersion(name, draft)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	jsonData := version.JsonData
+	if applyDefaults {
+		jsonData, err = cs.overlayDefaults(jsonData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse JSON data into ConfigData struct
 	var configData models.ConfigData
-	if err := json.Unmarshal([]byte(version.JsonData), &configData); err != nil {
+	if err := json.Unmarshal([]byte(jsonData), &configData); err != nil {
 		return nil, fmt.Errorf("failed to parse configuration data: %w", err)
 	}
 
+	// The checksum covers the data as served, including any overlaid defaults
+	checksum, err := DataChecksum(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.ConfigurationData{
 		Name:       config.Name,
-		Version:    config.CurrentVersion,
+		Version:    version.VersionNumber,
 		ConfigData: configData,
 		CreatedAt:  version.CreatedAt,
+		CreatedBy:  version.CreatedBy,
+		Format:     version.Format,
+		Draft:      version.Status == models.VersionStatusDraft,
+		Original:   version.OriginalData,
+		Checksum:   checksum,
 	}, nil
 }
 
+// GetDefaultConfig returns the stored default for a configuration that does not exist yet,
+// falling back to the global default. The returned data has version 0, and fromGlobal reports
+// whether the global default applied.
+func (cs *ConfigService) GetDefaultConfig(name string) (configData *models.ConfigurationData, fromGlobal bool, err error) {
+	jsonData, appliedName, err := cs.store.GetDefaultConfig(name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data models.ConfigData
+	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
+		return nil, false, fmt.Errorf("failed to parse default configuration data: %w", err)
+	}
+
+	checksum, err := DataChecksum(jsonData)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &models.ConfigurationData{
+		Name:       name,
+		ConfigData: data,
+		Format:     FormatJSON,
+		Checksum:   checksum,
+	}, appliedName == storage.GlobalDefaultName, nil
+}
+
+// SetDefaultConfig validates and stores the default served for name by GET ?default=true
+// while the configuration does not exist; storage.GlobalDefaultName sets the global default
+func (cs *ConfigService) SetDefaultConfig(name, jsonData string) (*models.DefaultConfig, error) {
+	if err := checkDuplicateKeys(jsonData); err != nil {
+		return nil, err
+	}
+	if err := cs.validationService.ValidateConfigData(jsonData); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := deriveEnabled(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.store.SetDefaultConfig(name, jsonData)
+}
+
+// DeleteDefaultConfig removes the default stored for name
+func (cs *ConfigService) DeleteDefaultConfig(name string) error {
+	return cs.store.DeleteDefaultConfig(name)
+}
+
+// ValidateForConfig validates candidate data against the schema the named configuration is
+// validated against, without writing anything
+//
+// Every configuration uses the active schema, so this checks that the configuration exists and
+// returns the active schema's hash along with the *SchemaValidationError for invalid data. The
+// data goes through the same checks as a new version, the data size budget and the status and
+// enabled agreement included, so valid data is data a write would accept.
+func (cs *ConfigService) ValidateForConfig(name, jsonData string) (string, error) {
+	if _, err := cs.store.GetConfigurationMeta(name); err != nil {
+		return "", err
+	}
+
+	if err := cs.checkDataBudget(name, jsonData); err != nil {
+		return "", err
+	}
+
+	if err := checkDuplicateKeys(jsonData); err != nil {
+		return "", err
+	}
+
+	hash, _ := cs.validationService.ActiveSchema()
+	if err := cs.validationService.ValidateConfigData(jsonData); err != nil {
+		return hash, err
+	}
+
+	_, err := deriveEnabled(jsonData)
+	return hash, err
+}
+
 // GetConfigVersion retrieves a specific version of a configuration (FR-007)
 //
 // GetConfigVersion fetches the configuration data for the specified version number.
 // Returns a ConfigurationData struct for the requested version or an error if not found.
+// Drafts are reported as not found; see GetConfigVersionWithDrafts.
 func (cs *ConfigService) GetConfigVersion(name string, versionNumber int) (*models.ConfigurationData, error) {
+	return cs.getConfigVersion(name, versionNumber, false)
+}
+
+// GetConfigVersionWithDrafts retrieves a specific version of a configuration, drafts included
+func (cs *ConfigService) GetConfigVersionWithDrafts(name string, versionNumber int) (*models.ConfigurationData, error) {
+	return cs.getConfigVersion(name, versionNumber, true)
+}
+
+// getConfigVersion retrieves a specific version, hiding drafts unless includeDrafts is set
+func (cs *ConfigService) getConfigVersion(name string, versionNumber int, includeDrafts bool) (*models.ConfigurationData, error) {
 	if versionNumber < 1 {
 		return nil, fmt.Errorf("INVALID_VERSION_NUMBER: Version number must be positive integer")
 	}
@@ -122,6 +593,9 @@ func (cs *ConfigService) GetConfigVersion(name string, versionNumber int) (*mode
 	if err != nil {
 		return nil, err
 	}
+	if version.Status == models.VersionStatusDraft && !includeDrafts {
+		return nil, &storage.VersionNotFoundError{ConfigName: name, Version: versionNumber}
+	}
 
 	// Parse JSON data into ConfigData struct
 	var configData models.ConfigData
@@ -129,31 +603,149 @@ func (cs *ConfigService) GetConfigVersion(name string, versionNumber int) (*mode
 		return nil, fmt.Errorf("failed to parse configuration data: %w", err)
 	}
 
+	checksum, err := DataChecksum(version.JsonData)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.ConfigurationData{
 		Name:       version.ConfigurationName,
 		Version:    version.VersionNumber,
 		ConfigData: configData,
 		CreatedAt:  version.CreatedAt,
+		CreatedBy:  version.CreatedBy,
+		Format:     version.Format,
+		Draft:      version.Status == models.VersionStatusDraft,
+		Original:   version.OriginalData,
+		Checksum:   checksum,
 	}, nil
 }
 
+// ListConfigs lists configurations ordered by sortField, descending when requested, returning
+// the page selected by limit and offset (a zero limit returns every configuration). Paging
+// happens in the database, so only the requested page is read. Soft-deleted configurations are
+// listed only with includeDeleted, and tag selectors restrict the listing to configurations
+// carrying all of them.
+func (cs *ConfigService) ListConfigs(sortField string, descending, includeDeleted bool, tags []models.TagSelector, limit, offset int) (*models.ConfigurationList, error) {
+	configs, total, err := cs.store.ListConfigurations(sortField, descending, includeDeleted, tags, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ConfigurationList{Configurations: configs, Pagination: pagePagination(total, limit, offset)}, nil
+}
+
+// ConfigsExist reports for each name whether the configuration exists, in a single query
+func (cs *ConfigService) ConfigsExist(names []string) (map[string]bool, error) {
+	return cs.store.ConfigurationsExist(names)
+}
+
+// GetVersionSchema retrieves the schema that was active when a version was created
+func (cs *ConfigService) GetVersionSchema(name string, versionNumber int) (*models.VersionSchema, error) {
+	if versionNumber < 1 {
+		return nil, fmt.Errorf("INVALID_VERSION_NUMBER: Version number must be positive integer")
+	}
+
+	hash, schemaJSON, err := cs.store.GetVersionSchema(name, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.VersionSchema{
+		Name:       name,
+		Version:    versionNumber,
+		SchemaHash: hash,
+		Schema:     json.RawMessage(schemaJSON),
+	}, nil
+}
+
+// recordActiveSchema saves the active schema so new versions can reference it by hash
+func (cs *ConfigService) recordActiveSchema() (string, error) {
+	if err := cs.validationService.Unavailable(); err != nil {
+		return "", err
+	}
+	hash, schemaJSON := cs.validationService.ActiveSchema()
+	if err := cs.store.SaveSchema(hash, schemaJSON); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// VerifyActiveSchema fails with SchemaMismatchError unless the active schema's hash is expected,
+// letting clients refuse to write data against a schema they were not built for
+func (cs *ConfigService) VerifyActiveSchema(expected string) error {
+	active, _ := cs.validationService.ActiveSchema()
+	if !strings.EqualFold(expected, active) {
+		return &SchemaMismatchError{Expected: expected, Active: active}
+	}
+	return nil
+}
+
+// ListVersionsOptions controls what ListVersions returns
+type ListVersionsOptions struct {
+	// IncludeDrafts lists unpublished drafts alongside published versions
+	IncludeDrafts bool
+	// IncludeData adds each version's parsed data to the listing
+	IncludeData bool
+	// IncludeAge adds each version's age relative to the server's clock
+	IncludeAge bool
+	// Limit caps the number of versions returned; zero returns every version
+	Limit int
+	// Offset skips that many versions before the returned page
+	Offset int
+}
+
 // ListVersions lists all versions of a configuration (FR-010)
 //
 // ListVersions returns a list of all version numbers and their creation timestamps
-// for the specified configuration name.
+// for the specified configuration name. Drafts are only included when opts.IncludeDrafts is
+// set, and version data only when opts.IncludeData is set.
+// opts.Limit and opts.Offset select a page of the listing.
 // Returns a VersionList struct or an error if the configuration is not found.
-func (cs *ConfigService) ListVersions(name string) (*models.VersionList, error) {
+func (cs *ConfigService) ListVersions(name string, opts ListVersionsOptions) (*models.VersionList, error) {
 	config, versions, err := cs.store.ListVersions(name)
 	if err != nil {
 		return nil, err
 	}
 
+	if !opts.IncludeDrafts {
+		published := versions[:0]
+		for _, version := range versions {
+			if version.Status != models.VersionStatusDraft {
+				published = append(published, version)
+			}
+		}
+		versions = published
+	}
+
+	versions, pagination := paginate(versions, opts.Limit, opts.Offset)
+
+	// Ages are measured from one instant so they are consistent across the listing
+	now := time.Now()
+
 	// Convert to VersionInfo structs
 	versionInfos := make([]models.VersionInfo, len(versions))
 	for i, version := range versions {
 		versionInfos[i] = models.VersionInfo{
 			Version:   version.VersionNumber,
 			CreatedAt: version.CreatedAt,
+			CreatedBy: version.CreatedBy,
+			Protected: version.Protected,
+			Status:    version.Status,
+		}
+
+		if opts.IncludeAge {
+			age := ageSeconds(version.CreatedAt, now)
+			versionInfos[i].AgeSeconds = &age
+			versionInfos[i].Age = isoDuration(time.Duration(age) * time.Second)
+		}
+
+		if opts.IncludeData {
+			var configData models.ConfigData
+			if err := json.Unmarshal([]byte(version.JsonData), &configData); err != nil {
+				return nil, fmt.Errorf("failed to parse configuration data: %w", err)
+			}
+			versionInfos[i].ConfigData = &configData
 		}
 	}
 
@@ -161,5 +753,530 @@ func (cs *ConfigService) ListVersions(name string) (*models.VersionList, error)
 		Name:           config.Name,
 		CurrentVersion: config.CurrentVersion,
 		Versions:       versionInfos,
+		Pagination:     pagination,
+	}, nil
+}
+
+// ProtectVersion marks a version as protected so squashing and deleting refuse to remove or
+// renumber it, guaranteeing a certified version survives every cleanup. Protection is permanent.
+func (cs *ConfigService) ProtectVersion(name string, versionNumber int) (*models.VersionProtection, error) {
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	if err := cs.store.ProtectVersion(name, versionNumber); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Protected version %d of configuration %s", versionNumber, name)
+	return &models.VersionProtection{Name: name, Version: versionNumber, Protected: true}, nil
+}
+
+// SetSensitive flags or unflags a configuration as sensitive
+//
+// Reads and writes of sensitive configurations are recorded in the access log
+// when access logging is enabled.
+func (cs *ConfigService) SetSensitive(name string, sensitive bool) (*models.ConfigurationSensitivity, error) {
+	if err := cs.store.SetConfigurationSensitive(name, sensitive); err != nil {
+		return nil, err
+	}
+
+	return &models.ConfigurationSensitivity{
+		Name:      name,
+		Sensitive: sensitive,
+	}, nil
+}
+
+// IsSensitive reports whether the named configuration is flagged sensitive;
+// unknown configurations are treated as not sensitive
+func (cs *ConfigService) IsSensitive(name string) bool {
+	sensitive, err := cs.store.IsConfigurationSensitive(name)
+	if err != nil {
+		return false
+	}
+	return sensitive
+}
+
+// LogAccess records an access by actor to the named configuration if access logging
+// is enabled and the configuration is flagged sensitive.
+//
+// Failures are logged rather than returned so auditing never breaks the request itself.
+func (cs *ConfigService) LogAccess(name, actor, action string) {
+	if !cs.accessLogEnabled {
+		return
+	}
+
+	sensitive, err := cs.store.IsConfigurationSensitive(name)
+	if err != nil {
+		log.Printf("Failed to check sensitive flag for %s: %v", name, err)
+		return
+	}
+	if !sensitive {
+		return
+	}
+
+	if err := cs.store.RecordAccess(name, actor, action); err != nil {
+		log.Printf("Failed to record %s access to %s by %s: %v", action, name, actor, err)
+	}
+}
+
+// DeleteConfigsByPrefix deletes all configurations whose name starts with prefix
+//
+// DeleteConfigsByPrefix soft-deletes the matching configurations in one transaction, keeping
+// their versions for audit. An empty prefix is rejected to avoid deleting every configuration.
+func (cs *ConfigService) DeleteConfigsByPrefix(prefix string) (*models.ConfigurationsDeleted, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("MISSING_REQUIRED_FIELD: name_prefix must not be empty")
+	}
+
+	names, err := cs.store.DeleteConfigurationsByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ConfigurationsDeleted{
+		Deleted: len(names),
+		Names:   names,
+	}, nil
+}
+
+// DeleteConfig soft-deletes a configuration; its versions are kept and it can be restored
+func (cs *ConfigService) DeleteConfig(name string) (*models.Configuration, error) {
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	config, err := cs.store.SoftDeleteConfiguration(name)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Deleted configuration %s", name)
+	return config, nil
+}
+
+// RestoreConfig makes a soft-deleted configuration visible again at the version it was deleted at
+func (cs *ConfigService) RestoreConfig(name string) (*models.Configuration, error) {
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	config, err := cs.store.RestoreConfiguration(name)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Restored configuration %s", name)
+	return config, nil
+}
+
+// ExportEnvironment returns the latest data of every configuration keyed by name
+//
+// ExportEnvironment produces the single document deploy tooling consumes to configure
+// a whole environment instead of fetching configurations one by one.
+func (cs *ConfigService) ExportEnvironment() (map[string]json.RawMessage, error) {
+	versions, err := cs.store.ListLatestVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	export := make(map[string]json.RawMessage, len(versions))
+	for _, version := range versions {
+		export[version.ConfigurationName] = json.RawMessage(version.JsonData)
+	}
+
+	return export, nil
+}
+
+// SquashConfig collapses a configuration's history so version keepFrom becomes version 1
+//
+// SquashConfig deletes the versions below keepFrom and renumbers the rest, which rewrites version
+// numbers clients may hold; every squash is recorded with its actor in the squash log.
+func (cs *ConfigService) SquashConfig(name string, keepFrom int, actor string) (*models.SquashResult, error) {
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	result, err := cs.store.SquashConfiguration(name, keepFrom, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Squashed configuration %s from version %d by %s: removed %d versions, current version %d -> %d",
+		name, keepFrom, actor, result.RemovedVersions, result.PreviousVersion, result.CurrentVersion)
+	return result, nil
+}
+
+// RenameConfig moves a configuration and its full history to newName
+//
+// RenameConfig holds the write locks of both names, taken in name order so concurrent renames
+// between the same pair cannot deadlock. Fails if newName already exists.
+func (cs *ConfigService) RenameConfig(name, newName string) (*models.ConfigurationMeta, error) {
+	first, second := name, newName
+	if second < first {
+		first, second = second, first
+	}
+	unlockFirst := cs.writeLocks.Lock(first)
+	defer unlockFirst()
+	if second != first {
+		unlockSecond := cs.writeLocks.Lock(second)
+		defer unlockSecond()
+	}
+
+	meta, err := cs.store.RenameConfiguration(name, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Renamed configuration %s to %s", name, newName)
+	return meta, nil
+}
+
+// EachLatestConfig calls fn with the latest data of every configuration, ordered by name, as it
+// is read from a single query. An error from fn stops the iteration and is returned.
+func (cs *ConfigService) EachLatestConfig(fn func(models.ConfigurationData) error) error {
+	return cs.store.EachLatestVersion(func(version models.Version) error {
+		data, err := configurationData(version)
+		if err != nil {
+			return err
+		}
+		return fn(data)
+	})
+}
+
+// ListLatestConfigs returns the latest data of every configuration, ordered by name
+func (cs *ConfigService) ListLatestConfigs() ([]models.ConfigurationData, error) {
+	configs := []models.ConfigurationData{}
+	err := cs.EachLatestConfig(func(data models.ConfigurationData) error {
+		configs = append(configs, data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// configurationData parses a version into the response data of a configuration at that version
+func configurationData(version models.Version) (models.ConfigurationData, error) {
+	var configData models.ConfigData
+	if err := json.Unmarshal([]byte(version.JsonData), &configData); err != nil {
+		return models.ConfigurationData{}, fmt.Errorf("failed to parse configuration data: %w", err)
+	}
+	return models.ConfigurationData{
+		Name:       version.ConfigurationName,
+		Version:    version.VersionNumber,
+		ConfigData: configData,
+		CreatedAt:  version.CreatedAt,
+		CreatedBy:  version.CreatedBy,
+		Format:     version.Format,
 	}, nil
 }
+
+// SyncConfigs returns the configurations that changed since the versions a client last saw
+//
+// SyncConfigs compares known (name to last-seen version) against every current version, read in a
+// single query, and returns the configurations with a newer version, those the client does not know,
+// and the known names that no longer exist.
+func (cs *ConfigService) SyncConfigs(known map[string]int) (*models.SyncResult, error) {
+	versions, err := cs.store.ListLatestVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SyncResult{
+		Updated: []models.ConfigurationData{},
+		New:     []models.ConfigurationData{},
+		Removed: []string{},
+	}
+	seen := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		seen[version.ConfigurationName] = true
+
+		lastSeen, isKnown := known[version.ConfigurationName]
+		if isKnown && version.VersionNumber <= lastSeen {
+			continue
+		}
+
+		data, err := configurationData(version)
+		if err != nil {
+			return nil, err
+		}
+
+		if isKnown {
+			result.Updated = append(result.Updated, data)
+		} else {
+			result.New = append(result.New, data)
+		}
+	}
+
+	for name := range known {
+		if !seen[name] {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+	sort.Strings(result.Removed)
+
+	return result, nil
+}
+
+// RepairCurrentVersions detects and fixes configurations whose current_version has no matching version
+//
+// RepairCurrentVersions resets each drifted current_version to the highest version present and
+// reports every detected issue, including configurations that have no versions and cannot be repaired.
+func (cs *ConfigService) RepairCurrentVersions() (*models.RepairReport, error) {
+	issues, err := cs.store.RepairCurrentVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RepairReport{Issues: issues}
+	for _, issue := range issues {
+		if issue.Repaired {
+			report.Repaired++
+		}
+	}
+
+	return report, nil
+}
+
+// CheckVersionContiguity reports configurations whose versions do not run contiguously from 1
+// to current_version. It only reads; gaps are left for an operator to resolve.
+func (cs *ConfigService) CheckVersionContiguity() (*models.ContiguityReport, error) {
+	gaps, err := cs.store.FindVersionGaps()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ContiguityReport{Contiguous: len(gaps) == 0, Gaps: gaps}, nil
+}
+
+// GetRawVersionData returns a version's data as stored, before decoding
+func (cs *ConfigService) GetRawVersionData(name string, versionNumber int) (*models.RawVersionData, error) {
+	if versionNumber < 1 {
+		return nil, fmt.Errorf("INVALID_VERSION_NUMBER: Version number must be positive integer")
+	}
+	return cs.store.GetRawVersionData(name, versionNumber)
+}
+
+// FindSchemaViolations validates the configurations of one page (ordered by name) against the
+// active schema and reports every version that fails, with its errors
+//
+// Only current versions are checked unless allVersions is set, in which case every live version
+// is. Scanning a page at a time keeps each request bounded however many configurations exist.
+func (cs *ConfigService) FindSchemaViolations(allVersions bool, limit, offset int) (*models.SchemaViolationReport, error) {
+	page, total, err := cs.store.ListConfigurations("name", false, false, nil, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	pagination := pagePagination(total, limit, offset)
+
+	hash, _ := cs.validationService.ActiveSchema()
+	report := &models.SchemaViolationReport{
+		SchemaHash: hash,
+		Violations: []models.SchemaViolation{},
+		Pagination: pagination,
+	}
+
+	for _, config := range page {
+		var versions []models.Version
+		if allVersions {
+			_, versions, err = cs.store.ListVersions(config.Name)
+		} else {
+			var latest *models.Version
+			_, latest, err = cs.store.GetLatestConfiguration(config.Name)
+			if latest != nil {
+				versions = []models.Version{*latest}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, version := range versions {
+			report.CheckedVersions++
+
+			err := cs.validationService.ValidateConfigData(version.JsonData)
+			if err == nil {
+				continue
+			}
+			violation, err := schemaViolation(version, err)
+			if err != nil {
+				return nil, err
+			}
+			report.Violations = append(report.Violations, *violation)
+		}
+	}
+
+	return report, nil
+}
+
+// SchemaImpact checks the current version of every configuration against a candidate schema
+// without activating it, reporting how many would pass and which would fail and why
+func (cs *ConfigService) SchemaImpact(schemaJSON string) (*models.SchemaImpactReport, error) {
+	validate, hash, err := ValidateAgainstSchema(schemaJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	activeHash, _ := cs.validationService.ActiveSchema()
+	report := &models.SchemaImpactReport{
+		SchemaHash:       hash,
+		ActiveSchemaHash: activeHash,
+		Violations:       []models.SchemaViolation{},
+	}
+
+	err = cs.store.EachLatestVersion(func(version models.Version) error {
+		report.Checked++
+
+		validationErr := validate(version.JsonData)
+		if validationErr == nil {
+			report.Passing++
+			return nil
+		}
+
+		violation, err := schemaViolation(version, validationErr)
+		if err != nil {
+			return err
+		}
+		report.Failing++
+		report.Violations = append(report.Violations, *violation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// schemaViolation describes a version that failed validation with validationErr; errors other
+// than schema validation failures are returned as they are
+func schemaViolation(version models.Version, validationErr error) (*models.SchemaViolation, error) {
+	schemaErr, ok := validationErr.(*SchemaValidationError)
+	if !ok {
+		return nil, validationErr
+	}
+
+	violation := &models.SchemaViolation{Name: version.ConfigurationName, Version: version.VersionNumber}
+	for _, fieldErr := range schemaErr.Errors {
+		violation.ValidationErrors = append(violation.ValidationErrors, models.SchemaViolationError{
+			Field: fieldErr.Field,
+			Error: fieldErr.Error,
+			Type:  fieldErr.Type,
+		})
+	}
+	return violation, nil
+}
+
+// FindDuplicateVersions groups the versions of a configuration by data checksum
+//
+// FindDuplicateVersions returns only clusters with more than one version, each listing its versions
+// in ascending order; clusters are ordered by their earliest version.
+func (cs *ConfigService) FindDuplicateVersions(name string) (*models.DuplicateVersions, error) {
+	_, versions, err := cs.store.ListVersions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Versions come newest first; walk oldest first so clusters and their members are ascending
+	clusters := []models.DuplicateCluster{}
+	clusterIndex := make(map[string]int)
+	for i := len(versions) - 1; i >= 0; i-- {
+		checksum, err := DataChecksum(versions[i].JsonData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum version %d: %w", versions[i].VersionNumber, err)
+		}
+
+		if index, ok := clusterIndex[checksum]; ok {
+			clusters[index].Versions = append(clusters[index].Versions, versions[i].VersionNumber)
+			continue
+		}
+		clusterIndex[checksum] = len(clusters)
+		clusters = append(clusters, models.DuplicateCluster{Checksum: checksum, Versions: []int{versions[i].VersionNumber}})
+	}
+
+	duplicates := &models.DuplicateVersions{Name: name, Clusters: []models.DuplicateCluster{}}
+	for _, cluster := range clusters {
+		if len(cluster.Versions) > 1 {
+			duplicates.Clusters = append(duplicates.Clusters, cluster)
+		}
+	}
+
+	return duplicates, nil
+}
+
+// deriveEnabled stores the normalized enabled flag for data given as a status, so consumers
+// reading only enabled keep working. Data without a status is returned unchanged.
+func deriveEnabled(jsonData string) (string, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonData), &fields); err != nil {
+		return "", fmt.Errorf("failed to parse configuration data: %w", err)
+	}
+
+	status, ok := fields["status"].(string)
+	if !ok {
+		return jsonData, nil
+	}
+
+	enabled := status == "on"
+	if given, ok := fields["enabled"].(bool); ok && given != enabled {
+		return "", &SchemaValidationError{
+			Message: "Configuration data does not match required schema",
+			Errors: []ValidationError{{
+				Field:   "enabled",
+				Error:   "enabled must be true exactly when status is \"on\"",
+				Type:    "status_enabled_mismatch",
+				Details: map[string]interface{}{"status": status},
+			}},
+		}
+	}
+	fields["enabled"] = enabled
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode configuration data: %w", err)
+	}
+	return string(normalized), nil
+}
+
+// convertText detects the format of text when none is given and converts it to JSON,
+// returning the original text to store alongside it
+func convertText(format, text string) (string, *models.OriginalData, error) {
+	if format == "" {
+		format = DetectFormat(text)
+	}
+
+	jsonData, err := ConvertToJSON(format, text)
+	if err != nil {
+		return "", nil, err
+	}
+	return jsonData, &models.OriginalData{Format: format, Text: text}, nil
+}
+
+// NothingToUndoError is returned when undoing a configuration that only has version 1
+type NothingToUndoError struct {
+	ConfigName string
+}
+
+func (e *NothingToUndoError) Error() string {
+	return fmt.Sprintf("NOTHING_TO_UNDO: Configuration '%s' has no previous version to undo to", e.ConfigName)
+}
+
+// SchemaMismatchError is returned when a client's expected schema hash differs from the active schema
+type SchemaMismatchError struct {
+	Expected string
+	Active   string
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf("SCHEMA_MISMATCH: Expected schema '%s' but the active schema is '%s'", e.Expected, e.Active)
+}
+
+// IsSchemaMismatchError checks if an error is a schema mismatch error
+func IsSchemaMismatchError(err error) bool {
+	_, ok := err.(*SchemaMismatchError)
+	return ok
+}
+
+// IsNothingToUndoError checks if an error is a nothing-to-undo error
+func IsNothingToUndoError(err error) bool {
+	_, ok := err.(*NothingToUndoError)
+	return ok
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"config-manager/src/models"
+	"config-manager/src/storage"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DiffData computes the structured difference from one JSON document to another over their
+// flattened keys, so a nested change is reported at the leaf that changed
+func DiffData(fromJSON, toJSON string) (*models.DataDiff, error) {
+	from, err := FlattenData([]byte(fromJSON))
+	if err != nil {
+		return nil, err
+	}
+	to, err := FlattenData([]byte(toJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.DataDiff{
+		Added:   map[string]interface{}{},
+		Removed: map[string]interface{}{},
+		Changed: map[string]models.ValueChange{},
+	}
+	for key, oldValue := range from {
+		newValue, ok := to[key]
+		if !ok {
+			diff.Removed[key] = oldValue
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			diff.Changed[key] = models.ValueChange{Old: oldValue, New: newValue}
+		}
+	}
+	for key, newValue := range to {
+		if _, ok := from[key]; !ok {
+			diff.Added[key] = newValue
+		}
+	}
+	diff.Identical = len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0
+
+	return diff, nil
+}
+
+// CompareConfigs diffs the data of two configurations, for example the same logical
+// configuration stored per environment. A version of 0 compares the latest version.
+func (cs *ConfigService) CompareConfigs(nameA string, versionA int, nameB string, versionB int) (*models.ConfigComparison, error) {
+	a, err := cs.publishedVersion(nameA, versionA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := cs.publishedVersion(nameB, versionB)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := DiffData(a.JsonData, b.JsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ConfigComparison{
+		A:        models.DiffSide{Name: nameA, Version: a.VersionNumber},
+		B:        models.DiffSide{Name: nameB, Version: b.VersionNumber},
+		DataDiff: *diff,
+	}, nil
+}
+
+// DiffVersions diffs the data of two versions of a configuration, for example around a rollback
+func (cs *ConfigService) DiffVersions(name string, from, to int) (*models.VersionDiff, error) {
+	fromVersion, toVersion, err := cs.versionPair(name, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := DiffData(fromVersion.JsonData, toVersion.JsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.VersionDiff{Name: name, From: from, To: to, DataDiff: *diff}, nil
+}
+
+// UnifiedDiffVersions renders the change between two versions of a configuration as a unified
+// diff of their pretty-printed data, for pasting into chat or code review. Identical versions
+// give an empty diff.
+func (cs *ConfigService) UnifiedDiffVersions(name string, from, to int) (string, error) {
+	fromVersion, toVersion, err := cs.versionPair(name, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	return UnifiedDiff(fromVersion.JsonData, toVersion.JsonData,
+		fmt.Sprintf("%s@%d", name, from), fmt.Sprintf("%s@%d", name, to))
+}
+
+// versionPair loads the two versions a diff compares
+func (cs *ConfigService) versionPair(name string, from, to int) (*models.Version, *models.Version, error) {
+	fromVersion, err := cs.publishedVersion(name, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	toVersion, err := cs.publishedVersion(name, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fromVersion, toVersion, nil
+}
+
+// UnifiedDiff renders the line difference between two JSON documents, each pretty-printed with
+// sorted keys so only actual changes show up, as a unified diff labelled fromLabel and toLabel
+func UnifiedDiff(fromJSON, toJSON, fromLabel, toLabel string) (string, error) {
+	fromText, err := prettyJSON(fromJSON)
+	if err != nil {
+		return "", err
+	}
+	toText, err := prettyJSON(toJSON)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromText),
+		B:        difflib.SplitLines(toText),
+		FromFile: fromLabel,
+		ToFile:   toLabel,
+		Context:  3,
+	})
+}
+
+// prettyJSON indents a JSON document with its object keys sorted, keeping numbers as written
+func prettyJSON(document string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+
+	pretty, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(pretty), nil
+}
+
+// publishedVersion loads a version of a configuration, or its latest version when
+// versionNumber is 0; drafts are reported as not found
+func (cs *ConfigService) publishedVersion(name string, versionNumber int) (*models.Version, error) {
+	if versionNumber == 0 {
+		_, version, err := cs.store.GetLatestConfiguration(name)
+		return version, err
+	}
+
+	version, err := cs.store.GetConfigurationVersion(name, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status == models.VersionStatusDraft {
+		return nil, &storage.VersionNotFoundError{ConfigName: name, Version: versionNumber}
+	}
+	return version, nil
+}
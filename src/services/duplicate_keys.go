@@ -0,0 +1,110 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DuplicateKeyError is returned when json data repeats a key within one object. encoding/json
+// keeps the last value, so such data would be stored and read back differently than written.
+type DuplicateKeyError struct {
+	// Path locates the object holding the key, "" for the top level
+	Path string
+	Key  string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("DUPLICATE_JSON_KEY: Key '%s' appears more than once", e.Key)
+	}
+	return fmt.Sprintf("DUPLICATE_JSON_KEY: Key '%s' appears more than once in '%s'", e.Key, e.Path)
+}
+
+// IsDuplicateKeyError checks if an error is a duplicate json key error
+func IsDuplicateKeyError(err error) bool {
+	_, ok := err.(*DuplicateKeyError)
+	return ok
+}
+
+// jsonContainer tracks one open object or array while walking json tokens
+type jsonContainer struct {
+	path string
+	// keys is nil for arrays
+	keys map[string]bool
+	// index counts array elements, to build element paths
+	index int
+	// expectKey is set in objects when the next string token is a key
+	expectKey bool
+	// pendingKey is the key whose value is read next
+	pendingKey string
+}
+
+// checkDuplicateKeys walks jsonData token by token and returns a *DuplicateKeyError for the first
+// object, at any depth, that repeats a key. Malformed json is left to the schema validation.
+func checkDuplicateKeys(jsonData string) error {
+	decoder := json.NewDecoder(strings.NewReader(jsonData))
+	decoder.UseNumber()
+
+	var stack []*jsonContainer
+	// childPath is the path of the value about to be read within the innermost container
+	childPath := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		top := stack[len(stack)-1]
+		if top.keys == nil {
+			return top.path + "[" + strconv.Itoa(top.index) + "]"
+		}
+		if top.path == "" {
+			return top.pendingKey
+		}
+		return top.path + "." + top.pendingKey
+	}
+	// valueDone records that a value was read in the innermost container
+	valueDone := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.keys == nil {
+			top.index++
+		} else {
+			top.expectKey = true
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// End of input, or malformed json that schema validation reports
+			return nil
+		}
+
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if key, ok := token.(string); ok && top.keys != nil && top.expectKey {
+				if top.keys[key] {
+					return &DuplicateKeyError{Path: top.path, Key: key}
+				}
+				top.keys[key] = true
+				top.pendingKey = key
+				top.expectKey = false
+				continue
+			}
+		}
+
+		switch token {
+		case json.Delim('{'):
+			stack = append(stack, &jsonContainer{path: childPath(), keys: map[string]bool{}, expectKey: true})
+		case json.Delim('['):
+			stack = append(stack, &jsonContainer{path: childPath()})
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			valueDone()
+		default:
+			valueDone()
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"config-manager/src/models"
+)
+
+// ExportAll calls fn with every configuration and its version history as they are read, for
+// streaming a full export archive. An error from fn stops the export and is returned.
+func (cs *ConfigService) ExportAll(fn func(models.ExportedConfiguration) error) error {
+	return cs.store.ExportAll(fn)
+}
+
+// InvalidArchiveError is returned for an import archive that is not a well-formed export
+type InvalidArchiveError struct {
+	Reason string
+}
+
+func (e *InvalidArchiveError) Error() string {
+	return fmt.Sprintf("INVALID_ARCHIVE: Invalid import archive: %s", e.Reason)
+}
+
+// IsInvalidArchiveError checks if an error is a malformed import archive
+func IsInvalidArchiveError(err error) bool {
+	_, ok := err.(*InvalidArchiveError)
+	return ok
+}
+
+// DuplicateVersionError is returned for an import archive repeating version numbers within a
+// configuration; nothing is imported
+type DuplicateVersionError struct {
+	Duplicates []models.ImportDuplicateVersions
+}
+
+func (e *DuplicateVersionError) Error() string {
+	return fmt.Sprintf("DUPLICATE_VERSION_IN_IMPORT: %d archived configurations repeat version numbers", len(e.Duplicates))
+}
+
+// IsDuplicateVersionError checks if an error is an import archive with repeated version numbers
+func IsDuplicateVersionError(err error) bool {
+	_, ok := err.(*DuplicateVersionError)
+	return ok
+}
+
+// ImportVersionGapError is returned when contiguous histories are required and archived
+// configurations miss version numbers; nothing is imported
+type ImportVersionGapError struct {
+	Gaps []models.ImportVersionGap
+}
+
+func (e *ImportVersionGapError) Error() string {
+	return fmt.Sprintf("VERSION_GAP_IN_IMPORT: %d archived configurations have gaps in their versions", len(e.Gaps))
+}
+
+// IsImportVersionGapError checks if an error is an import archive with gaps in its histories
+func IsImportVersionGapError(err error) bool {
+	_, ok := err.(*ImportVersionGapError)
+	return ok
+}
+
+// ImportValidationError is returned when archived versions fail validation; nothing is imported
+type ImportValidationError struct {
+	Failures []models.ImportVersionFailure
+}
+
+func (e *ImportValidationError) Error() string {
+	return fmt.Sprintf("IMPORT_VALIDATION_FAILED: %d archived versions fail validation", len(e.Failures))
+}
+
+// IsImportValidationError checks if an error is an import rejected for invalid version data
+func IsImportValidationError(err error) bool {
+	_, ok := err.(*ImportValidationError)
+	return ok
+}
+
+// ImportOptions controls how ImportArchive handles an archive
+type ImportOptions struct {
+	// Mode says what happens to a name that already exists (see storage.ImportModeFail and the
+	// other modes)
+	Mode string
+	// DryRun writes nothing and returns what the import would do, or a
+	// storage.ImportConflictsError listing every configuration it would refuse
+	DryRun bool
+	// RequireContiguous refuses configurations whose versions do not run from 1 without gaps
+	RequireContiguous bool
+}
+
+// ImportArchive recreates the configurations of an export archive with their version numbers
+// and timestamps, as controlled by opts. Repeated version numbers reject the whole import with
+// a DuplicateVersionError listing them, before anything else is checked. Every version is then
+// validated against the active schema before writing, and any failure rejects the whole import
+// with an ImportValidationError listing each failing version.
+func (cs *ConfigService) ImportArchive(archive models.ExportArchive, opts ImportOptions) (*models.ImportResult, error) {
+	if duplicates := duplicateVersions(archive); len(duplicates) > 0 {
+		return nil, &DuplicateVersionError{Duplicates: duplicates}
+	}
+	if err := checkArchive(archive); err != nil {
+		return nil, err
+	}
+	if opts.RequireContiguous {
+		if gaps := versionGaps(archive); len(gaps) > 0 {
+			return nil, &ImportVersionGapError{Gaps: gaps}
+		}
+	}
+
+	var failures []models.ImportVersionFailure
+	names := make([]string, 0, len(archive.Configurations))
+	for _, config := range archive.Configurations {
+		names = append(names, config.Name)
+		for _, version := range config.Versions {
+			if errs := cs.validateImportedData(string(version.Data)); len(errs) > 0 {
+				failures = append(failures, models.ImportVersionFailure{Name: config.Name, Version: version.Version, Errors: errs})
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return nil, &ImportValidationError{Failures: failures}
+	}
+
+	schemaHash, err := cs.recordActiveSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := cs.lockNames(names)
+	defer unlock()
+
+	return cs.store.ImportAll(archive.Configurations, schemaHash, opts.Mode, opts.DryRun)
+}
+
+// duplicateVersions lists, per archived configuration, the version numbers appearing more than
+// once, each listed once in ascending order
+func duplicateVersions(archive models.ExportArchive) []models.ImportDuplicateVersions {
+	var duplicates []models.ImportDuplicateVersions
+	for _, config := range archive.Configurations {
+		counts := make(map[int]int, len(config.Versions))
+		for _, version := range config.Versions {
+			counts[version.Version]++
+		}
+		var repeated []int
+		for number, count := range counts {
+			if count > 1 {
+				repeated = append(repeated, number)
+			}
+		}
+		if len(repeated) > 0 {
+			sort.Ints(repeated)
+			duplicates = append(duplicates, models.ImportDuplicateVersions{Name: config.Name, Versions: repeated})
+		}
+	}
+	return duplicates
+}
+
+// versionGaps lists every run of version numbers missing from an archived configuration between
+// 1 and its highest version. The versions must already be checked to be increasing.
+func versionGaps(archive models.ExportArchive) []models.ImportVersionGap {
+	var gaps []models.ImportVersionGap
+	for _, config := range archive.Configurations {
+		expected := 1
+		for _, version := range config.Versions {
+			if version.Version > expected {
+				gaps = append(gaps, models.ImportVersionGap{Name: config.Name, From: expected, To: version.Version - 1})
+			}
+			expected = version.Version + 1
+		}
+	}
+	return gaps
+}
+
+// validateImportedData runs the checks of new version data on archived data and returns the
+// problems found, one message per schema violation
+func (cs *ConfigService) validateImportedData(jsonData string) []string {
+	if err := checkDuplicateKeys(jsonData); err != nil {
+		return []string{err.Error()}
+	}
+
+	err := cs.validationService.ValidateConfigData(jsonData)
+	if err == nil {
+		return nil
+	}
+	schemaErr, ok := err.(*SchemaValidationError)
+	if !ok {
+		return []string{err.Error()}
+	}
+	messages := make([]string, 0, len(schemaErr.Errors))
+	for _, validationErr := range schemaErr.Errors {
+		messages = append(messages, validationErr.Field+": "+validationErr.Error)
+	}
+	return messages
+}
+
+// checkArchive checks that an archive is a well-formed export: a known format version, unique
+// names, valid tags, and per configuration increasing version numbers with the current one
+// published.
+// Versions without a format or status get the defaults of new versions.
+func checkArchive(archive models.ExportArchive) error {
+	if archive.FormatVersion != models.ExportArchiveFormat {
+		return &InvalidArchiveError{Reason: fmt.Sprintf("unsupported format_version %d, expected %d", archive.FormatVersion, models.ExportArchiveFormat)}
+	}
+
+	seen := make(map[string]bool, len(archive.Configurations))
+	for i := range archive.Configurations {
+		config := &archive.Configurations[i]
+		if strings.TrimSpace(config.Name) == "" {
+			return &InvalidArchiveError{Reason: fmt.Sprintf("configuration %d has no name", i)}
+		}
+		if seen[config.Name] {
+			return &InvalidArchiveError{Reason: fmt.Sprintf("configuration '%s' appears more than once", config.Name)}
+		}
+		seen[config.Name] = true
+
+		if len(config.Versions) == 0 {
+			return &InvalidArchiveError{Reason: fmt.Sprintf("configuration '%s' has no versions", config.Name)}
+		}
+		if config.CreatedAt.IsZero() || config.UpdatedAt.IsZero() {
+			return &InvalidArchiveError{Reason: fmt.Sprintf("configuration '%s' is missing created_at or updated_at", config.Name)}
+		}
+		for key, value := range config.Tags {
+			if err := validateTag(key, value); err != nil {
+				return &InvalidArchiveError{Reason: fmt.Sprintf("configuration '%s' has an invalid tag: %v", config.Name, err)}
+			}
+		}
+
+		currentFound := false
+		previous := 0
+		for j := range config.Versions {
+			version := &config.Versions[j]
+			if version.Version <= previous {
+				return &InvalidArchiveError{Reason: fmt.Sprintf("versions of '%s' must be positive and increasing, got %d after %d", config.Name, version.Version, previous)}
+			}
+			previous = version.Version
+
+			if len(version.Data) == 0 {
+				return &InvalidArchiveError{Reason: fmt.Sprintf("version %d of '%s' has no data", version.Version, config.Name)}
+			}
+			if version.CreatedAt.IsZero() {
+				return &InvalidArchiveError{Reason: fmt.Sprintf("version %d of '%s' is missing created_at", version.Version, config.Name)}
+			}
+			if version.Format == "" {
+				version.Format = FormatJSON
+			}
+			switch version.Status {
+			case "":
+				version.Status = models.VersionStatusPublished
+			case models.VersionStatusPublished, models.VersionStatusDraft:
+			default:
+				return &InvalidArchiveError{Reason: fmt.Sprintf("version %d of '%s' has unknown status '%s'", version.Version, config.Name, version.Status)}
+			}
+
+			if version.Version == config.CurrentVersion {
+				currentFound = version.Status == models.VersionStatusPublished
+			}
+		}
+		if !currentFound {
+			return &InvalidArchiveError{Reason: fmt.Sprintf("current_version %d of '%s' is not one of its published versions", config.CurrentVersion, config.Name)}
+		}
+	}
+	return nil
+}
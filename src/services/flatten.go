@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FlattenData flattens JSON data into a single-level map with dot-notation keys: nested
+// objects become a.b.c and array elements a.0, a.1. Leaf values keep their JSON types;
+// empty objects and arrays are kept as leaves so no key disappears.
+func FlattenData(jsonData []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration data: %w", err)
+	}
+
+	flat := make(map[string]interface{})
+	flattenInto(flat, "", value)
+	return flat, nil
+}
+
+// flattenInto adds value to flat under prefix, recursing into non-empty objects and arrays
+func flattenInto(flat map[string]interface{}, prefix string, value interface{}) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			flat[prefix] = v
+			return
+		}
+		for key, child := range v {
+			flattenInto(flat, join(key), child)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			flat[prefix] = v
+			return
+		}
+		for i, child := range v {
+			flattenInto(flat, join(strconv.Itoa(i)), child)
+		}
+	default:
+		flat[prefix] = v
+	}
+}
+
+// EnvLines renders flattened data as sorted `export KEY=value` lines for shells and .env files.
+// Keys are upper-cased with every character outside [A-Z0-9_] replaced by an underscore;
+// strings are single-quoted, while numbers and booleans are written bare.
+func EnvLines(flat map[string]interface{}) string {
+	lines := make([]string, 0, len(flat))
+	for key, value := range flat {
+		lines = append(lines, "export "+envName(key)+"="+envValue(value))
+	}
+	sort.Strings(lines)
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// envName turns a dot-notation key into an environment variable name
+func envName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
+		return "_" + string(name)
+	}
+	return string(name)
+}
+
+// envValue renders a flattened leaf as a shell-safe value
+func envValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "''"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+	default:
+		// Empty objects and arrays
+		encoded, _ := json.Marshal(v)
+		return "'" + string(encoded) + "'"
+	}
+}
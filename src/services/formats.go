@@ -0,0 +1,126 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration data formats; versions are always validated and diffed as JSON
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// InvalidFormatError is returned when configuration text is in an unknown format or cannot be parsed
+type InvalidFormatError struct {
+	Format string
+	Reason string
+}
+
+func (e *InvalidFormatError) Error() string {
+	return fmt.Sprintf("INVALID_CONFIG_FORMAT: Configuration data is not valid %s: %s", e.Format, e.Reason)
+}
+
+// IsInvalidFormatError checks if an error is an invalid format error
+func IsInvalidFormatError(err error) bool {
+	_, ok := err.(*InvalidFormatError)
+	return ok
+}
+
+// DetectFormat guesses the format of configuration text: JSON if it parses as JSON, TOML if
+// its first statement is a key = value pair or a table header, and YAML otherwise
+func DetectFormat(text string) string {
+	if json.Valid([]byte(text)) {
+		return FormatJSON
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			return FormatTOML
+		}
+		equals := strings.Index(line, "=")
+		colon := strings.Index(line, ":")
+		if equals > 0 && (colon < 0 || equals < colon) {
+			return FormatTOML
+		}
+		break
+	}
+
+	return FormatYAML
+}
+
+// ConvertToJSON converts configuration text in the given format to a JSON object
+func ConvertToJSON(format, text string) (string, error) {
+	var fields map[string]interface{}
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal([]byte(text), &fields); err != nil {
+			return "", &InvalidFormatError{Format: format, Reason: err.Error()}
+		}
+		return text, nil
+	case FormatYAML:
+		if err := yaml.Unmarshal([]byte(text), &fields); err != nil {
+			return "", &InvalidFormatError{Format: format, Reason: err.Error()}
+		}
+	case FormatTOML:
+		if _, err := toml.Decode(text, &fields); err != nil {
+			return "", &InvalidFormatError{Format: format, Reason: err.Error()}
+		}
+		if fields != nil {
+			fields = tomlDatesToStrings(fields).(map[string]interface{})
+		}
+	default:
+		return "", &InvalidFormatError{Format: format, Reason: "supported formats are json, yaml and toml"}
+	}
+
+	if fields == nil {
+		return "", &InvalidFormatError{Format: format, Reason: "data must be a mapping of keys to values"}
+	}
+
+	converted, err := json.Marshal(fields)
+	if err != nil {
+		return "", &InvalidFormatError{Format: format, Reason: err.Error()}
+	}
+	return string(converted), nil
+}
+
+// tomlDatesToStrings replaces the TOML dates and times in a decoded value with strings in their
+// TOML form, so a local date stays "2024-05-27" rather than becoming a timestamp in some zone
+func tomlDatesToStrings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = tomlDatesToStrings(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = tomlDatesToStrings(item)
+		}
+	case []map[string]interface{}:
+		for _, table := range v {
+			tomlDatesToStrings(table)
+		}
+	case time.Time:
+		switch v.Location().String() {
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999")
+		case "date-local":
+			return v.Format("2006-01-02")
+		case "time-local":
+			return v.Format("15:04:05.999999999")
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	return value
+}
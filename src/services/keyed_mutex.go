@@ -0,0 +1,47 @@
+package services
+
+import "sync"
+
+// keyedMutex serializes work per key within this process. Entries are reference counted
+// and removed once no goroutine holds or waits for them, so the map stays bounded by the
+// number of keys in use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// keyedLock is the mutex for one key plus the number of goroutines holding or awaiting it
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// newKeyedMutex creates an empty keyed mutex
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: make(map[string]*keyedLock)}
+}
+
+// Lock acquires the lock for key and returns the function that releases it
+func (km *keyedMutex) Lock(key string) (unlock func()) {
+	km.mu.Lock()
+	lock, ok := km.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		km.locks[key] = lock
+	}
+	lock.refs++
+	km.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		km.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(km.locks, key)
+		}
+		km.mu.Unlock()
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// schemaMismatchMessages translates the SchemaValidationError message by language
+var schemaMismatchMessages = map[string]string{
+	"es": "Los datos de configuración no coinciden con el esquema requerido",
+	"fr": "Les données de configuration ne correspondent pas au schéma requis",
+	"id": "Data konfigurasi tidak sesuai dengan skema yang diwajibkan",
+}
+
+// unknownFieldMessages translates the UNKNOWN_CONFIG_FIELD message by language; {fields} lists
+// the offending properties
+var unknownFieldMessages = map[string]string{
+	"":   "Unknown field(s) {fields} not allowed in configuration data; remove them or check for typos",
+	"es": "Campo(s) desconocido(s) {fields} no permitido(s) en los datos de configuración; elimínelos o revise si hay errores tipográficos",
+	"fr": "Champ(s) inconnu(s) {fields} non autorisé(s) dans les données de configuration ; supprimez-les ou vérifiez l'orthographe",
+	"id": "Field tidak dikenal {fields} tidak diizinkan dalam data konfigurasi; hapus atau periksa kesalahan ketik",
+}
+
+// UnknownFieldMessage returns the message naming unknown configuration fields, translated into
+// the first supported language of the Accept-Language header
+func UnknownFieldMessage(fields []string, acceptLanguage string) string {
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = "'" + field + "'"
+	}
+	return renderMessage(unknownFieldMessages[preferredLanguage(acceptLanguage)], map[string]interface{}{
+		"fields": strings.Join(quoted, ", "),
+	})
+}
+
+// validationMessages holds localized templates keyed by language and gojsonschema error type.
+// Placeholders in braces are filled from the error details.
+var validationMessages = map[string]map[string]string{
+	"es": {
+		"required":                        "{property} es obligatorio",
+		"invalid_type":                    "Tipo no válido. Se esperaba: {expected}, se recibió: {given}",
+		"number_gte":                      "Debe ser mayor o igual que {min}",
+		"number_lte":                      "Debe ser menor o igual que {max}",
+		"enum":                            "Debe ser uno de los siguientes: {allowed}",
+		"additional_property_not_allowed": "No se permite la propiedad adicional {property}",
+		"status_enabled_mismatch":         "enabled debe ser true solo cuando status es \"on\" (status: {status})",
+	},
+	"fr": {
+		"required":                        "{property} est obligatoire",
+		"invalid_type":                    "Type invalide. Attendu : {expected}, reçu : {given}",
+		"number_gte":                      "Doit être supérieur ou égal à {min}",
+		"number_lte":                      "Doit être inférieur ou égal à {max}",
+		"enum":                            "Doit être l'une des valeurs suivantes : {allowed}",
+		"additional_property_not_allowed": "La propriété supplémentaire {property} n'est pas autorisée",
+		"status_enabled_mismatch":         "enabled doit valoir true uniquement lorsque status vaut \"on\" (status : {status})",
+	},
+	"id": {
+		"required":                        "{property} wajib diisi",
+		"invalid_type":                    "Tipe tidak valid. Diharapkan: {expected}, diberikan: {given}",
+		"number_gte":                      "Harus lebih besar dari atau sama dengan {min}",
+		"number_lte":                      "Harus lebih kecil dari atau sama dengan {max}",
+		"enum":                            "Harus salah satu dari: {allowed}",
+		"additional_property_not_allowed": "Properti tambahan {property} tidak diizinkan",
+		"status_enabled_mismatch":         "enabled harus true hanya jika status bernilai \"on\" (status: {status})",
+	},
+}
+
+// LocalizeSchemaValidationError returns a copy of err with human messages translated into the
+// first supported language of the Accept-Language header. Error types and fields stay unchanged;
+// English (the gojsonschema default) is used when no supported language is requested.
+func LocalizeSchemaValidationError(err *SchemaValidationError, acceptLanguage string) *SchemaValidationError {
+	language := preferredLanguage(acceptLanguage)
+	if language == "" {
+		return err
+	}
+
+	localized := &SchemaValidationError{
+		Message: schemaMismatchMessages[language],
+		Errors:  make([]ValidationError, len(err.Errors)),
+	}
+
+	for i, validationErr := range err.Errors {
+		localized.Errors[i] = validationErr
+		if template, ok := validationMessages[language][validationErr.Type]; ok {
+			localized.Errors[i].Error = renderMessage(template, validationErr.Details)
+		}
+	}
+
+	return localized
+}
+
+// preferredLanguage picks the first language in an Accept-Language header that has translations
+func preferredLanguage(acceptLanguage string) string {
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.SplitN(entry, ";", 2)[0])
+		primary := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+
+		if primary == "en" {
+			return ""
+		}
+		if _, ok := validationMessages[primary]; ok {
+			return primary
+		}
+	}
+
+	return ""
+}
+
+// renderMessage fills {placeholder} values in template from details
+func renderMessage(template string, details map[string]interface{}) string {
+	message := template
+	for key, value := range details {
+		message = strings.ReplaceAll(message, "{"+key+"}", fmt.Sprint(value))
+	}
+	return message
+}
@@ -0,0 +1,26 @@
+package services
+
+import "config-manager/src/models"
+
+// paginate returns the page of items selected by limit and offset; a zero limit selects
+// everything from offset onwards
+func paginate[T any](items []T, limit, offset int) ([]T, *models.Pagination) {
+	total := len(items)
+	if limit <= 0 {
+		limit = total
+	}
+
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	return items[start:end], &models.Pagination{Total: total, Limit: limit, Offset: offset}
+}
+
+// pagePagination describes a page already selected by the store out of total items, reporting
+// a zero limit as the total like paginate does
+func pagePagination(total, limit, offset int) *models.Pagination {
+	if limit <= 0 {
+		limit = total
+	}
+	return &models.Pagination{Total: total, Limit: limit, Offset: offset}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"fmt"
+
+	"config-manager/src/models"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Patch document types accepted by PatchConfig, named by their media types
+const (
+	// PatchTypeJSONPatch is a JSON Patch (RFC 6902): a list of add/remove/replace/move/copy/test
+	// operations addressed by JSON Pointer
+	PatchTypeJSONPatch = "application/json-patch+json"
+	// PatchTypeMergePatch is a JSON Merge Patch (RFC 7386): an object whose members replace the
+	// matching members of the data, with null removing them
+	PatchTypeMergePatch = "application/merge-patch+json"
+)
+
+// InvalidPatchError is returned for a patch document that cannot be parsed
+type InvalidPatchError struct {
+	Reason string
+}
+
+func (e *InvalidPatchError) Error() string {
+	return fmt.Sprintf("INVALID_PATCH: Invalid patch document: %s", e.Reason)
+}
+
+// IsInvalidPatchError checks if an error is a malformed patch document
+func IsInvalidPatchError(err error) bool {
+	_, ok := err.(*InvalidPatchError)
+	return ok
+}
+
+// PatchConflictError is returned when a JSON Patch does not apply to the current data, e.g.
+// it removes a missing member or one of its test operations fails
+type PatchConflictError struct {
+	ConfigName string
+	Reason     string
+}
+
+func (e *PatchConflictError) Error() string {
+	return fmt.Sprintf("PATCH_CONFLICT: Patch does not apply to configuration '%s': %s", e.ConfigName, e.Reason)
+}
+
+// IsPatchConflictError checks if an error is a patch that does not apply to the current data
+func IsPatchConflictError(err error) bool {
+	_, ok := err.(*PatchConflictError)
+	return ok
+}
+
+// PatchConfig applies a patch of the given type to the data of the current version of a
+// configuration and stores the result as a new version, validated like an update. The patch is
+// applied to the stored data, without drafts or schema defaults. The store only accepts the
+// result while the patched version is still current, so a write landing in between from another
+// instance fails with storage.StaleVersionError rather than being overwritten. The new version is
+// recorded as created by actor.
+func (cs *ConfigService) PatchConfig(name, patchType string, patch []byte, actor string) (*models.Configuration, error) {
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	_, version, err := cs.store.GetLatestConfiguration(name)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := applyPatch(name, patchType, []byte(version.JsonData), patch)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, schemaHash, err := cs.prepareVersionData(name, string(patched))
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.store.UpdateConfigurationIfCurrent(name, jsonData, schemaHash, nil, version.VersionNumber, actor)
+}
+
+// applyPatch applies a patch of the given type to the data of the named configuration
+func applyPatch(name, patchType string, data, patch []byte) ([]byte, error) {
+	switch patchType {
+	case PatchTypeJSONPatch:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, &InvalidPatchError{Reason: err.Error()}
+		}
+		patched, err := operations.Apply(data)
+		if err != nil {
+			// Operations are validated when decoded, so failures here come from the data
+			return nil, &PatchConflictError{ConfigName: name, Reason: err.Error()}
+		}
+		return patched, nil
+	case PatchTypeMergePatch:
+		patched, err := jsonpatch.MergePatch(data, patch)
+		if err != nil {
+			return nil, &InvalidPatchError{Reason: err.Error()}
+		}
+		return patched, nil
+	default:
+		return nil, &InvalidPatchError{Reason: fmt.Sprintf("unsupported patch type '%s'", patchType)}
+	}
+}
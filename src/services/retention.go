@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// InvalidRetentionError is returned for a prune asked to keep fewer than one version
+type InvalidRetentionError struct {
+	Keep int
+}
+
+func (e *InvalidRetentionError) Error() string {
+	return fmt.Sprintf("INVALID_KEEP: keep must be at least 1, got %d", e.Keep)
+}
+
+// IsInvalidRetentionError checks if an error is a prune keeping fewer than one version
+func IsInvalidRetentionError(err error) bool {
+	_, ok := err.(*InvalidRetentionError)
+	return ok
+}
+
+// SetVersionRetention sets how many published versions scheduled pruning keeps per
+// configuration; zero or less keeps every version and disables scheduled pruning
+func (cs *ConfigService) SetVersionRetention(keep int) {
+	if keep < 0 {
+		keep = 0
+	}
+	cs.versionRetention = keep
+}
+
+// PruneVersions deletes all but the keep most recent published versions of a configuration.
+// The current version, the redo target, protected versions and drafts are never deleted, and
+// the remaining versions keep their numbers.
+func (cs *ConfigService) PruneVersions(name string, keep int) (*models.PruneResult, error) {
+	if keep < 1 {
+		return nil, &InvalidRetentionError{Keep: keep}
+	}
+
+	unlock := cs.writeLocks.Lock(name)
+	defer unlock()
+
+	result, err := cs.store.PruneVersions(name, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.RemovedVersions) > 0 {
+		log.Printf("Pruned %d versions of configuration %s, keeping the newest %d", len(result.RemovedVersions), name, keep)
+	}
+	return result, nil
+}
+
+// PruneAllVersions prunes every configuration down to the configured version retention and
+// returns how many versions were deleted. A configuration that fails to prune is logged and
+// skipped so it cannot hold up the others.
+func (cs *ConfigService) PruneAllVersions() (int, error) {
+	if cs.versionRetention <= 0 {
+		return 0, nil
+	}
+
+	configs, _, err := cs.store.ListConfigurations("name", false, false, nil, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, config := range configs {
+		result, err := cs.PruneVersions(config.Name, cs.versionRetention)
+		if err != nil {
+			log.Printf("Failed to prune versions of configuration %s: %v", config.Name, err)
+			continue
+		}
+		removed += len(result.RemovedVersions)
+	}
+	return removed, nil
+}
+
+// StartVersionPruning prunes every configuration now and then every interval until stop is
+// called. It does nothing when no version retention is configured.
+func (cs *ConfigService) StartVersionPruning(interval time.Duration) (stop func()) {
+	if cs.versionRetention <= 0 || interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := cs.PruneAllVersions(); err != nil {
+				log.Printf("Scheduled version pruning failed: %v", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
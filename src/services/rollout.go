@@ -0,0 +1,27 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// rolloutBuckets is the number of buckets keys are spread over; one bucket per percent
+const rolloutBuckets = 100
+
+// rolloutBucket places key in a bucket from 0 to 99. The bucket depends only on the seed and
+// the key, so a subject stays in the same bucket across requests and replicas, and raising the
+// rollout percentage only ever adds subjects.
+func rolloutBucket(seed, key string) int {
+	sum := sha256.Sum256([]byte(seed + ":" + key))
+	return int(binary.BigEndian.Uint64(sum[:8]) % rolloutBuckets)
+}
+
+// rolloutEnabled reports whether a flag that is enabled is on for key under a rollout of percent.
+// Without a key there is no bucket to place, so only a full rollout counts as on.
+func rolloutEnabled(percent int, seed, key string) (enabled bool, bucket *int) {
+	if key == "" {
+		return percent >= rolloutBuckets, nil
+	}
+	b := rolloutBucket(seed, key)
+	return b < percent, &b
+}
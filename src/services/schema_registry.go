@@ -0,0 +1,110 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// defaultRegistryTimeout bounds a single schema fetch from the registry
+const defaultRegistryTimeout = 10 * time.Second
+
+// NewRegistryValidationService creates a validation service whose schema is fetched
+// from an external schema registry over HTTP.
+//
+// The schema is fetched and compiled once at startup; if the registry is unavailable
+// or serves an invalid schema, an error is returned so the caller can fail fast or fall back to
+// NewUnavailableValidationService.
+func NewRegistryValidationService(registryURL string) (*ValidationService, error) {
+	vs := &ValidationService{
+		registryURL: registryURL,
+		httpClient:  &http.Client{Timeout: defaultRegistryTimeout},
+	}
+
+	if err := vs.RefreshSchema(); err != nil {
+		return nil, err
+	}
+
+	return vs, nil
+}
+
+// RefreshSchema fetches the schema from the registry and swaps it in once it compiles.
+// On failure the previously cached schema stays active.
+func (vs *ValidationService) RefreshSchema() error {
+	if vs.registryURL == "" {
+		return nil
+	}
+
+	schemaJSON, err := vs.fetchRegistrySchema()
+	if err != nil {
+		return err
+	}
+
+	schema, err := compileSchema(schemaJSON)
+	if err != nil {
+		return err
+	}
+
+	vs.mu.Lock()
+	vs.schema = schema
+	vs.schemaJSON = schemaJSON
+	vs.schemaHash = schemaHash(schemaJSON)
+	vs.unavailableReason = ""
+	vs.mu.Unlock()
+
+	return nil
+}
+
+// StartSchemaRefresh periodically refreshes the schema from the registry until stop is called.
+// Refresh failures are logged and the last-good schema is kept.
+func (vs *ValidationService) StartSchemaRefresh(interval time.Duration) (stop func()) {
+	if vs.registryURL == "" || interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := vs.RefreshSchema(); err != nil {
+					log.Printf("Failed to refresh schema from registry, keeping last-good schema: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// fetchRegistrySchema downloads the raw schema document from the registry
+func (vs *ValidationService) fetchRegistrySchema() (string, error) {
+	resp, err := vs.httpClient.Get(vs.registryURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch schema from registry: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Failed to close registry response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("schema registry returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read schema from registry: %w", err)
+	}
+
+	return string(body), nil
+}
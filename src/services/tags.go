@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"config-manager/src/models"
+)
+
+// maxTagValueLength is the longest tag value accepted
+const maxTagValueLength = 255
+
+// tagKeyPattern matches valid tag keys: letters, digits, '_', '-' and '.', at most 63 long.
+// Keys cannot contain ':', which separates key and value in tag selectors.
+var tagKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,62}$`)
+
+// InvalidTagError is returned for a malformed tag or tag selector
+type InvalidTagError struct {
+	Tag    string
+	Reason string
+}
+
+func (e *InvalidTagError) Error() string {
+	return fmt.Sprintf("INVALID_TAG: Invalid tag '%s': %s", e.Tag, e.Reason)
+}
+
+// IsInvalidTagError checks if an error is a malformed tag or tag selector
+func IsInvalidTagError(err error) bool {
+	_, ok := err.(*InvalidTagError)
+	return ok
+}
+
+// validateTag checks a tag key and value
+func validateTag(key, value string) error {
+	if !tagKeyPattern.MatchString(key) {
+		return &InvalidTagError{Tag: key, Reason: "keys must be 1-63 letters, digits, '_', '-' or '.', starting with a letter or digit"}
+	}
+	if value == "" || len(value) > maxTagValueLength {
+		return &InvalidTagError{Tag: key + ":" + value, Reason: fmt.Sprintf("values must be 1-%d bytes long", maxTagValueLength)}
+	}
+	return nil
+}
+
+// ParseTagSelectors parses tag selectors written key:value, as given in ?tag= filters
+func ParseTagSelectors(raw []string) ([]models.TagSelector, error) {
+	selectors := make([]models.TagSelector, 0, len(raw))
+	for _, selector := range raw {
+		key, value, ok := strings.Cut(selector, ":")
+		if !ok {
+			return nil, &InvalidTagError{Tag: selector, Reason: "selectors must be written key:value"}
+		}
+		if err := validateTag(key, value); err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, models.TagSelector{Key: key, Value: value})
+	}
+	return selectors, nil
+}
+
+// SetTags sets tags on a configuration, replacing the value of keys it already has and keeping
+// its other tags, and returns all of its tags
+func (cs *ConfigService) SetTags(name string, tags map[string]string) (*models.ConfigurationTags, error) {
+	for key, value := range tags {
+		if err := validateTag(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := cs.store.SetConfigurationTags(name, tags)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ConfigurationTags{Name: name, Tags: current}, nil
+}
+
+// GetTags retrieves the tags of a configuration
+func (cs *ConfigService) GetTags(name string) (*models.ConfigurationTags, error) {
+	tags, err := cs.store.GetConfigurationTags(name)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ConfigurationTags{Name: name, Tags: tags}, nil
+}
+
+// RemoveTag removes the tag with the given key from a configuration
+func (cs *ConfigService) RemoveTag(name, key string) error {
+	return cs.store.DeleteConfigurationTag(name, key)
+}
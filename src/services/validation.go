@@ -1,45 +1,147 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sync"
 
 	"github.com/xeipuuv/gojsonschema"
 )
 
 // ValidationService handles JSON schema validation for configuration data
 type ValidationService struct {
+	mu     sync.RWMutex
 	schema *gojsonschema.Schema
+
+	// schemaJSON and schemaHash identify the active schema document so versions can record it
+	schemaJSON string
+	schemaHash string
+
+	// registryURL is set when the schema is sourced from an external schema registry
+	registryURL string
+	httpClient  *http.Client
+
+	// unavailableReason explains why no schema is active; empty once one is
+	unavailableReason string
 }
 
 // ConfigDataSchema Hardcoded JSON schema that all configuration data must conform to
+// Either enabled or status must be given; the service derives enabled from status.
+// rollout_percent turns enabled into a gradual rollout over subjects, bucketed by rollout_seed.
 const ConfigDataSchema = `{
   "type": "object",
   "properties": {
     "max_limit": {"type": "integer", "minimum": 0},
-    "enabled": {"type": "boolean"}
+    "enabled": {"type": "boolean"},
+    "status": {"type": "string", "enum": ["on", "off", "scheduled"]},
+    "rollout_percent": {"type": "integer", "minimum": 0, "maximum": 100},
+    "rollout_seed": {"type": "string", "minLength": 1}
   },
-  "required": ["max_limit", "enabled"],
+  "required": ["max_limit"],
+  "anyOf": [
+    {"required": ["enabled"]},
+    {"required": ["status"]}
+  ],
   "additionalProperties": false
 }`
 
 // NewValidationService creates a new validation service with the hardcoded schema
 func NewValidationService() (*ValidationService, error) {
-	schemaLoader := gojsonschema.NewStringLoader(ConfigDataSchema)
-	schema, err := gojsonschema.NewSchema(schemaLoader)
+	schema, err := compileSchema(ConfigDataSchema)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create JSON schema: %w", err)
+		return nil, err
 	}
 
 	return &ValidationService{
-		schema: schema,
+		schema:     schema,
+		schemaJSON: ConfigDataSchema,
+		schemaHash: schemaHash(ConfigDataSchema),
 	}, nil
 }
 
-// ValidateConfigData validates the provided JSON data against the hardcoded schema
+// NewUnavailableValidationService creates a validation service without a usable schema, for
+// serving reads when the schema failed to load at startup. Validation fails with
+// ValidationUnavailableError until a schema is active; a registry-sourced service recovers on its
+// next successful refresh.
+func NewUnavailableValidationService(registryURL string, cause error) *ValidationService {
+	vs := &ValidationService{
+		registryURL:       registryURL,
+		unavailableReason: cause.Error(),
+	}
+	if registryURL != "" {
+		vs.httpClient = &http.Client{Timeout: defaultRegistryTimeout}
+	}
+	return vs
+}
+
+// schemaHash returns the SHA-256 hash identifying a schema document
+func schemaHash(schemaJSON string) string {
+	sum := sha256.Sum256([]byte(schemaJSON))
+	return hex.EncodeToString(sum[:])
+}
+
+// compileSchema compiles a JSON schema document
+func compileSchema(schemaJSON string) (*gojsonschema.Schema, error) {
+	schemaLoader := gojsonschema.NewStringLoader(schemaJSON)
+	schema, err := gojsonschema.NewSchema(schemaLoader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JSON schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+// currentSchema returns the compiled schema currently in use, or a ValidationUnavailableError
+// when there is none
+func (vs *ValidationService) currentSchema() (*gojsonschema.Schema, error) {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+	if vs.schema == nil {
+		return nil, &ValidationUnavailableError{Reason: vs.unavailableReason}
+	}
+	return vs.schema, nil
+}
+
+// Unavailable returns a ValidationUnavailableError while no schema is active, and nil otherwise
+func (vs *ValidationService) Unavailable() error {
+	_, err := vs.currentSchema()
+	return err
+}
+
+// ActiveSchema returns the hash and raw JSON of the schema currently in use
+func (vs *ValidationService) ActiveSchema() (hash string, schemaJSON string) {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+	return vs.schemaHash, vs.schemaJSON
+}
+
+// ValidateConfigData validates the provided JSON data against the active schema
 func (vs *ValidationService) ValidateConfigData(jsonData string) error {
+	schema, err := vs.currentSchema()
+	if err != nil {
+		return err
+	}
+	return validateAgainst(schema, jsonData)
+}
+
+// ValidateAgainstSchema returns a validator for a candidate schema document, so data can be
+// checked against it without making it the active schema
+func ValidateAgainstSchema(schemaJSON string) (validate func(jsonData string) error, hash string, err error) {
+	schema, err := compileSchema(schemaJSON)
+	if err != nil {
+		return nil, "", &InvalidSchemaError{Reason: err.Error()}
+	}
+	return func(jsonData string) error { return validateAgainst(schema, jsonData) }, schemaHash(schemaJSON), nil
+}
+
+// validateAgainst validates JSON data against a compiled schema, returning a
+// SchemaValidationError listing every failure
+func validateAgainst(schema *gojsonschema.Schema, jsonData string) error {
 	documentLoader := gojsonschema.NewStringLoader(jsonData)
-	result, err := vs.schema.Validate(documentLoader)
+	result, err := schema.Validate(documentLoader)
 	if err != nil {
 		return fmt.Errorf("schema validation error: %w", err)
 	}
@@ -48,8 +150,10 @@ func (vs *ValidationService) ValidateConfigData(jsonData string) error {
 		var validationErrors []ValidationError
 		for _, desc := range result.Errors() {
 			validationErrors = append(validationErrors, ValidationError{
-				Field: desc.Field(),
-				Error: desc.Description(),
+				Field:   desc.Field(),
+				Error:   desc.Description(),
+				Type:    desc.Type(),
+				Details: desc.Details(),
 			})
 		}
 
@@ -78,10 +182,56 @@ func (vs *ValidationService) ValidateAndParseConfigData(jsonData string) (map[st
 	return configData, nil
 }
 
+// ApplyDefaults fills keys missing from jsonData with the "default" values of the active schema's
+// top-level properties. The merged data is not validated.
+func (vs *ValidationService) ApplyDefaults(jsonData string) (string, error) {
+	_, schemaJSON := vs.ActiveSchema()
+	if schemaJSON == "" {
+		// Without a schema there are no defaults; reads must keep working
+		return jsonData, nil
+	}
+
+	var schema struct {
+		Properties map[string]struct {
+			Default json.RawMessage `json:"default"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		return "", fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(jsonData), &fields); err != nil {
+		return "", fmt.Errorf("failed to parse configuration data: %w", err)
+	}
+
+	applied := false
+	for key, property := range schema.Properties {
+		if _, ok := fields[key]; ok || property.Default == nil {
+			continue
+		}
+		fields[key] = property.Default
+		applied = true
+	}
+	if !applied {
+		return jsonData, nil
+	}
+
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode configuration data: %w", err)
+	}
+	return string(merged), nil
+}
+
 // ValidationError represents a single validation error
 type ValidationError struct {
 	Field string `json:"field"`
 	Error string `json:"error"`
+	// Type is the stable gojsonschema error type (e.g. "required", "invalid_type")
+	Type string `json:"type"`
+	// Details holds the error parameters used to render localized messages
+	Details map[string]interface{} `json:"-"`
 }
 
 // SchemaValidationError represents schema validation failure with details
@@ -94,8 +244,53 @@ func (e *SchemaValidationError) Error() string {
 	return e.Message
 }
 
+// UnknownFields returns the offending property names when every error is a property not allowed
+// by the schema (additionalProperties: false), and nil otherwise
+func (e *SchemaValidationError) UnknownFields() []string {
+	var fields []string
+	for _, validationErr := range e.Errors {
+		property, ok := validationErr.Details["property"].(string)
+		if validationErr.Type != "additional_property_not_allowed" || !ok {
+			return nil
+		}
+		fields = append(fields, property)
+	}
+	return fields
+}
+
 // IsSchemaValidationError checks if an error is a schema validation error
 func IsSchemaValidationError(err error) bool {
 	_, ok := err.(*SchemaValidationError)
 	return ok
 }
+
+// InvalidSchemaError is returned when a candidate schema document cannot be compiled
+type InvalidSchemaError struct {
+	Reason string
+}
+
+func (e *InvalidSchemaError) Error() string {
+	return "INVALID_SCHEMA: " + e.Reason
+}
+
+// IsInvalidSchemaError checks if an error is an invalid-schema error
+func IsInvalidSchemaError(err error) bool {
+	_, ok := err.(*InvalidSchemaError)
+	return ok
+}
+
+// ValidationUnavailableError is returned for writes while no schema is active because it failed
+// to load; reads keep working
+type ValidationUnavailableError struct {
+	Reason string
+}
+
+func (e *ValidationUnavailableError) Error() string {
+	return "VALIDATION_UNAVAILABLE: Configuration schema is unavailable, writes are rejected until it loads: " + e.Reason
+}
+
+// IsValidationUnavailableError checks if an error is a validation-unavailable error
+func IsValidationUnavailableError(err error) bool {
+	_, ok := err.(*ValidationUnavailableError)
+	return ok
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"config-manager/src/models"
+)
+
+// RecordBackup appends the outcome of a backup upload to the backup log
+func (s *sqlStore) RecordBackup(record models.BackupRecord) error {
+	query := `
+		INSERT INTO backup_log (object_key, size_bytes, status, attempts, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := s.db.Exec(query, record.ObjectKey, record.SizeBytes, record.Status, record.Attempts,
+		nullIfEmpty(record.Error), formatTimestamp(record.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to record backup: %w", err)
+	}
+
+	return nil
+}
+
+// ListBackups retrieves the most recent backup log entries, newest first
+func (s *sqlStore) ListBackups(limit int) ([]models.BackupRecord, error) {
+	query := `
+		SELECT id, object_key, size_bytes, status, attempts, error, created_at
+		FROM backup_log
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?`
+
+	rows, err := s.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query backups: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	backups := []models.BackupRecord{}
+	for rows.Next() {
+		var record models.BackupRecord
+		var backupError sql.NullString
+		var createdAtStr string
+		err := rows.Scan(&record.ID, &record.ObjectKey, &record.SizeBytes, &record.Status,
+			&record.Attempts, &backupError, &createdAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan backup: %w", err)
+		}
+
+		record.CreatedAt, err = parseTimestamp(createdAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse backup created_at: %w", err)
+		}
+		record.Error = backupError.String
+
+		backups = append(backups, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating backups: %w", err)
+	}
+
+	return backups, nil
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// NewConfiguration is one configuration to create in a batch, with already validated data
+type NewConfiguration struct {
+	Name       string
+	JSONData   string
+	SchemaHash string
+	CreatedBy  string
+}
+
+// CreateConfigurationsBatch creates every configuration of items at version 1 in a single
+// transaction and returns, per item, either the created configuration or the reason it was not
+// created. Each item is inserted under its own savepoint, so a failed item leaves no trace.
+// When atomic is set the first failure rolls back the whole batch: nothing is created and
+// only the failed item carries an error. Otherwise the remaining items are still created.
+// A dry run rolls the transaction back instead of committing, so the results only tell what
+// would have been created.
+func (s *sqlStore) CreateConfigurationsBatch(items []NewConfiguration, atomic, dryRun bool) ([]*models.Configuration, []error, error) {
+	configs := make([]*models.Configuration, len(items))
+	itemErrs := make([]error, len(items))
+
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	now := time.Now()
+	var created []string
+	for i, item := range items {
+		if _, err := tx.Exec(`SAVEPOINT batch_item`); err != nil {
+			return nil, nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		if err := s.insertConfiguration(tx, item.Name, item.JSONData, item.SchemaHash, nil, item.CreatedBy, now); err != nil {
+			if atomic {
+				itemErrs[i] = err
+				return make([]*models.Configuration, len(items)), itemErrs, nil
+			}
+			if _, rbErr := tx.Exec(`ROLLBACK TO batch_item`); rbErr != nil {
+				return nil, nil, fmt.Errorf("failed to roll back to savepoint: %w", rbErr)
+			}
+			itemErrs[i] = err
+		} else {
+			configs[i] = &models.Configuration{
+				Name:           item.Name,
+				CurrentVersion: 1,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			created = append(created, item.Name)
+		}
+
+		if _, err := tx.Exec(`RELEASE batch_item`); err != nil {
+			return nil, nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	if dryRun {
+		return configs, itemErrs, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(created...)
+
+	return configs, itemErrs, nil
+}
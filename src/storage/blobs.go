@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"config-manager/src/models"
+)
+
+// versionDataColumn reads a version's data from its blob, falling back to the inline json_data
+// of rows written before content-addressed storage; queries select it with versionBlobJoin
+const (
+	versionDataColumn = "COALESCE(b.data, v.json_data)"
+	versionBlobJoin   = "LEFT JOIN version_blobs b ON b.id = v.blob_id"
+)
+
+// dataHash returns the SHA-256 of json data in canonical form, so documents differing only in
+// whitespace or key order share a blob. Numbers keep their literal text to avoid float rounding.
+func dataHash(jsonData string) string {
+	canonical := []byte(jsonData)
+
+	decoder := json.NewDecoder(bytes.NewReader(canonical))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err == nil {
+		if marshalled, err := json.Marshal(value); err == nil {
+			canonical = marshalled
+		}
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// storeBlob returns the id of the blob holding jsonData, reusing an existing blob with the same
+// canonical data or inserting one encoded for the current storage setting
+func (s *sqlStore) storeBlob(tx *sql.Tx, name, jsonData string) (int64, error) {
+	hash := dataHash(jsonData)
+	if id, ok, err := findBlob(tx, hash); err != nil || ok {
+		return id, err
+	}
+
+	storedData, err := s.encodeJSONData(name, jsonData)
+	if err != nil {
+		return 0, err
+	}
+	return insertBlob(tx, hash, storedData)
+}
+
+// findBlob looks up the blob with the given hash
+func findBlob(tx *sql.Tx, hash string) (int64, bool, error) {
+	var id int64
+	err := tx.QueryRow(`SELECT id FROM version_blobs WHERE hash = ?`, hash).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to look up version blob: %w", err)
+	}
+	return id, true, nil
+}
+
+// insertBlob stores already encoded data under hash
+func insertBlob(tx *sql.Tx, hash, storedData string) (int64, error) {
+	var id int64
+	err := tx.QueryRow(`INSERT INTO version_blobs (hash, data) VALUES (?, ?) RETURNING id`, hash, storedData).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert version blob: %w", err)
+	}
+	return id, nil
+}
+
+// deleteUnreferencedBlobs removes blobs no version points to any more
+func deleteUnreferencedBlobs(tx *sql.Tx) error {
+	query := `DELETE FROM version_blobs WHERE id NOT IN (SELECT blob_id FROM versions WHERE blob_id IS NOT NULL)`
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("failed to delete unreferenced version blobs: %w", err)
+	}
+	return nil
+}
+
+// backfillVersionBlobs moves the inline json_data of versions written before content-addressed
+// storage into shared blobs. Stored values are kept as they are, compressed or not, and the
+// backfill is idempotent, so it is safe to run on every startup.
+func backfillVersionBlobs(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	rows, err := tx.Query(`SELECT id, json_data FROM versions WHERE blob_id IS NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to query versions to backfill: %w", err)
+	}
+
+	type pending struct {
+		id     int64
+		stored string
+	}
+	var versions []pending
+	for rows.Next() {
+		var version pending
+		if err := rows.Scan(&version.id, &version.stored); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("failed to close rows: %w", err)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating versions: %w", err)
+	}
+
+	for _, version := range versions {
+		plain, err := decodeJSONData(version.stored)
+		if err != nil {
+			return err
+		}
+
+		hash := dataHash(plain)
+		blobID, ok, err := findBlob(tx, hash)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if blobID, err = insertBlob(tx, hash, version.stored); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(`UPDATE versions SET blob_id = ?, json_data = '' WHERE id = ?`, blobID, version.id); err != nil {
+			return fmt.Errorf("failed to link version blob: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetRawVersionData returns a version's data exactly as stored, along with its decoded form for
+// encoded rows. A value that fails to decode is reported rather than returned as an error, since
+// inspecting such rows is what this is for.
+func (s *sqlStore) GetRawVersionData(name string, versionNumber int) (*models.RawVersionData, error) {
+	query := `
+		SELECT ` + versionDataColumn + `, COALESCE(b.hash, '')
+		FROM versions v ` + versionBlobJoin + `
+		WHERE v.configuration_name = ? AND v.version_number = ?`
+
+	raw := models.RawVersionData{Name: name, Version: versionNumber}
+	err := s.reader(name).QueryRow(query, name, versionNumber).Scan(&raw.Stored, &raw.BlobHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &VersionNotFoundError{ConfigName: name, Version: versionNumber}
+		}
+		return nil, fmt.Errorf("failed to get raw version data: %w", err)
+	}
+
+	raw.Encoding = storedEncoding(raw.Stored)
+	raw.StoredBytes = len(raw.Stored)
+	if raw.Encoding != "plain" {
+		decoded, err := decodeJSONData(raw.Stored)
+		if err != nil {
+			raw.DecodeError = err.Error()
+		} else {
+			raw.Decoded = decoded
+		}
+	}
+
+	return &raw, nil
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// SetConfigBudget stores the budget for a configuration name, replacing any existing one
+func (s *sqlStore) SetConfigBudget(budget models.ConfigBudget) (*models.ConfigBudget, error) {
+	budget.UpdatedAt = time.Now()
+	query := `
+		INSERT INTO config_budgets (name, max_requests_per_minute, max_data_bytes, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			max_requests_per_minute = excluded.max_requests_per_minute,
+			max_data_bytes = excluded.max_data_bytes,
+			updated_at = excluded.updated_at`
+
+	_, err := s.db.Exec(query, budget.Name, budget.MaxRequestsPerMinute, budget.MaxDataBytes, formatTimestamp(budget.UpdatedAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store config budget: %w", err)
+	}
+
+	return &budget, nil
+}
+
+// DeleteConfigBudget removes the budget stored for name
+func (s *sqlStore) DeleteConfigBudget(name string) error {
+	result, err := s.db.Exec(`DELETE FROM config_budgets WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete config budget: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return &ConfigBudgetNotFoundError{ConfigName: name}
+	}
+
+	return nil
+}
+
+// ListConfigBudgets retrieves every stored budget ordered by configuration name
+func (s *sqlStore) ListConfigBudgets() ([]models.ConfigBudget, error) {
+	query := `
+		SELECT name, max_requests_per_minute, max_data_bytes, updated_at
+		FROM config_budgets
+		ORDER BY name`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query config budgets: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	budgets := []models.ConfigBudget{}
+	for rows.Next() {
+		var budget models.ConfigBudget
+		var updatedAtStr string
+		if err := rows.Scan(&budget.Name, &budget.MaxRequestsPerMinute, &budget.MaxDataBytes, &updatedAtStr); err != nil {
+			return nil, fmt.Errorf("failed to scan config budget: %w", err)
+		}
+
+		budget.UpdatedAt, err = parseTimestamp(updatedAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config budget updated_at: %w", err)
+		}
+
+		budgets = append(budgets, budget)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating config budgets: %w", err)
+	}
+
+	return budgets, nil
+}
+
+// ConfigBudgetNotFoundError is returned when no budget is stored for a configuration name
+type ConfigBudgetNotFoundError struct {
+	ConfigName string
+}
+
+func (e *ConfigBudgetNotFoundError) Error() string {
+	return fmt.Sprintf("CONFIG_BUDGET_NOT_FOUND: No budget is set for '%s'", e.ConfigName)
+}
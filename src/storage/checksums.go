@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"fmt"
+
+	"config-manager/src/models"
+)
+
+// VerifyBlobChecksums recomputes the checksum of up to limit blobs with an id above afterID and
+// reports the versions of every blob whose data no longer matches its recorded hash. It returns
+// the id of the last blob checked so the caller can continue from there, and how many were
+// checked; zero means every blob has been visited.
+func (s *sqlStore) VerifyBlobChecksums(afterID int64, limit int) ([]models.ChecksumMismatch, int64, int, error) {
+	rows, err := s.db.Query(`SELECT id, hash, data FROM version_blobs WHERE id > ? ORDER BY id LIMIT ?`, afterID, limit)
+	if err != nil {
+		return nil, afterID, 0, fmt.Errorf("failed to query version blobs: %w", err)
+	}
+
+	type badBlob struct {
+		id       int64
+		mismatch models.ChecksumMismatch
+	}
+	var bad []badBlob
+	lastID, checked := afterID, 0
+	for rows.Next() {
+		var id int64
+		var hash, stored string
+		if err := rows.Scan(&id, &hash, &stored); err != nil {
+			_ = rows.Close()
+			return nil, afterID, 0, fmt.Errorf("failed to scan version blob: %w", err)
+		}
+		lastID = id
+		checked++
+
+		jsonData, err := decodeJSONData(stored)
+		if err != nil {
+			bad = append(bad, badBlob{id, models.ChecksumMismatch{StoredChecksum: hash, DecodeError: err.Error()}})
+			continue
+		}
+		if computed := dataHash(jsonData); computed != hash {
+			bad = append(bad, badBlob{id, models.ChecksumMismatch{StoredChecksum: hash, ComputedChecksum: computed}})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, afterID, 0, fmt.Errorf("error iterating version blobs: %w", err)
+	}
+	_ = rows.Close()
+
+	// A blob is shared by every version with the same data, so each affected version is reported
+	mismatches := []models.ChecksumMismatch{}
+	for _, blob := range bad {
+		versions, err := s.db.Query(`
+			SELECT configuration_name, version_number
+			FROM versions
+			WHERE blob_id = ?
+			ORDER BY configuration_name, version_number`, blob.id)
+		if err != nil {
+			return nil, afterID, 0, fmt.Errorf("failed to query versions of blob %d: %w", blob.id, err)
+		}
+		for versions.Next() {
+			mismatch := blob.mismatch
+			if err := versions.Scan(&mismatch.Name, &mismatch.Version); err != nil {
+				_ = versions.Close()
+				return nil, afterID, 0, fmt.Errorf("failed to scan version of blob %d: %w", blob.id, err)
+			}
+			mismatches = append(mismatches, mismatch)
+		}
+		err = versions.Err()
+		_ = versions.Close()
+		if err != nil {
+			return nil, afterID, 0, fmt.Errorf("error iterating versions of blob %d: %w", blob.id, err)
+		}
+	}
+
+	return mismatches, lastID, checked, nil
+}
+
+// CountUnchecksummedVersions counts versions whose data is stored inline from before
+// content-addressed storage, and so has no recorded checksum to verify against
+func (s *sqlStore) CountUnchecksummedVersions() (int, error) {
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM versions WHERE blob_id IS NULL`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count versions without checksum: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// compressedMarker prefixes json_data values stored gzip-compressed (base64 encoded).
+// Rows without the marker are plain JSON, so compressed and uncompressed rows coexist.
+const compressedMarker = "gzip:"
+
+// SetCompressStorage enables gzip compression of version data written from now on;
+// existing rows are read transparently either way
+func (s *sqlStore) SetCompressStorage(enabled bool) {
+	s.compressStorage = enabled
+}
+
+// encodeJSONData prepares json data for storage, compressing it when enabled
+func (s *sqlStore) encodeJSONData(name, jsonData string) (string, error) {
+	if !s.compressStorage {
+		return jsonData, nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(jsonData)); err != nil {
+		return "", fmt.Errorf("failed to compress json data: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress json data: %w", err)
+	}
+
+	encoded := compressedMarker + base64.StdEncoding.EncodeToString(buf.Bytes())
+	if len(jsonData) > 0 {
+		log.Printf("Compressed version data for '%s': %d -> %d bytes (ratio %.2f)",
+			name, len(jsonData), len(encoded), float64(len(encoded))/float64(len(jsonData)))
+	}
+
+	return encoded, nil
+}
+
+// storedEncoding names the encoding of a stored json_data value
+func storedEncoding(stored string) string {
+	if strings.HasPrefix(stored, compressedMarker) {
+		return "gzip"
+	}
+	return "plain"
+}
+
+// decodeJSONData returns the plain json for a stored json_data value, decompressing marked rows
+func decodeJSONData(stored string) (string, error) {
+	if !strings.HasPrefix(stored, compressedMarker) {
+		return stored, nil
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, compressedMarker))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode compressed json data: %w", err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress json data: %w", err)
+	}
+	defer reader.Close()
+
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress json data: %w", err)
+	}
+
+	return string(plain), nil
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"config-manager/src/models"
+)
+
+// SetEnforceContiguity makes writes refuse to add a version to a configuration whose versions
+// do not run contiguously from 1 to current_version. Off by default so imports with
+// intentional gaps keep working.
+func (s *sqlStore) SetEnforceContiguity(enabled bool) {
+	s.enforceContiguity = enabled
+}
+
+// checkContiguous verifies inside a write transaction that the configuration's versions are
+// exactly 1..currentVersion; it is a no-op unless enforcement is enabled. Drafts pending above
+// currentVersion are not gaps.
+func (s *sqlStore) checkContiguous(tx *sql.Tx, name string, currentVersion int) error {
+	if !s.enforceContiguity {
+		return nil
+	}
+
+	var count int
+	var minVersion, maxVersion sql.NullInt64
+	query := `
+		SELECT COUNT(*), MIN(version_number), MAX(version_number)
+		FROM versions
+		WHERE configuration_name = ? AND NOT (status = 'draft' AND version_number > ?)`
+	if err := tx.QueryRow(query, name, currentVersion).Scan(&count, &minVersion, &maxVersion); err != nil {
+		return fmt.Errorf("failed to check version contiguity: %w", err)
+	}
+
+	if count != currentVersion || minVersion.Int64 != 1 || maxVersion.Int64 != int64(currentVersion) {
+		return &VersionGapError{ConfigName: name, CurrentVersion: currentVersion}
+	}
+	return nil
+}
+
+// FindVersionGaps reports every configuration whose versions do not run contiguously from 1
+// to current_version, listing the missing version numbers and any versions beyond current_version.
+// Drafts pending above current_version are not counted as beyond it.
+func (s *sqlStore) FindVersionGaps() ([]models.VersionGap, error) {
+	query := `
+		SELECT c.name, c.current_version, v.version_number
+		FROM configurations c
+		JOIN versions v ON v.configuration_name = c.name
+		     AND NOT (v.status = 'draft' AND v.version_number > c.current_version)
+		WHERE c.name IN (
+			SELECT c2.name
+			FROM configurations c2
+			LEFT JOIN versions v2 ON v2.configuration_name = c2.name
+			     AND NOT (v2.status = 'draft' AND v2.version_number > c2.current_version)
+			GROUP BY c2.name
+			HAVING COUNT(v2.version_number) != c2.current_version
+			    OR MIN(v2.version_number) != 1
+			    OR MAX(v2.version_number) != c2.current_version
+		)
+		ORDER BY c.name, v.version_number`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query version gaps: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	gaps := []models.VersionGap{}
+	present := map[int]bool{}
+	flush := func() {
+		gap := &gaps[len(gaps)-1]
+		for version := 1; version <= gap.CurrentVersion; version++ {
+			if !present[version] {
+				gap.Missing = append(gap.Missing, version)
+			}
+		}
+		present = map[int]bool{}
+	}
+
+	for rows.Next() {
+		var name string
+		var currentVersion, versionNumber int
+		if err := rows.Scan(&name, &currentVersion, &versionNumber); err != nil {
+			return nil, fmt.Errorf("failed to scan version gap: %w", err)
+		}
+
+		if len(gaps) == 0 || gaps[len(gaps)-1].Name != name {
+			if len(gaps) > 0 {
+				flush()
+			}
+			gaps = append(gaps, models.VersionGap{Name: name, CurrentVersion: currentVersion, Missing: []int{}, Beyond: []int{}})
+		}
+
+		present[versionNumber] = true
+		if versionNumber > currentVersion {
+			gap := &gaps[len(gaps)-1]
+			gap.Beyond = append(gap.Beyond, versionNumber)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating version gaps: %w", err)
+	}
+	if len(gaps) > 0 {
+		flush()
+	}
+
+	return gaps, nil
+}
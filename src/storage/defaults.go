@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// GlobalDefaultName keys the default served for names without a default of their own
+const GlobalDefaultName = "*"
+
+// SetDefaultConfig stores the default payload for name, or the global default for GlobalDefaultName
+func (s *sqlStore) SetDefaultConfig(name, jsonData string) (*models.DefaultConfig, error) {
+	now := time.Now()
+	query := `
+		INSERT INTO default_configs (name, json_data, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET json_data = excluded.json_data, updated_at = excluded.updated_at`
+
+	if _, err := s.db.Exec(query, name, jsonData, formatTimestamp(now)); err != nil {
+		return nil, fmt.Errorf("failed to store default configuration: %w", err)
+	}
+
+	return &models.DefaultConfig{Name: name, UpdatedAt: now}, nil
+}
+
+// DeleteDefaultConfig removes the default stored for name
+func (s *sqlStore) DeleteDefaultConfig(name string) error {
+	result, err := s.db.Exec(`DELETE FROM default_configs WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete default configuration: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return &DefaultConfigNotFoundError{ConfigName: name}
+	}
+
+	return nil
+}
+
+// GetDefaultConfig retrieves the default for name, falling back to the global default.
+// It returns the key of the default that applied, name or GlobalDefaultName.
+func (s *sqlStore) GetDefaultConfig(name string) (jsonData, appliedName string, err error) {
+	query := `
+		SELECT name, json_data FROM default_configs
+		WHERE name IN (?, ?)
+		ORDER BY name = ? LIMIT 1`
+
+	err = s.db.QueryRow(query, name, GlobalDefaultName, GlobalDefaultName).Scan(&appliedName, &jsonData)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", &DefaultConfigNotFoundError{ConfigName: name}
+		}
+		return "", "", fmt.Errorf("failed to get default configuration: %w", err)
+	}
+
+	return jsonData, appliedName, nil
+}
+
+// DefaultConfigNotFoundError is returned when no default applies to a configuration name
+type DefaultConfigNotFoundError struct {
+	ConfigName string
+}
+
+func (e *DefaultConfigNotFoundError) Error() string {
+	if e.ConfigName == GlobalDefaultName {
+		return "DEFAULT_CONFIG_NOT_FOUND: No global default configuration is set"
+	}
+	return fmt.Sprintf("DEFAULT_CONFIG_NOT_FOUND: No default configuration applies to '%s'", e.ConfigName)
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// nextVersionNumber returns the number for a new version of a configuration: the one after
+// currentVersion, or after the highest draft when drafts are pending above it
+func nextVersionNumber(tx *sql.Tx, name string, currentVersion int) (int, error) {
+	var highestDraft sql.NullInt64
+	err := tx.QueryRow(`SELECT MAX(version_number) FROM versions WHERE configuration_name = ? AND status = 'draft'`, name).Scan(&highestDraft)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check draft versions: %w", err)
+	}
+	if int(highestDraft.Int64) > currentVersion {
+		return int(highestDraft.Int64) + 1, nil
+	}
+	return currentVersion + 1, nil
+}
+
+// CreateDraftVersion stores jsonData as a draft version of an existing configuration, returning
+// it with the current version. The draft gets the next version number but current_version is
+// unchanged until it is published. A non-zero expectedVersion must match the current version,
+// as in UpdateConfigurationIfCurrent.
+func (s *sqlStore) CreateDraftVersion(name, jsonData, schemaHash string, original *models.OriginalData, expectedVersion int, createdBy string) (*models.Version, int, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	var currentVersion int
+	row := tx.QueryRow("SELECT current_version FROM configurations WHERE name = ? AND deleted_at IS NULL", name)
+	if err := row.Scan(&currentVersion); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, &ConfigNotFoundError{ConfigName: name}
+		}
+		return nil, 0, fmt.Errorf("failed to query configuration: %w", err)
+	}
+
+	if err := checkExpectedVersion(name, expectedVersion, currentVersion); err != nil {
+		return nil, 0, err
+	}
+
+	newVersion, err := nextVersionNumber(tx, name, currentVersion)
+	if err != nil {
+		return nil, 0, err
+	}
+	now := time.Now()
+
+	blobID, err := s.storeBlob(tx, name, jsonData)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	format, originalText := originalColumns(original)
+	versionQuery := `
+		INSERT INTO versions (configuration_name, version_number, json_data, blob_id, created_at, schema_hash, format, original_data, status, created_by)
+		VALUES (?, ?, '', ?, ?, ?, ?, ?, ?, ?)`
+	_, err = tx.Exec(versionQuery, name, newVersion, blobID, formatTimestamp(now), nullIfEmpty(schemaHash), format, originalText, models.VersionStatusDraft, nullIfEmpty(createdBy))
+	if err != nil {
+		if s.dialect.uniqueViolation(err, "versions") {
+			return nil, 0, &VersionConflictError{ConfigName: name, Version: newVersion}
+		}
+		return nil, 0, fmt.Errorf("failed to insert draft version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	return &models.Version{
+		ConfigurationName: name,
+		VersionNumber:     newVersion,
+		CreatedAt:         now,
+		Status:            models.VersionStatusDraft,
+		Format:            format,
+		CreatedBy:         createdBy,
+	}, currentVersion, nil
+}
+
+// PublishVersion approves a draft, making it the current version. Drafts overtaken by a newer
+// published version cannot be published, since that would silently discard the newer changes.
+func (s *sqlStore) PublishVersion(name string, versionNumber int) (*models.Configuration, int, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	var currentVersion int
+	var createdAtStr string
+	err = tx.QueryRow(`SELECT current_version, created_at FROM configurations WHERE name = ? AND deleted_at IS NULL`, name).Scan(&currentVersion, &createdAtStr)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, &ConfigNotFoundError{ConfigName: name}
+		}
+		return nil, 0, fmt.Errorf("failed to query configuration: %w", err)
+	}
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM versions WHERE configuration_name = ? AND version_number = ?`, name, versionNumber).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, &VersionNotFoundError{ConfigName: name, Version: versionNumber}
+		}
+		return nil, 0, fmt.Errorf("failed to query version: %w", err)
+	}
+	if status != models.VersionStatusDraft {
+		return nil, 0, &VersionNotDraftError{ConfigName: name, Version: versionNumber}
+	}
+	if versionNumber < currentVersion {
+		return nil, 0, &DraftSupersededError{ConfigName: name, Version: versionNumber, CurrentVersion: currentVersion}
+	}
+
+	createdAt, err := parseTimestamp(createdAtStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE versions SET status = ? WHERE configuration_name = ? AND version_number = ?`,
+		models.VersionStatusPublished, name, versionNumber); err != nil {
+		return nil, 0, fmt.Errorf("failed to publish version: %w", err)
+	}
+
+	// Publishing is a new edit, so it leaves nothing to redo
+	now := time.Now()
+	updateQuery := `UPDATE configurations SET current_version = ?, updated_at = ?, redo_version = NULL WHERE name = ?`
+	if _, err := tx.Exec(updateQuery, versionNumber, formatTimestamp(now), name); err != nil {
+		return nil, 0, fmt.Errorf("failed to update current version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	return &models.Configuration{
+		Name:           name,
+		CurrentVersion: versionNumber,
+		CreatedAt:      createdAt,
+		UpdatedAt:      now,
+	}, currentVersion, nil
+}
+
+// LatestDraftVersion returns the number of the newest draft pending above current_version, or
+// zero when there is none
+func (s *sqlStore) LatestDraftVersion(name string) (int, error) {
+	query := `
+		SELECT MAX(v.version_number)
+		FROM versions v
+		JOIN configurations c ON c.name = v.configuration_name
+		WHERE v.configuration_name = ? AND v.status = 'draft'
+		  AND v.version_number > c.current_version`
+
+	var version sql.NullInt64
+	if err := s.reader(name).QueryRow(query, name).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to query draft versions: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// VersionNotDraftError is returned when publishing a version that is already published
+type VersionNotDraftError struct {
+	ConfigName string
+	Version    int
+}
+
+func (e *VersionNotDraftError) Error() string {
+	return fmt.Sprintf("VERSION_NOT_DRAFT: Version %d of configuration '%s' is not a draft", e.Version, e.ConfigName)
+}
+
+// DraftSupersededError is returned when publishing a draft older than the current version
+type DraftSupersededError struct {
+	ConfigName     string
+	Version        int
+	CurrentVersion int
+}
+
+func (e *DraftSupersededError) Error() string {
+	return fmt.Sprintf("DRAFT_SUPERSEDED: Draft version %d of configuration '%s' is older than current version %d", e.Version, e.ConfigName, e.CurrentVersion)
+}
+
+// VersionIsDraftError is returned when rolling back to a draft, which would activate it
+// without approval
+type VersionIsDraftError struct {
+	ConfigName string
+	Version    int
+}
+
+func (e *VersionIsDraftError) Error() string {
+	return fmt.Sprintf("VERSION_IS_DRAFT: Version %d of configuration '%s' is an unpublished draft", e.Version, e.ConfigName)
+}
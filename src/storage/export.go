@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"config-manager/src/models"
+)
+
+// ExportAll calls fn with every live configuration, its sensitive flag, tags and versions,
+// ordered by name and version number. The versions come from a single query, so the export is a
+// consistent snapshot, and only one configuration's history is held at a time; tags are read just
+// before. Soft-deleted configurations are left out. An error from fn stops
+// the iteration and is returned.
+func (s *sqlStore) ExportAll(fn func(models.ExportedConfiguration) error) error {
+	tags, err := allTags(s.reader(""))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		SELECT c.name, c.current_version, c.created_at, c.updated_at, c.sensitive,
+		       v.version_number, ` + versionDataColumn + `, v.format, v.original_data, v.status, v.protected, v.created_at, v.created_by
+		FROM configurations c
+		JOIN versions v ON v.configuration_name = c.name
+		` + versionBlobJoin + `
+		WHERE c.deleted_at IS NULL
+		ORDER BY c.name, v.version_number`
+
+	rows, err := s.reader("").Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to query configurations for export: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	var config *models.ExportedConfiguration
+	for rows.Next() {
+		var name, configCreatedAt, configUpdatedAt, jsonData, versionCreatedAt string
+		var currentVersion int
+		var sensitive bool
+		var originalData, createdBy sql.NullString
+		var version models.ExportedVersion
+		err := rows.Scan(
+			&name, &currentVersion, &configCreatedAt, &configUpdatedAt, &sensitive,
+			&version.Version, &jsonData, &version.Format, &originalData, &version.Status, &version.Protected, &versionCreatedAt, &createdBy,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan exported version: %w", err)
+		}
+
+		if config == nil || config.Name != name {
+			if config != nil {
+				if err := fn(*config); err != nil {
+					return err
+				}
+			}
+			config = &models.ExportedConfiguration{Name: name, CurrentVersion: currentVersion, Sensitive: sensitive, Tags: tags[name]}
+			if config.CreatedAt, err = parseTimestamp(configCreatedAt); err != nil {
+				return fmt.Errorf("failed to parse config created_at: %w", err)
+			}
+			if config.UpdatedAt, err = parseTimestamp(configUpdatedAt); err != nil {
+				return fmt.Errorf("failed to parse config updated_at: %w", err)
+			}
+		}
+
+		if version.CreatedAt, err = parseTimestamp(versionCreatedAt); err != nil {
+			return fmt.Errorf("failed to parse version created_at: %w", err)
+		}
+		jsonData, err = decodeJSONData(jsonData)
+		if err != nil {
+			return err
+		}
+		version.Data = json.RawMessage(jsonData)
+		version.Original = originalData.String
+		version.CreatedBy = createdBy.String
+		config.Versions = append(config.Versions, version)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating exported versions: %w", err)
+	}
+
+	if config != nil {
+		return fn(*config)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"config-manager/src/models"
+)
+
+// Import modes: what ImportAll does with a configuration whose name already exists
+const (
+	// ImportModeFail fails the whole import
+	ImportModeFail = "fail"
+	// ImportModeSkip keeps the existing configuration and skips the imported one
+	ImportModeSkip = "skip"
+	// ImportModeOverwrite replaces the existing configuration and its history
+	ImportModeOverwrite = "overwrite"
+)
+
+// ImportConflictsError is returned by a dry-run import for every configuration the import would
+// refuse: existing names under ImportModeFail and protected ones under ImportModeOverwrite
+type ImportConflictsError struct {
+	Conflicts []error
+}
+
+func (e *ImportConflictsError) Error() string {
+	return fmt.Sprintf("IMPORT_CONFLICTS: %d archived configurations conflict with existing ones", len(e.Conflicts))
+}
+
+// ImportAll recreates configurations, their sensitive flags, tags and version histories from an
+// export archive in a single transaction, keeping their version numbers, statuses and timestamps. The data must
+// already be validated against the schema identified by schemaHash. An existing name, live or
+// soft-deleted, is handled as mode says; overwriting refuses configurations with a protected
+// version. Any error leaves the database unchanged.
+//
+// A dry run rolls the transaction back instead of committing. Rather than stopping at the first
+// conflicting configuration it checks them all, and returns an ImportConflictsError listing
+// each of them, or the result the import would have.
+func (s *sqlStore) ImportAll(configs []models.ExportedConfiguration, schemaHash, mode string, dryRun bool) (*models.ImportResult, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	result := &models.ImportResult{
+		Mode:        mode,
+		Created:     []string{},
+		Overwritten: []string{},
+		Skipped:     []string{},
+	}
+	var written []string
+	var conflicts []error
+	for _, config := range configs {
+		deleted, err := isDeleted(tx, config.Name)
+		exists := err == nil
+		if _, missing := err.(*ConfigNotFoundError); err != nil && !missing {
+			return nil, err
+		}
+
+		var conflict error
+		switch {
+		case !exists:
+			_, err = tx.Exec(`INSERT INTO configurations (name, current_version, created_at, updated_at, sensitive) VALUES (?, ?, ?, ?, ?)`,
+				config.Name, config.CurrentVersion, formatTimestamp(config.CreatedAt), formatTimestamp(config.UpdatedAt), sensitiveFlag(config.Sensitive))
+			if err != nil {
+				return nil, fmt.Errorf("failed to insert configuration: %w", err)
+			}
+			if err := insertImportedTags(tx, config); err != nil {
+				return nil, err
+			}
+			result.Created = append(result.Created, config.Name)
+		case mode == ImportModeSkip:
+			result.Skipped = append(result.Skipped, config.Name)
+			continue
+		case mode == ImportModeOverwrite:
+			err := overwriteForImport(tx, config)
+			if _, protected := err.(*VersionProtectedError); protected {
+				conflict = err
+			} else if err != nil {
+				return nil, err
+			} else {
+				result.Overwritten = append(result.Overwritten, config.Name)
+			}
+		case deleted:
+			conflict = &ConfigDeletedError{ConfigName: config.Name}
+		default:
+			conflict = &ConfigAlreadyExistsError{ConfigName: config.Name}
+		}
+		if conflict != nil {
+			if !dryRun {
+				return nil, conflict
+			}
+			conflicts = append(conflicts, conflict)
+			continue
+		}
+
+		for _, version := range config.Versions {
+			if err := s.insertImportedVersion(tx, config.Name, version, schemaHash); err != nil {
+				return nil, err
+			}
+		}
+		result.Versions += len(config.Versions)
+		written = append(written, config.Name)
+	}
+
+	if dryRun {
+		if len(conflicts) > 0 {
+			return nil, &ImportConflictsError{Conflicts: conflicts}
+		}
+		result.DryRun = true
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(written...)
+
+	return result, nil
+}
+
+// overwriteForImport clears an existing configuration's history and tags within tx and takes
+// over the imported configuration's current version, timestamps, sensitive flag and tags,
+// restoring it if soft-deleted
+func overwriteForImport(tx *sql.Tx, config models.ExportedConfiguration) error {
+	// Overwriting discards the whole history, so one protected version refuses it
+	protected, err := lowestProtectedVersion(tx, config.Name)
+	if err != nil {
+		return err
+	}
+	if protected > 0 {
+		return &VersionProtectedError{ConfigName: config.Name, Version: protected, Operation: "overwrite"}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM versions WHERE configuration_name = ?`, config.Name); err != nil {
+		return fmt.Errorf("failed to delete versions: %w", err)
+	}
+	if err := deleteUnreferencedBlobs(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE configurations
+		SET current_version = ?, created_at = ?, updated_at = ?, sensitive = ?, redo_version = NULL, deleted_at = NULL
+		WHERE name = ?`,
+		config.CurrentVersion, formatTimestamp(config.CreatedAt), formatTimestamp(config.UpdatedAt), sensitiveFlag(config.Sensitive), config.Name)
+	if err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM tags WHERE configuration_name = ?`, config.Name); err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+	return insertImportedTags(tx, config)
+}
+
+// insertImportedTags stores the tags of an imported configuration within tx
+func insertImportedTags(tx *sql.Tx, config models.ExportedConfiguration) error {
+	for key, value := range config.Tags {
+		if _, err := tx.Exec(`INSERT INTO tags (configuration_name, key, value) VALUES (?, ?, ?)`, config.Name, key, value); err != nil {
+			return fmt.Errorf("failed to store tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// sensitiveFlag converts a sensitive flag to the integer the column stores
+func sensitiveFlag(sensitive bool) int {
+	if sensitive {
+		return 1
+	}
+	return 0
+}
+
+// insertImportedVersion inserts one imported version within tx, keeping its number and metadata
+func (s *sqlStore) insertImportedVersion(tx *sql.Tx, name string, version models.ExportedVersion, schemaHash string) error {
+	blobID, err := s.storeBlob(tx, name, string(version.Data))
+	if err != nil {
+		return err
+	}
+
+	protected := 0
+	if version.Protected {
+		protected = 1
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO versions (configuration_name, version_number, json_data, blob_id, created_at, schema_hash, format, original_data, status, protected, created_by)
+		VALUES (?, ?, '', ?, ?, ?, ?, ?, ?, ?, ?)`,
+		name, version.Version, blobID, formatTimestamp(version.CreatedAt), nullIfEmpty(schemaHash),
+		version.Format, nullIfEmpty(version.Original), version.Status, protected, nullIfEmpty(version.CreatedBy))
+	if err != nil {
+		return fmt.Errorf("failed to insert version %d: %w", version.Version, err)
+	}
+	return nil
+}
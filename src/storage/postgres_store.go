@@ -0,0 +1,198 @@
+//go:build postgres || !sqlite
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pgmigrations "config-manager/migrations/postgres"
+
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/lib/pq"
+)
+
+// writeLockKey is the transaction-level advisory lock every Postgres write transaction takes
+const writeLockKey = 0x636f6e666967 // "config"
+
+// PostgresStore implements Store on PostgreSQL
+type PostgresStore struct {
+	*sqlStore
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a new PostgreSQL storage instance; db should come from OpenPostgres
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{sqlStore: newSQLStore(db, db, 0, postgresDialect{})}
+}
+
+// NewPostgresStoreWithReplica creates a PostgreSQL storage instance that sends reads to replica,
+// with the same read-after-write routing as NewSQLiteStoreWithReplica
+func NewPostgresStoreWithReplica(primary, replica *sql.DB, readAfterWriteWindow time.Duration) *PostgresStore {
+	return &PostgresStore{sqlStore: newSQLStore(primary, replica, readAfterWriteWindow, postgresDialect{})}
+}
+
+// OpenPostgres opens the PostgreSQL database at dsn. The store's queries use ? placeholders,
+// which the returned handle rewrites to Postgres' numbered $N form.
+func OpenPostgres(dsn string) (*sql.DB, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid postgres connection string: %w", err)
+	}
+	return sql.OpenDB(rebindConnector{connector}), nil
+}
+
+// MigratePostgres applies every pending embedded schema migration to the PostgreSQL database db.
+// The Postgres schema starts out with content-addressed blobs, so there is nothing to backfill.
+func MigratePostgres(db *sql.DB) error {
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to create migration driver: %w", err)
+	}
+	return applyMigrations(pgmigrations.FS, "postgres", driver)
+}
+
+// postgresDialect adapts the shared queries to PostgreSQL
+type postgresDialect struct{}
+
+// beginWrite takes a transaction-level advisory lock, serializing writers like SQLite's
+// immediate transactions do; it is released on commit or rollback
+func (postgresDialect) beginWrite(tx *sql.Tx) error {
+	_, err := tx.Exec(`SELECT pg_advisory_xact_lock(?)`, writeLockKey)
+	return err
+}
+
+func (postgresDialect) uniqueViolation(err error, table string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Table == table
+}
+
+// noLimit is NULL, which Postgres reads as LIMIT ALL
+func (postgresDialect) noLimit() interface{} {
+	return nil
+}
+
+// rebind rewrites ? placeholders to $1, $2, ... leaving quoted strings, quoted identifiers
+// and comments untouched
+func rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+4])
+			i += end + 3
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// rebindConnector hands out connections that rebind every query before passing it to pq
+type rebindConnector struct {
+	driver.Connector
+}
+
+func (c rebindConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rebindConn{conn}, nil
+}
+
+type rebindConn struct {
+	driver.Conn
+}
+
+func (c rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(rebind(query))
+}
+
+func (c rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, rebind(query))
+	}
+	return c.Prepare(query)
+}
+
+func (c rebindConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, rebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, rebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c rebindConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c rebindConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c rebindConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
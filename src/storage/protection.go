@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ProtectVersion marks a version as protected, so no cleanup operation may remove or renumber it.
+// Protection is permanent; protecting an already protected version is a no-op.
+func (s *sqlStore) ProtectVersion(name string, versionNumber int) error {
+	result, err := s.db.Exec(`UPDATE versions SET protected = 1 WHERE configuration_name = ? AND version_number = ?`, name, versionNumber)
+	if err != nil {
+		return fmt.Errorf("failed to protect version: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM configurations WHERE name = ? AND deleted_at IS NULL)`, name).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check configuration: %w", err)
+	}
+	if !exists {
+		return &ConfigNotFoundError{ConfigName: name}
+	}
+	return &VersionNotFoundError{ConfigName: name, Version: versionNumber}
+}
+
+// lowestProtectedVersion returns the lowest protected version number of a configuration, or
+// zero when none is protected
+func lowestProtectedVersion(tx *sql.Tx, name string) (int, error) {
+	var version sql.NullInt64
+	err := tx.QueryRow(`SELECT MIN(version_number) FROM versions WHERE configuration_name = ? AND protected = 1`, name).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check protected versions: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// VersionProtectedError is returned when an operation would remove or renumber a protected version
+type VersionProtectedError struct {
+	ConfigName string
+	Version    int
+	Operation  string
+}
+
+func (e *VersionProtectedError) Error() string {
+	return fmt.Sprintf("VERSION_PROTECTED: Cannot %s configuration '%s': version %d is protected", e.Operation, e.ConfigName, e.Version)
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"config-manager/src/models"
+)
+
+// PruneVersions deletes the published versions of a configuration older than its keep newest
+// ones, in one transaction, and returns the deleted version numbers. The current version, the
+// redo target and protected versions are always kept, as are drafts, which do not count
+// towards keep. Remaining versions keep their numbers; blobs no longer referenced are removed.
+func (s *sqlStore) PruneVersions(name string, keep int) (*models.PruneResult, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	var currentVersion int
+	var redoVersion sql.NullInt64
+	err = tx.QueryRow(`SELECT current_version, redo_version FROM configurations WHERE name = ? AND deleted_at IS NULL`, name).Scan(&currentVersion, &redoVersion)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &ConfigNotFoundError{ConfigName: name}
+		}
+		return nil, fmt.Errorf("failed to query configuration: %w", err)
+	}
+
+	result := &models.PruneResult{
+		Name:            name,
+		Keep:            keep,
+		CurrentVersion:  currentVersion,
+		RemovedVersions: []int{},
+	}
+
+	// The oldest version to keep is the keep-th newest published one; with fewer, nothing goes
+	var oldestKept int
+	err = tx.QueryRow(`
+		SELECT version_number FROM versions
+		WHERE configuration_name = ? AND status = 'published'
+		ORDER BY version_number DESC
+		LIMIT 1 OFFSET ?`, name, keep-1).Scan(&oldestKept)
+	if err == sql.ErrNoRows {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find versions to keep: %w", err)
+	}
+
+	prunable := `
+		configuration_name = ? AND status = 'published' AND protected = 0
+		AND version_number < ? AND version_number <> ? AND version_number <> ?`
+	args := []interface{}{name, oldestKept, currentVersion, redoVersion.Int64}
+
+	rows, err := tx.Query(`SELECT version_number FROM versions WHERE `+prunable+` ORDER BY version_number`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query versions to prune: %w", err)
+	}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan version: %w", err)
+		}
+		result.RemovedVersions = append(result.RemovedVersions, version)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close rows: %w", err)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating versions to prune: %w", err)
+	}
+	if len(result.RemovedVersions) == 0 {
+		return result, nil
+	}
+
+	if _, err := tx.Exec(`DELETE FROM versions WHERE `+prunable, args...); err != nil {
+		return nil, fmt.Errorf("failed to delete pruned versions: %w", err)
+	}
+	if err := deleteUnreferencedBlobs(tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	return result, nil
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// SoftDeleteConfiguration marks a configuration deleted, keeping its row and versions for audit.
+// Deleted configurations are hidden from reads, listings and writes until restored. A
+// configuration with a protected version cannot be deleted.
+func (s *sqlStore) SoftDeleteConfiguration(name string) (*models.Configuration, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	deleted, err := isDeleted(tx, name)
+	if err != nil {
+		return nil, err
+	}
+	if deleted {
+		return nil, &ConfigNotFoundError{ConfigName: name}
+	}
+
+	protected, err := lowestProtectedVersion(tx, name)
+	if err != nil {
+		return nil, err
+	}
+	if protected > 0 {
+		return nil, &VersionProtectedError{ConfigName: name, Version: protected, Operation: "delete"}
+	}
+
+	now := time.Now()
+	config, err := setDeletedAt(tx, name, formatTimestamp(now))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	config.DeletedAt = &now
+	return config, nil
+}
+
+// RestoreConfiguration clears the deletion mark of a soft-deleted configuration, making it and
+// its versions visible again. It fails with ConfigNotDeletedError for a live configuration.
+func (s *sqlStore) RestoreConfiguration(name string) (*models.Configuration, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	deleted, err := isDeleted(tx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, &ConfigNotDeletedError{ConfigName: name}
+	}
+
+	config, err := setDeletedAt(tx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	return config, nil
+}
+
+// isDeleted reports whether a configuration is soft-deleted, failing with ConfigNotFoundError
+// when there is no configuration of that name at all
+func isDeleted(tx *sql.Tx, name string) (bool, error) {
+	var deletedAt sql.NullString
+	err := tx.QueryRow(`SELECT deleted_at FROM configurations WHERE name = ?`, name).Scan(&deletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, &ConfigNotFoundError{ConfigName: name}
+		}
+		return false, fmt.Errorf("failed to query configuration: %w", err)
+	}
+	return deletedAt.Valid, nil
+}
+
+// setDeletedAt sets deleted_at of a configuration, nil restoring it, and returns the configuration
+func setDeletedAt(tx *sql.Tx, name string, deletedAt interface{}) (*models.Configuration, error) {
+	if _, err := tx.Exec(`UPDATE configurations SET deleted_at = ? WHERE name = ?`, deletedAt, name); err != nil {
+		return nil, fmt.Errorf("failed to update deleted_at: %w", err)
+	}
+
+	config := models.Configuration{Name: name}
+	var createdAtStr, updatedAtStr string
+	err := tx.QueryRow(`SELECT current_version, created_at, updated_at FROM configurations WHERE name = ?`, name).
+		Scan(&config.CurrentVersion, &createdAtStr, &updatedAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query configuration: %w", err)
+	}
+
+	config.CreatedAt, err = parseTimestamp(createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config created_at: %w", err)
+	}
+	config.UpdatedAt, err = parseTimestamp(updatedAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config updated_at: %w", err)
+	}
+
+	return &config, nil
+}
+
+// ConfigDeletedError is returned when creating a configuration whose name belongs to a
+// soft-deleted one; the deleted configuration must be restored instead
+type ConfigDeletedError struct {
+	ConfigName string
+}
+
+func (e *ConfigDeletedError) Error() string {
+	return fmt.Sprintf("CONFIG_DELETED: Configuration '%s' was deleted; restore it instead of creating it again", e.ConfigName)
+}
+
+// ConfigNotDeletedError is returned when restoring a configuration that is not deleted
+type ConfigNotDeletedError struct {
+	ConfigName string
+}
+
+func (e *ConfigNotDeletedError) Error() string {
+	return fmt.Sprintf("CONFIG_NOT_DELETED: Configuration '%s' is not deleted", e.ConfigName)
+}
@@ -0,0 +1,92 @@
+//go:build sqlite || !postgres
+
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"config-manager/migrations"
+
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	sqlite "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteStore is the Store backed by a SQLite database, the default backend
+type SQLiteStore struct {
+	*sqlStore
+}
+
+var _ Store = (*SQLiteStore)(nil)
+
+// SQLiteDSN builds the data source name for a SQLite database file. Transactions begin
+// IMMEDIATE so concurrent writers to a configuration are serialized instead of racing on
+// version numbers, and a busy timeout makes waiting writers queue rather than fail.
+func SQLiteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "_txlock=immediate&_busy_timeout=5000"
+}
+
+// NewSQLiteStore creates a new SQLite storage instance
+func NewSQLiteStore(db *sql.DB) *SQLiteStore {
+	return &SQLiteStore{newSQLStore(db, db, 0, sqliteDialect{})}
+}
+
+// NewSQLiteStoreWithReplica creates a SQLite storage instance that sends reads to a replica
+// and writes to the primary. Reads of a configuration written within readAfterWriteWindow
+// go to the primary; a zero window always reads from the replica.
+func NewSQLiteStoreWithReplica(primary, replica *sql.DB, readAfterWriteWindow time.Duration) *SQLiteStore {
+	return &SQLiteStore{newSQLStore(primary, replica, readAfterWriteWindow, sqliteDialect{})}
+}
+
+// sqliteDialect adapts the shared queries to SQLite
+type sqliteDialect struct{}
+
+// beginWrite does nothing: SQLiteDSN makes every transaction IMMEDIATE, which already takes
+// the database's single write lock
+func (sqliteDialect) beginWrite(tx *sql.Tx) error {
+	return nil
+}
+
+// uniqueViolation reads the table from the message of a UNIQUE or PRIMARY KEY constraint
+// error, which names the columns as "table.column"
+func (sqliteDialect) uniqueViolation(err error, table string) bool {
+	var sqliteErr sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	if sqliteErr.ExtendedCode != sqlite.ErrConstraintUnique && sqliteErr.ExtendedCode != sqlite.ErrConstraintPrimaryKey {
+		return false
+	}
+	_, columns, _ := strings.Cut(sqliteErr.Error(), "constraint failed: ")
+	return strings.HasPrefix(columns, table+".")
+}
+
+// noLimit is -1, which SQLite reads as no limit
+func (sqliteDialect) noLimit() interface{} {
+	return -1
+}
+
+// Migrate applies every pending embedded schema migration to the SQLite database db
+func Migrate(db *sql.DB) error {
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to create migration driver: %w", err)
+	}
+	if err := applyMigrations(migrations.FS, "sqlite3", driver); err != nil {
+		return err
+	}
+
+	// Hashing cannot be expressed in SQL, so moving version data into blobs finishes here
+	if err := backfillVersionBlobs(db); err != nil {
+		return fmt.Errorf("failed to backfill version blobs: %w", err)
+	}
+
+	return nil
+}
@@ -4,27 +4,17 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"config-manager/src/models"
-
-	_ "github.com/mattn/go-sqlite3"
 )
 
-// SQLiteStore handles all database operations for configurations and versions
-type SQLiteStore struct {
-	db *sql.DB
-}
-
-// NewSQLiteStore creates a new SQLite storage instance
-func NewSQLiteStore(db *sql.DB) *SQLiteStore {
-	return &SQLiteStore{db: db}
-}
-
-// CreateConfiguration creates a new configuration with version 1
+// CreateConfiguration creates a new configuration with version 1, recording the hash of the schema it was validated against,
+// who created it and, for data authored in another format, the original text
 // Implements the data access pattern from data-model.md
-func (s *SQLiteStore) CreateConfiguration(name, jsonData string) (*models.Configuration, error) {
-	tx, err := s.db.Begin()
+func (s *sqlStore) CreateConfiguration(name, jsonData, schemaHash string, original *models.OriginalData, createdBy string) (*models.Configuration, error) {
+	tx, err := s.beginWrite()
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -35,55 +25,80 @@ func (s *SQLiteStore) CreateConfiguration(name, jsonData string) (*models.Config
 	}()
 
 	now := time.Now()
+	if err := s.insertConfiguration(tx, name, jsonData, schemaHash, original, createdBy, now); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	return &models.Configuration{
+		Name:           name,
+		CurrentVersion: 1,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// insertConfiguration inserts a configuration record and its version 1 within tx
+func (s *sqlStore) insertConfiguration(tx *sql.Tx, name, jsonData, schemaHash string, original *models.OriginalData, createdBy string, now time.Time) error {
+	// A soft-deleted configuration keeps its name; it is restored rather than recreated.
+	// Checking first keeps the transaction usable, which a failed insert would not on Postgres.
+	deleted, err := isDeleted(tx, name)
+	if err == nil {
+		if deleted {
+			return &ConfigDeletedError{ConfigName: name}
+		}
+		return &ConfigAlreadyExistsError{ConfigName: name}
+	}
+	if _, missing := err.(*ConfigNotFoundError); !missing {
+		return err
+	}
 
 	// 1. Insert new configuration record
 	configQuery := `
 		INSERT INTO configurations (name, current_version, created_at, updated_at)
 		VALUES (?, ?, ?, ?)`
 
-	_, err = tx.Exec(configQuery, name, 1, now, now)
+	_, err = tx.Exec(configQuery, name, 1, formatTimestamp(now), formatTimestamp(now))
 	if err != nil {
-		if isUniqueConstraintError(err) {
-			return nil, &ConfigAlreadyExistsError{ConfigName: name}
+		if s.dialect.uniqueViolation(err, "configurations") {
+			return &ConfigAlreadyExistsError{ConfigName: name}
 		}
-		return nil, fmt.Errorf("failed to insert configuration: %w", err)
+		return fmt.Errorf("failed to insert configuration: %w", err)
 	}
 
-	// 2. Insert version 1 record
-	versionQuery := `
-		INSERT INTO versions (configuration_name, version_number, json_data, created_at)
-		VALUES (?, ?, ?, ?)`
-
-	_, err = tx.Exec(versionQuery, name, 1, jsonData, now)
+	// 2. Insert version 1 record, pointing at the shared blob for its data
+	blobID, err := s.storeBlob(tx, name, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert version: %w", err)
+		return err
 	}
 
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	format, originalText := originalColumns(original)
+	versionQuery := `
+		INSERT INTO versions (configuration_name, version_number, json_data, blob_id, created_at, schema_hash, format, original_data, created_by)
+		VALUES (?, ?, '', ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(versionQuery, name, 1, blobID, formatTimestamp(now), nullIfEmpty(schemaHash), format, originalText, nullIfEmpty(createdBy))
+	if err != nil {
+		return fmt.Errorf("failed to insert version: %w", err)
 	}
 
-	return &models.Configuration{
-		Name:           name,
-		CurrentVersion: 1,
-		CreatedAt:      now,
-		UpdatedAt:      now,
-	}, nil
+	return nil
 }
 
 // UpdateConfiguration updates an existing configuration, increments version, and returns updated config
-func (s *SQLiteStore) UpdateConfiguration(name, jsonData string) (*models.Configuration, error) {
-	// Check if configuration exists
-	var currentVersion int
-	row := s.db.QueryRow("SELECT current_version FROM configurations WHERE name = ?", name)
-	if err := row.Scan(&currentVersion); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, &ConfigNotFoundError{ConfigName: name}
-		}
-		return nil, fmt.Errorf("failed to query configuration: %w", err)
-	}
+func (s *sqlStore) UpdateConfiguration(name, jsonData, schemaHash string, original *models.OriginalData, createdBy string) (*models.Configuration, error) {
+	return s.UpdateConfigurationIfCurrent(name, jsonData, schemaHash, original, 0, createdBy)
+}
 
-	tx, err := s.db.Begin()
+// UpdateConfigurationIfCurrent updates a configuration like UpdateConfiguration, but only if its
+// current version is expectedVersion, checked inside the transaction; otherwise it fails with
+// StaleVersionError. An expectedVersion of 0 skips the check.
+func (s *sqlStore) UpdateConfigurationIfCurrent(name, jsonData, schemaHash string, original *models.OriginalData, expectedVersion int, createdBy string) (*models.Configuration, error) {
+	tx, err := s.beginWrite()
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -93,22 +108,53 @@ func (s *SQLiteStore) UpdateConfiguration(name, jsonData string) (*models.Config
 		}
 	}()
 
-	newVersion := currentVersion + 1
+	// Read the current version inside the transaction so the increment is atomic
+	var currentVersion int
+	row := tx.QueryRow("SELECT current_version FROM configurations WHERE name = ? AND deleted_at IS NULL", name)
+	if err := row.Scan(&currentVersion); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &ConfigNotFoundError{ConfigName: name}
+		}
+		return nil, fmt.Errorf("failed to query configuration: %w", err)
+	}
+
+	if err := checkExpectedVersion(name, expectedVersion, currentVersion); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkContiguous(tx, name, currentVersion); err != nil {
+		return nil, err
+	}
+
+	// Pending drafts keep their numbers, so the new version goes above them
+	newVersion, err := nextVersionNumber(tx, name, currentVersion)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now()
 
+	blobID, err := s.storeBlob(tx, name, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
 	// Insert new version row
+	format, originalText := originalColumns(original)
 	versionQuery := `
-		INSERT INTO versions (configuration_name, version_number, json_data, created_at)
-		VALUES (?, ?, ?, ?)`
-	_, err = tx.Exec(versionQuery, name, newVersion, jsonData, now)
+		INSERT INTO versions (configuration_name, version_number, json_data, blob_id, created_at, schema_hash, format, original_data, created_by)
+		VALUES (?, ?, '', ?, ?, ?, ?, ?, ?)`
+	_, err = tx.Exec(versionQuery, name, newVersion, blobID, formatTimestamp(now), nullIfEmpty(schemaHash), format, originalText, nullIfEmpty(createdBy))
 	if err != nil {
+		if s.dialect.uniqueViolation(err, "versions") {
+			return nil, &VersionConflictError{ConfigName: name, Version: newVersion}
+		}
 		return nil, fmt.Errorf("failed to insert new version: %w", err)
 	}
 
-	// Update current_version in configurations table
+	// Update current_version in configurations table; a new edit leaves nothing to redo
 	updateConfigQuery := `
-		UPDATE configurations SET current_version = ?, updated_at = ? WHERE name = ?`
-	_, err = tx.Exec(updateConfigQuery, newVersion, now, name)
+		UPDATE configurations SET current_version = ?, updated_at = ?, redo_version = NULL WHERE name = ?`
+	_, err = tx.Exec(updateConfigQuery, newVersion, formatTimestamp(now), name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update configuration: %w", err)
 	}
@@ -116,6 +162,7 @@ func (s *SQLiteStore) UpdateConfiguration(name, jsonData string) (*models.Config
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.markWritten(name)
 
 	return &models.Configuration{
 		Name:           name,
@@ -126,10 +173,25 @@ func (s *SQLiteStore) UpdateConfiguration(name, jsonData string) (*models.Config
 }
 
 // RollbackConfiguration creates a new version with data from target version
-func (s *SQLiteStore) RollbackConfiguration(name string, targetVersion int) (*models.Configuration, error) {
-	tx, err := s.db.Begin()
+// and remembers the superseded version so the rollback can be redone
+func (s *sqlStore) RollbackConfiguration(name string, targetVersion int, schemaHash, createdBy string) (*models.Configuration, error) {
+	config, _, err := s.restoreVersion(name, targetVersion, schemaHash, createdBy, false)
+	return config, err
+}
+
+// RedoConfiguration restores the version superseded by the latest rollback, returning the
+// restored version. It fails with CannotRedoError once the configuration was edited since.
+func (s *sqlStore) RedoConfiguration(name, schemaHash, createdBy string) (*models.Configuration, int, error) {
+	return s.restoreVersion(name, 0, schemaHash, createdBy, true)
+}
+
+// restoreVersion creates a new version with the data of targetVersion, or of the recorded
+// redo version when redo is set. The new version is recorded as created by createdBy, not by
+// the author of the restored one.
+func (s *sqlStore) restoreVersion(name string, targetVersion int, schemaHash, createdBy string, redo bool) (*models.Configuration, int, error) {
+	tx, err := s.beginWrite()
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() {
 		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
@@ -137,82 +199,134 @@ func (s *SQLiteStore) RollbackConfiguration(name string, targetVersion int) (*mo
 		}
 	}()
 
+	if redo {
+		var redoVersion sql.NullInt64
+		err = tx.QueryRow(`SELECT redo_version FROM configurations WHERE name = ? AND deleted_at IS NULL`, name).Scan(&redoVersion)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, 0, &ConfigNotFoundError{ConfigName: name}
+			}
+			return nil, 0, fmt.Errorf("failed to get redo version: %w", err)
+		}
+		if !redoVersion.Valid {
+			return nil, 0, &CannotRedoError{ConfigName: name}
+		}
+		targetVersion = int(redoVersion.Int64)
+	}
+
 	// 1. Validate target version exists and get its data
-	var targetJsonData string
-	versionQuery := `SELECT json_data FROM versions WHERE configuration_name = ? AND version_number = ?`
-	err = tx.QueryRow(versionQuery, name, targetVersion).Scan(&targetJsonData)
+	var targetJsonData, targetFormat string
+	var targetOriginal sql.NullString
+	var targetStatus string
+	versionQuery := `
+		SELECT ` + versionDataColumn + `, v.format, v.original_data, v.status
+		FROM versions v ` + versionBlobJoin + `
+		WHERE v.configuration_name = ? AND v.version_number = ?`
+	err = tx.QueryRow(versionQuery, name, targetVersion).Scan(&targetJsonData, &targetFormat, &targetOriginal, &targetStatus)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, &VersionNotFoundError{ConfigName: name, Version: targetVersion}
+			return nil, 0, &VersionNotFoundError{ConfigName: name, Version: targetVersion}
 		}
-		return nil, fmt.Errorf("failed to get target version data: %w", err)
+		return nil, 0, fmt.Errorf("failed to get target version data: %w", err)
+	}
+
+	// Drafts become active only by being published
+	if targetStatus == models.VersionStatusDraft {
+		return nil, 0, &VersionIsDraftError{ConfigName: name, Version: targetVersion}
 	}
 
 	// 2. Get current version number and created_at
 	var currentVersion int
 	var createdAtStr string
-	configQuery := `SELECT current_version, created_at FROM configurations WHERE name = ?`
+	configQuery := `SELECT current_version, created_at FROM configurations WHERE name = ? AND deleted_at IS NULL`
 	err = tx.QueryRow(configQuery, name).Scan(&currentVersion, &createdAtStr)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, &ConfigNotFoundError{ConfigName: name}
+			return nil, 0, &ConfigNotFoundError{ConfigName: name}
 		}
-		return nil, fmt.Errorf("failed to get current version: %w", err)
+		return nil, 0, fmt.Errorf("failed to get current version: %w", err)
+	}
+
+	if err := s.checkContiguous(tx, name, currentVersion); err != nil {
+		return nil, 0, err
 	}
 
 	// Parse SQLite timestamp format using helper
 	createdAt, err := parseTimestamp(createdAtStr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		return nil, 0, fmt.Errorf("failed to parse created_at: %w", err)
 	}
 
-	// 3. Insert new version with target's JSON data
-	newVersion := currentVersion + 1
+	// 3. Insert new version sharing the target's data blob, and its original text
+	targetJsonData, err = decodeJSONData(targetJsonData)
+	if err != nil {
+		return nil, 0, err
+	}
+	blobID, err := s.storeBlob(tx, name, targetJsonData)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	newVersion, err := nextVersionNumber(tx, name, currentVersion)
+	if err != nil {
+		return nil, 0, err
+	}
 	now := time.Now()
 	insertVersionQuery := `
-		INSERT INTO versions (configuration_name, version_number, json_data, created_at)
-		VALUES (?, ?, ?, ?)`
+		INSERT INTO versions (configuration_name, version_number, json_data, blob_id, created_at, schema_hash, format, original_data, created_by)
+		VALUES (?, ?, '', ?, ?, ?, ?, ?, ?)`
 
-	_, err = tx.Exec(insertVersionQuery, name, newVersion, targetJsonData, now)
+	_, err = tx.Exec(insertVersionQuery, name, newVersion, blobID, formatTimestamp(now), nullIfEmpty(schemaHash), targetFormat, targetOriginal, nullIfEmpty(createdBy))
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert rollback version: %w", err)
+		if s.dialect.uniqueViolation(err, "versions") {
+			return nil, 0, &VersionConflictError{ConfigName: name, Version: newVersion}
+		}
+		return nil, 0, fmt.Errorf("failed to insert rollback version: %w", err)
 	}
 
-	// 4. Update configuration's current_version
-	updateQuery := `UPDATE configurations SET current_version = ?, updated_at = ? WHERE name = ?`
-	_, err = tx.Exec(updateQuery, newVersion, now, name)
+	// 4. Update configuration's current_version; a rollback can be redone, a redo cannot
+	var redoVersion interface{}
+	if !redo {
+		redoVersion = currentVersion
+	}
+	updateQuery := `UPDATE configurations SET current_version = ?, updated_at = ?, redo_version = ? WHERE name = ?`
+	_, err = tx.Exec(updateQuery, newVersion, formatTimestamp(now), redoVersion, name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update current version: %w", err)
+		return nil, 0, fmt.Errorf("failed to update current version: %w", err)
 	}
 
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.markWritten(name)
 
 	return &models.Configuration{
 		Name:           name,
 		CurrentVersion: newVersion,
 		CreatedAt:      createdAt,
 		UpdatedAt:      now,
-	}, nil
+	}, targetVersion, nil
 }
 
 // GetLatestConfiguration retrieves the latest version of a configuration
-func (s *SQLiteStore) GetLatestConfiguration(name string) (*models.Configuration, *models.Version, error) {
+func (s *sqlStore) GetLatestConfiguration(name string) (*models.Configuration, *models.Version, error) {
 	query := `
 		SELECT c.name, c.current_version, c.created_at, c.updated_at,
-		       v.id, v.version_number, v.json_data, v.created_at
+		       v.id, v.version_number, ` + versionDataColumn + `, v.created_at, v.format, v.original_data, v.created_by
 		FROM configurations c
 		JOIN versions v ON c.name = v.configuration_name AND c.current_version = v.version_number
-		WHERE c.name = ?`
+		` + versionBlobJoin + `
+		WHERE c.name = ? AND c.deleted_at IS NULL`
 
 	var config models.Configuration
 	var version models.Version
 	var configCreatedAtStr, configUpdatedAtStr, versionCreatedAtStr string
+	var originalData, createdBy sql.NullString
 
-	err := s.db.QueryRow(query, name).Scan(
+	err := s.reader(name).QueryRow(query, name).Scan(
 		&config.Name, &config.CurrentVersion, &configCreatedAtStr, &configUpdatedAtStr,
 		&version.ID, &version.VersionNumber, &version.JsonData, &versionCreatedAtStr,
+		&version.Format, &originalData, &createdBy,
 	)
 
 	if err != nil {
@@ -238,22 +352,32 @@ func (s *SQLiteStore) GetLatestConfiguration(name string) (*models.Configuration
 		return nil, nil, fmt.Errorf("failed to parse version created_at: %w", err)
 	}
 
+	version.JsonData, err = decodeJSONData(version.JsonData)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	version.ConfigurationName = name
+	version.OriginalData = originalData.String
+	version.CreatedBy = createdBy.String
 	return &config, &version, nil
 }
 
 // GetConfigurationVersion retrieves a specific version of a configuration
-func (s *SQLiteStore) GetConfigurationVersion(name string, versionNumber int) (*models.Version, error) {
+func (s *sqlStore) GetConfigurationVersion(name string, versionNumber int) (*models.Version, error) {
 	query := `
-		SELECT id, configuration_name, version_number, json_data, created_at
-		FROM versions 
-		WHERE configuration_name = ? AND version_number = ?`
+		SELECT v.id, v.configuration_name, v.version_number, ` + versionDataColumn + `, v.created_at, v.format, v.original_data, v.status, v.created_by
+		FROM versions v
+		JOIN configurations c ON c.name = v.configuration_name AND c.deleted_at IS NULL
+		` + versionBlobJoin + `
+		WHERE v.configuration_name = ? AND v.version_number = ?`
 
 	var version models.Version
 	var createdAtStr string
-	err := s.db.QueryRow(query, name, versionNumber).Scan(
+	var originalData, createdBy sql.NullString
+	err := s.reader(name).QueryRow(query, name, versionNumber).Scan(
 		&version.ID, &version.ConfigurationName, &version.VersionNumber,
-		&version.JsonData, &createdAtStr,
+		&version.JsonData, &createdAtStr, &version.Format, &originalData, &version.Status, &createdBy,
 	)
 
 	if err != nil {
@@ -269,16 +393,23 @@ func (s *SQLiteStore) GetConfigurationVersion(name string, versionNumber int) (*
 		return nil, fmt.Errorf("failed to parse created_at: %w", err)
 	}
 
+	version.JsonData, err = decodeJSONData(version.JsonData)
+	if err != nil {
+		return nil, err
+	}
+	version.OriginalData = originalData.String
+	version.CreatedBy = createdBy.String
+
 	return &version, nil
 }
 
 // ListVersions retrieves all versions of a configuration
-func (s *SQLiteStore) ListVersions(name string) (*models.Configuration, []models.Version, error) {
+func (s *sqlStore) ListVersions(name string) (*models.Configuration, []models.Version, error) {
 	// First check if configuration exists
 	var config models.Configuration
 	var createdAtStr, updatedAtStr string
-	configQuery := `SELECT name, current_version, created_at, updated_at FROM configurations WHERE name = ?`
-	err := s.db.QueryRow(configQuery, name).Scan(
+	configQuery := `SELECT name, current_version, created_at, updated_at FROM configurations WHERE name = ? AND deleted_at IS NULL`
+	err := s.reader(name).QueryRow(configQuery, name).Scan(
 		&config.Name, &config.CurrentVersion, &createdAtStr, &updatedAtStr,
 	)
 	if err != nil {
@@ -301,12 +432,12 @@ func (s *SQLiteStore) ListVersions(name string) (*models.Configuration, []models
 
 	// Get all versions ordered by version number descending
 	versionsQuery := `
-		SELECT id, configuration_name, version_number, json_data, created_at
-		FROM versions 
-		WHERE configuration_name = ?
-		ORDER BY version_number DESC`
+		SELECT v.id, v.configuration_name, v.version_number, ` + versionDataColumn + `, v.created_at, v.protected, v.status, v.created_by
+		FROM versions v ` + versionBlobJoin + `
+		WHERE v.configuration_name = ?
+		ORDER BY v.version_number DESC`
 
-	rows, err := s.db.Query(versionsQuery, name)
+	rows, err := s.reader(name).Query(versionsQuery, name)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to query versions: %w", err)
 	}
@@ -320,9 +451,10 @@ func (s *SQLiteStore) ListVersions(name string) (*models.Configuration, []models
 	for rows.Next() {
 		var version models.Version
 		var versionCreatedAtStr string
+		var createdBy sql.NullString
 		err := rows.Scan(
 			&version.ID, &version.ConfigurationName, &version.VersionNumber,
-			&version.JsonData, &versionCreatedAtStr,
+			&version.JsonData, &versionCreatedAtStr, &version.Protected, &version.Status, &createdBy,
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to scan version: %w", err)
@@ -334,6 +466,12 @@ func (s *SQLiteStore) ListVersions(name string) (*models.Configuration, []models
 			return nil, nil, fmt.Errorf("failed to parse version created_at: %w", err)
 		}
 
+		version.JsonData, err = decodeJSONData(version.JsonData)
+		if err != nil {
+			return nil, nil, err
+		}
+		version.CreatedBy = createdBy.String
+
 		versions = append(versions, version)
 	}
 
@@ -344,23 +482,673 @@ func (s *SQLiteStore) ListVersions(name string) (*models.Configuration, []models
 	return &config, versions, nil
 }
 
-// parseTimestamp parses SQLite timestamp strings with fallback formats
-func parseTimestamp(timestampStr string) (time.Time, error) {
-	// Try different SQLite timestamp formats
-	formats := []string{
-		"2006-01-02 15:04:05.999999999-07:00", // Full format with timezone
-		"2006-01-02 15:04:05.999999999",       // Without timezone
-		"2006-01-02 15:04:05",                 // Simple format
-		time.RFC3339,                          // ISO format
+// ConfigurationsExist reports for each name whether a configuration with that name exists
+func (s *sqlStore) ConfigurationsExist(names []string) (map[string]bool, error) {
+	exists := make(map[string]bool, len(names))
+	if len(names) == 0 {
+		return exists, nil
+	}
+
+	placeholders := make([]string, len(names))
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		placeholders[i] = "?"
+		args[i] = name
+		exists[name] = false
+	}
+
+	query := `SELECT name FROM configurations WHERE deleted_at IS NULL AND name IN (` + strings.Join(placeholders, ", ") + `)`
+	rows, err := s.reader("").Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query configurations: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan configuration name: %w", err)
+		}
+		exists[name] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating configurations: %w", err)
+	}
+
+	return exists, nil
+}
+
+// configurationSortColumns whitelists the fields ListConfigurations can sort by and maps them to columns
+var configurationSortColumns = map[string]string{
+	"name":       "name",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"version":    "current_version",
+}
+
+// ListConfigurations retrieves the page of configurations selected by limit and offset, ordered
+// by sortField with ties broken by name, along with the total number of configurations. A zero
+// limit returns every configuration from offset onwards. Soft-deleted configurations are skipped
+// unless includeDeleted is set, and with tag selectors only configurations carrying all of them
+// are listed and counted.
+func (s *sqlStore) ListConfigurations(sortField string, descending, includeDeleted bool, tags []models.TagSelector, limit, offset int) ([]models.Configuration, int, error) {
+	column, ok := configurationSortColumns[sortField]
+	if !ok {
+		return nil, 0, &InvalidSortFieldError{Field: sortField}
+	}
+
+	tagCondition, tagArgs := tagFilter(tags)
+	filterArgs := append([]interface{}{includeDeleted}, tagArgs...)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM configurations WHERE (deleted_at IS NULL OR ?)` + tagCondition
+	if err := s.reader("").QueryRow(countQuery, filterArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count configurations: %w", err)
+	}
+
+	var limitArg interface{} = limit
+	if limit <= 0 {
+		limitArg = s.dialect.noLimit()
+	}
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+
+	// column and direction come from fixed whitelists, never from the request
+	query := fmt.Sprintf(`
+		SELECT name, current_version, created_at, updated_at, deleted_at
+		FROM configurations
+		WHERE (deleted_at IS NULL OR ?)%s
+		ORDER BY %s %s, name ASC
+		LIMIT ? OFFSET ?`, tagCondition, column, direction)
+
+	rows, err := s.reader("").Query(query, append(filterArgs, limitArg, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query configurations: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	configs := []models.Configuration{}
+	for rows.Next() {
+		var config models.Configuration
+		var createdAtStr, updatedAtStr string
+		var deletedAtStr sql.NullString
+		if err := rows.Scan(&config.Name, &config.CurrentVersion, &createdAtStr, &updatedAtStr, &deletedAtStr); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan configuration: %w", err)
+		}
+
+		config.CreatedAt, err = parseTimestamp(createdAtStr)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to parse config created_at: %w", err)
+		}
+
+		config.UpdatedAt, err = parseTimestamp(updatedAtStr)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to parse config updated_at: %w", err)
+		}
+
+		if deletedAtStr.Valid {
+			deletedAt, err := parseTimestamp(deletedAtStr.String)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to parse config deleted_at: %w", err)
+			}
+			config.DeletedAt = &deletedAt
+		}
+
+		configs = append(configs, config)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating configurations: %w", err)
+	}
+
+	return configs, total, nil
+}
+
+// ListLatestVersions retrieves the current version of every configuration in a single query, ordered by name
+func (s *sqlStore) ListLatestVersions() ([]models.Version, error) {
+	var versions []models.Version
+	err := s.EachLatestVersion(func(version models.Version) error {
+		versions = append(versions, version)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// EachLatestVersion calls fn with the current version of every configuration, ordered by name,
+// as rows are read from a single query, so callers can stream without holding every version.
+// An error from fn stops the iteration and is returned.
+func (s *sqlStore) EachLatestVersion(fn func(models.Version) error) error {
+	query := `
+		SELECT v.id, v.configuration_name, v.version_number, ` + versionDataColumn + `, v.created_at, v.format
+		FROM configurations c
+		JOIN versions v ON c.name = v.configuration_name AND c.current_version = v.version_number
+		` + versionBlobJoin + `
+		WHERE c.deleted_at IS NULL
+		ORDER BY c.name`
+
+	rows, err := s.reader("").Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to query latest versions: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var version models.Version
+		var createdAtStr string
+		err := rows.Scan(
+			&version.ID, &version.ConfigurationName, &version.VersionNumber,
+			&version.JsonData, &createdAtStr, &version.Format,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan version: %w", err)
+		}
+
+		version.CreatedAt, err = parseTimestamp(createdAtStr)
+		if err != nil {
+			return fmt.Errorf("failed to parse version created_at: %w", err)
+		}
+
+		version.JsonData, err = decodeJSONData(version.JsonData)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(version); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating latest versions: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteConfigurationsByPrefix soft-deletes every live configuration whose name starts with
+// prefix in a single transaction, keeping their versions for audit.
+// Returns the names of the deleted configurations.
+func (s *sqlStore) DeleteConfigurationsByPrefix(prefix string) ([]string, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	// substr comparison avoids LIKE wildcard semantics for '_' which is valid in names
+	rows, err := tx.Query(`SELECT name FROM configurations WHERE substr(name, 1, ?) = ? AND deleted_at IS NULL ORDER BY name`, len(prefix), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query configurations: %w", err)
+	}
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan configuration name: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close rows: %w", err)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating configurations: %w", err)
+	}
+
+	// Deleting is all or nothing, so one protected version refuses the whole delete
+	for _, name := range names {
+		protected, err := lowestProtectedVersion(tx, name)
+		if err != nil {
+			return nil, err
+		}
+		if protected > 0 {
+			return nil, &VersionProtectedError{ConfigName: name, Version: protected, Operation: "delete"}
+		}
+	}
+
+	deletedAt := formatTimestamp(time.Now())
+	for _, name := range names {
+		if _, err := tx.Exec(`UPDATE configurations SET deleted_at = ? WHERE name = ?`, deletedAt, name); err != nil {
+			return nil, fmt.Errorf("failed to delete configuration: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.markWritten(names...)
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, timestampStr); err == nil {
-			return t, nil
+	return names, nil
+}
+
+// SquashConfiguration deletes every version below keepFrom and renumbers the remaining versions
+// from 1, adjusting current_version and any pending redo target, and records the squash in
+// squash_log, all in one transaction
+func (s *sqlStore) SquashConfiguration(name string, keepFrom int, actor string) (*models.SquashResult, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	var currentVersion int
+	var redoVersion sql.NullInt64
+	err = tx.QueryRow(`SELECT current_version, redo_version FROM configurations WHERE name = ? AND deleted_at IS NULL`, name).Scan(&currentVersion, &redoVersion)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &ConfigNotFoundError{ConfigName: name}
+		}
+		return nil, fmt.Errorf("failed to query configuration: %w", err)
+	}
+
+	// The current version must survive the squash
+	if keepFrom > currentVersion {
+		return nil, &VersionNotFoundError{ConfigName: name, Version: keepFrom}
+	}
+
+	// Squashing removes the versions below keepFrom and renumbers the rest, so it would alter any
+	// protected version; squashing from version 1 changes nothing
+	if keepFrom > 1 {
+		protected, err := lowestProtectedVersion(tx, name)
+		if err != nil {
+			return nil, err
+		}
+		if protected > 0 {
+			return nil, &VersionProtectedError{ConfigName: name, Version: protected, Operation: "squash"}
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM versions WHERE configuration_name = ? AND version_number < ?`, name, keepFrom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete squashed versions: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count squashed versions: %w", err)
+	}
+	if err := deleteUnreferencedBlobs(tx); err != nil {
+		return nil, err
+	}
+
+	// Renumber through negative numbers so no intermediate row collides on (name, version_number)
+	shift := keepFrom - 1
+	negateQuery := `UPDATE versions SET version_number = -(version_number - ?) WHERE configuration_name = ?`
+	if _, err := tx.Exec(negateQuery, shift, name); err != nil {
+		return nil, fmt.Errorf("failed to renumber versions: %w", err)
+	}
+	restoreQuery := `UPDATE versions SET version_number = -version_number WHERE configuration_name = ?`
+	if _, err := tx.Exec(restoreQuery, name); err != nil {
+		return nil, fmt.Errorf("failed to renumber versions: %w", err)
+	}
+
+	// A redo target below the cutoff no longer exists
+	var newRedoVersion interface{}
+	if redoVersion.Valid && int(redoVersion.Int64) >= keepFrom {
+		newRedoVersion = int(redoVersion.Int64) - shift
+	}
+
+	newCurrentVersion := currentVersion - shift
+	now := time.Now()
+	updateQuery := `UPDATE configurations SET current_version = ?, redo_version = ?, updated_at = ? WHERE name = ?`
+	if _, err := tx.Exec(updateQuery, newCurrentVersion, newRedoVersion, formatTimestamp(now), name); err != nil {
+		return nil, fmt.Errorf("failed to update current version: %w", err)
+	}
+
+	auditQuery := `
+		INSERT INTO squash_log (configuration_name, actor, keep_from, removed_versions,
+		                        previous_current_version, new_current_version, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.Exec(auditQuery, name, actor, keepFrom, removed, currentVersion, newCurrentVersion, formatTimestamp(now)); err != nil {
+		return nil, fmt.Errorf("failed to record squash: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	return &models.SquashResult{
+		Name:            name,
+		KeepFrom:        keepFrom,
+		RemovedVersions: int(removed),
+		PreviousVersion: currentVersion,
+		CurrentVersion:  newCurrentVersion,
+	}, nil
+}
+
+// RepairCurrentVersions finds configurations whose current_version has no matching version row
+// and resets current_version to the highest version number actually present.
+// Configurations without any version rows cannot be repaired and are reported unchanged.
+func (s *sqlStore) RepairCurrentVersions() ([]models.CurrentVersionRepair, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	query := `
+		SELECT c.name, c.current_version,
+		       (SELECT MAX(v.version_number) FROM versions v WHERE v.configuration_name = c.name AND v.status = 'published')
+		FROM configurations c
+		WHERE NOT EXISTS (
+			SELECT 1 FROM versions v
+			WHERE v.configuration_name = c.name AND v.version_number = c.current_version
+		)
+		ORDER BY c.name`
+
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query drifted configurations: %w", err)
+	}
+
+	repairs := []models.CurrentVersionRepair{}
+	for rows.Next() {
+		var repair models.CurrentVersionRepair
+		var maxVersion sql.NullInt64
+		if err := rows.Scan(&repair.Name, &repair.PreviousVersion, &maxVersion); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan drifted configuration: %w", err)
+		}
+
+		repair.CurrentVersion = repair.PreviousVersion
+		if maxVersion.Valid {
+			repair.CurrentVersion = int(maxVersion.Int64)
+			repair.Repaired = true
+		}
+		repairs = append(repairs, repair)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close rows: %w", err)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating drifted configurations: %w", err)
+	}
+
+	now := time.Now()
+	var repairedNames []string
+	for _, repair := range repairs {
+		if !repair.Repaired {
+			continue
+		}
+
+		updateQuery := `UPDATE configurations SET current_version = ?, updated_at = ? WHERE name = ?`
+		if _, err := tx.Exec(updateQuery, repair.CurrentVersion, formatTimestamp(now), repair.Name); err != nil {
+			return nil, fmt.Errorf("failed to repair current version: %w", err)
+		}
+		repairedNames = append(repairedNames, repair.Name)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(repairedNames...)
+
+	return repairs, nil
+}
+
+// SaveSchema records a schema document under its hash; saving a known schema is a no-op
+func (s *sqlStore) SaveSchema(hash, schemaJSON string) error {
+	query := `INSERT INTO schemas (hash, schema_json, created_at) VALUES (?, ?, ?) ON CONFLICT (hash) DO NOTHING`
+	_, err := s.db.Exec(query, hash, schemaJSON, formatTimestamp(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to save schema: %w", err)
+	}
+	return nil
+}
+
+// GetVersionSchema retrieves the hash and document of the schema a version was created under
+func (s *sqlStore) GetVersionSchema(name string, versionNumber int) (string, string, error) {
+	query := `
+		SELECT v.schema_hash, sc.schema_json
+		FROM versions v
+		LEFT JOIN schemas sc ON sc.hash = v.schema_hash
+		WHERE v.configuration_name = ? AND v.version_number = ?`
+
+	var hash, schemaJSON sql.NullString
+	err := s.reader(name).QueryRow(query, name, versionNumber).Scan(&hash, &schemaJSON)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", &VersionNotFoundError{ConfigName: name, Version: versionNumber}
+		}
+		return "", "", fmt.Errorf("failed to get version schema: %w", err)
+	}
+
+	if !hash.Valid || !schemaJSON.Valid {
+		return "", "", &SchemaNotRecordedError{ConfigName: name, Version: versionNumber}
+	}
+
+	return hash.String, schemaJSON.String, nil
+}
+
+// GetStorageFootprint totals the stored data of every version of a configuration in a single
+// query. Sizes are bytes as stored, so compressed versions count at their
+// compressed size, and data shared through a blob counts once for each version using it.
+func (s *sqlStore) GetStorageFootprint(name string) (*models.StorageFootprint, error) {
+	query := `
+		SELECT c.name, COUNT(v.id), COALESCE(SUM(OCTET_LENGTH(` + versionDataColumn + `)), 0)
+		FROM configurations c
+		LEFT JOIN versions v ON v.configuration_name = c.name
+		` + versionBlobJoin + `
+		WHERE c.name = ? AND c.deleted_at IS NULL
+		GROUP BY c.name`
+
+	var footprint models.StorageFootprint
+	err := s.reader(name).QueryRow(query, name).Scan(&footprint.Name, &footprint.VersionCount, &footprint.TotalBytes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &ConfigNotFoundError{ConfigName: name}
+		}
+		return nil, fmt.Errorf("failed to get storage footprint: %w", err)
+	}
+
+	if footprint.VersionCount > 0 {
+		footprint.AverageBytesPerVersion = footprint.TotalBytes / int64(footprint.VersionCount)
+	}
+	return &footprint, nil
+}
+
+// GetConfigurationMeta retrieves a configuration's metadata from the configurations table alone,
+// without joining versions or reading any data
+func (s *sqlStore) GetConfigurationMeta(name string) (*models.ConfigurationMeta, error) {
+	query := `
+		SELECT name, current_version, created_at, updated_at, sensitive
+		FROM configurations
+		WHERE name = ? AND deleted_at IS NULL`
+
+	var meta models.ConfigurationMeta
+	var createdAtStr, updatedAtStr string
+
+	err := s.reader(name).QueryRow(query, name).Scan(
+		&meta.Name, &meta.CurrentVersion, &createdAtStr, &updatedAtStr, &meta.Sensitive,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &ConfigNotFoundError{ConfigName: name}
+		}
+		return nil, fmt.Errorf("failed to get configuration metadata: %w", err)
+	}
+
+	meta.CreatedAt, err = parseTimestamp(createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config created_at: %w", err)
+	}
+
+	meta.UpdatedAt, err = parseTimestamp(updatedAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config updated_at: %w", err)
+	}
+
+	return &meta, nil
+}
+
+// RenameConfiguration moves a configuration to newName with its versions, access log and squash
+// log in one transaction. The row under the new name is inserted before the versions are moved
+// and the old row deleted after, so the versions' foreign key always has a target.
+func (s *sqlStore) RenameConfiguration(name, newName string) (*models.ConfigurationMeta, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	var exists int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM configurations WHERE name = ? AND deleted_at IS NULL`, name).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query configuration: %w", err)
+	}
+	if exists == 0 {
+		return nil, &ConfigNotFoundError{ConfigName: name}
+	}
+
+	insertQuery := `
+		INSERT INTO configurations (name, current_version, created_at, updated_at, sensitive, redo_version)
+		SELECT CAST(? AS TEXT), current_version, created_at, updated_at, sensitive, redo_version
+		FROM configurations WHERE name = ?`
+	if _, err := tx.Exec(insertQuery, newName, name); err != nil {
+		if s.dialect.uniqueViolation(err, "configurations") {
+			return nil, &ConfigAlreadyExistsError{ConfigName: newName}
+		}
+		return nil, fmt.Errorf("failed to insert renamed configuration: %w", err)
+	}
+
+	for _, table := range []string{"versions", "access_log", "squash_log", "tags"} {
+		query := `UPDATE ` + table + ` SET configuration_name = ? WHERE configuration_name = ?`
+		if _, err := tx.Exec(query, newName, name); err != nil {
+			return nil, fmt.Errorf("failed to rename configuration in %s: %w", table, err)
 		}
 	}
 
-	return time.Time{}, fmt.Errorf("unable to parse timestamp: %s", timestampStr)
+	if _, err := tx.Exec(`DELETE FROM configurations WHERE name = ?`, name); err != nil {
+		return nil, fmt.Errorf("failed to delete configuration: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name, newName)
+
+	return s.GetConfigurationMeta(newName)
+}
+
+// SetConfigurationSensitive flags or unflags a configuration as sensitive
+func (s *sqlStore) SetConfigurationSensitive(name string, sensitive bool) error {
+	flag := 0
+	if sensitive {
+		flag = 1
+	}
+	result, err := s.db.Exec(`UPDATE configurations SET sensitive = ? WHERE name = ? AND deleted_at IS NULL`, flag, name)
+	if err != nil {
+		return fmt.Errorf("failed to update sensitive flag: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return &ConfigNotFoundError{ConfigName: name}
+	}
+	s.markWritten(name)
+
+	return nil
+}
+
+// IsConfigurationSensitive reports whether a configuration is flagged as sensitive
+func (s *sqlStore) IsConfigurationSensitive(name string) (bool, error) {
+	var sensitive bool
+	err := s.reader(name).QueryRow(`SELECT sensitive FROM configurations WHERE name = ?`, name).Scan(&sensitive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, &ConfigNotFoundError{ConfigName: name}
+		}
+		return false, fmt.Errorf("failed to query sensitive flag: %w", err)
+	}
+
+	return sensitive, nil
+}
+
+// RecordAccess appends an entry to the access log
+func (s *sqlStore) RecordAccess(name, actor, action string) error {
+	query := `
+		INSERT INTO access_log (configuration_name, actor, action, created_at)
+		VALUES (?, ?, ?, ?)`
+
+	if _, err := s.db.Exec(query, name, actor, action, formatTimestamp(time.Now())); err != nil {
+		return fmt.Errorf("failed to record access: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeAccessLog deletes access log entries created before cutoff and returns how many were removed
+func (s *sqlStore) PurgeAccessLog(cutoff time.Time) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM access_log WHERE created_at < ?`, formatTimestamp(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge access log: %w", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return removed, nil
+}
+
+// timestampLayout is the one format timestamps are stored in: UTC RFC3339 with a fixed nine-digit
+// fraction, so stored values also sort chronologically as text
+const timestampLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// formatTimestamp renders t for storage. Timestamps are always written by the application in this
+// form rather than left to column defaults or the driver's own time formatting.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+// parseTimestamp parses a timestamp read from the database. Stored values are in timestampLayout,
+// and the driver hands DATETIME columns to string scans as RFC3339, so one format covers both.
+func parseTimestamp(timestampStr string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, timestampStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse timestamp: %s", timestampStr)
+	}
+	return t, nil
 }
 
 // Error types for specific database errors
@@ -389,9 +1177,89 @@ func (e *VersionNotFoundError) Error() string {
 	return fmt.Sprintf("VERSION_NOT_FOUND: Version %d not found for configuration '%s'", e.Version, e.ConfigName)
 }
 
-// isUniqueConstraintError checks if the error is due to unique constraint violation
-func isUniqueConstraintError(err error) bool {
-	return err != nil &&
-		(err.Error() == "UNIQUE constraint failed: configurations.name" ||
-			err.Error() == "constraint failed: UNIQUE constraint failed: configurations.name")
+// InvalidSortFieldError is returned when listing configurations by a field that is not sortable
+type InvalidSortFieldError struct {
+	Field string
+}
+
+func (e *InvalidSortFieldError) Error() string {
+	return fmt.Sprintf("INVALID_SORT_FIELD: Cannot sort by '%s'; use one of name, created_at, updated_at, version", e.Field)
+}
+
+// CannotRedoError is returned when there is no rollback to redo, or the configuration was edited since
+type CannotRedoError struct {
+	ConfigName string
+}
+
+func (e *CannotRedoError) Error() string {
+	return fmt.Sprintf("CANNOT_REDO: Configuration '%s' has no rollback to redo since its last edit", e.ConfigName)
+}
+
+// VersionGapError is returned by writes, when contiguity is enforced, to a configuration whose
+// versions do not run from 1 to current_version without gaps
+type VersionGapError struct {
+	ConfigName     string
+	CurrentVersion int
+}
+
+func (e *VersionGapError) Error() string {
+	return fmt.Sprintf("VERSION_SEQUENCE_GAP: Versions of configuration '%s' are not contiguous from 1 to %d", e.ConfigName, e.CurrentVersion)
+}
+
+// SchemaNotRecordedError is returned for versions created before schemas were recorded
+type SchemaNotRecordedError struct {
+	ConfigName string
+	Version    int
+}
+
+func (e *SchemaNotRecordedError) Error() string {
+	return fmt.Sprintf("SCHEMA_NOT_RECORDED: No schema was recorded for version %d of configuration '%s'", e.Version, e.ConfigName)
+}
+
+// VersionConflictError is returned when a concurrent write claimed the same version number;
+// the write did not happen and can be retried
+type VersionConflictError struct {
+	ConfigName string
+	Version    int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("VERSION_CONFLICT: Version %d of configuration '%s' was created concurrently, retry the request", e.Version, e.ConfigName)
+}
+
+// StaleVersionError is returned by a compare-and-swap write when the configuration's current
+// version is no longer the version the client based its change on
+type StaleVersionError struct {
+	ConfigName      string
+	ExpectedVersion int
+	CurrentVersion  int
+}
+
+func (e *StaleVersionError) Error() string {
+	return fmt.Sprintf("VERSION_CONFLICT: Configuration '%s' is at version %d, not the expected version %d", e.ConfigName, e.CurrentVersion, e.ExpectedVersion)
+}
+
+// checkExpectedVersion fails with StaleVersionError unless expectedVersion is 0 or currentVersion
+func checkExpectedVersion(name string, expectedVersion, currentVersion int) error {
+	if expectedVersion != 0 && expectedVersion != currentVersion {
+		return &StaleVersionError{ConfigName: name, ExpectedVersion: expectedVersion, CurrentVersion: currentVersion}
+	}
+	return nil
+}
+
+// originalColumns returns the format and original_data values for a new version; data
+// submitted as JSON has no original text
+func originalColumns(original *models.OriginalData) (string, interface{}) {
+	if original == nil {
+		return "json", nil
+	}
+	return original.Format, original.Text
+}
+
+// nullIfEmpty stores empty strings as NULL
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
 }
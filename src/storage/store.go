@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"config-manager/src/models"
+)
+
+// Store is the configuration storage used by the services, implemented by SQLiteStore and
+// PostgresStore. Every write runs in a single transaction, and concurrent writers are
+// serialized so version numbers never race.
+type Store interface {
+	CreateConfiguration(name, jsonData, schemaHash string, original *models.OriginalData, createdBy string) (*models.Configuration, error)
+	CreateConfigurationsBatch(items []NewConfiguration, atomic, dryRun bool) ([]*models.Configuration, []error, error)
+	UpdateConfiguration(name, jsonData, schemaHash string, original *models.OriginalData, createdBy string) (*models.Configuration, error)
+	UpdateConfigurationIfCurrent(name, jsonData, schemaHash string, original *models.OriginalData, expectedVersion int, createdBy string) (*models.Configuration, error)
+	RollbackConfiguration(name string, targetVersion int, schemaHash, createdBy string) (*models.Configuration, error)
+	RedoConfiguration(name, schemaHash, createdBy string) (*models.Configuration, int, error)
+	CreateDraftVersion(name, jsonData, schemaHash string, original *models.OriginalData, expectedVersion int, createdBy string) (*models.Version, int, error)
+	PublishVersion(name string, versionNumber int) (*models.Configuration, int, error)
+	LatestDraftVersion(name string) (int, error)
+	ProtectVersion(name string, versionNumber int) error
+	SquashConfiguration(name string, keepFrom int, actor string) (*models.SquashResult, error)
+	PruneVersions(name string, keep int) (*models.PruneResult, error)
+	RenameConfiguration(name, newName string) (*models.ConfigurationMeta, error)
+	SoftDeleteConfiguration(name string) (*models.Configuration, error)
+	RestoreConfiguration(name string) (*models.Configuration, error)
+	DeleteConfigurationsByPrefix(prefix string) ([]string, error)
+	ImportAll(configs []models.ExportedConfiguration, schemaHash, mode string, dryRun bool) (*models.ImportResult, error)
+
+	GetLatestConfiguration(name string) (*models.Configuration, *models.Version, error)
+	GetConfigurationVersion(name string, versionNumber int) (*models.Version, error)
+	GetConfigurationMeta(name string) (*models.ConfigurationMeta, error)
+	GetRawVersionData(name string, versionNumber int) (*models.RawVersionData, error)
+	GetStorageFootprint(name string) (*models.StorageFootprint, error)
+	ListVersions(name string) (*models.Configuration, []models.Version, error)
+	ListConfigurations(sortField string, descending, includeDeleted bool, tags []models.TagSelector, limit, offset int) ([]models.Configuration, int, error)
+	ListLatestVersions() ([]models.Version, error)
+	EachLatestVersion(fn func(models.Version) error) error
+	ExportAll(fn func(models.ExportedConfiguration) error) error
+	ConfigurationsExist(names []string) (map[string]bool, error)
+
+	SaveSchema(hash, schemaJSON string) error
+	GetVersionSchema(name string, versionNumber int) (string, string, error)
+
+	SetConfigurationTags(name string, tags map[string]string) (map[string]string, error)
+	DeleteConfigurationTag(name, key string) error
+	GetConfigurationTags(name string) (map[string]string, error)
+
+	SetConfigurationSensitive(name string, sensitive bool) error
+	IsConfigurationSensitive(name string) (bool, error)
+	RecordAccess(name, actor, action string) error
+	PurgeAccessLog(cutoff time.Time) (int64, error)
+
+	SetDefaultConfig(name, jsonData string) (*models.DefaultConfig, error)
+	DeleteDefaultConfig(name string) error
+	GetDefaultConfig(name string) (jsonData, appliedName string, err error)
+
+	SetConfigBudget(budget models.ConfigBudget) (*models.ConfigBudget, error)
+	DeleteConfigBudget(name string) error
+	ListConfigBudgets() ([]models.ConfigBudget, error)
+
+	RecordBackup(record models.BackupRecord) error
+	ListBackups(limit int) ([]models.BackupRecord, error)
+
+	RepairCurrentVersions() ([]models.CurrentVersionRepair, error)
+	FindVersionGaps() ([]models.VersionGap, error)
+	VerifyBlobChecksums(afterID int64, limit int) ([]models.ChecksumMismatch, int64, int, error)
+	CountUnchecksummedVersions() (int, error)
+}
+
+// dialect holds what differs between the databases sqlStore runs its queries on
+type dialect interface {
+	// beginWrite runs first in every write transaction, serializing concurrent writers
+	beginWrite(tx *sql.Tx) error
+	// uniqueViolation reports whether err is a unique constraint violation on table
+	uniqueViolation(err error, table string) bool
+	// noLimit is the LIMIT argument that returns every row
+	noLimit() interface{}
+}
+
+// sqlStore implements Store with SQL shared by every backend; queries use ? placeholders
+// and the dialect covers the rest
+type sqlStore struct {
+	db      *sql.DB
+	dialect dialect
+
+	// readDB serves GET/list queries; it is the primary db unless a read replica is configured
+	readDB *sql.DB
+
+	// readAfterWriteWindow routes reads of a recently written configuration to the primary
+	// so clients observe their own writes despite replica lag
+	readAfterWriteWindow time.Duration
+	writesMu             sync.Mutex
+	recentWrites         map[string]time.Time
+
+	// compressStorage gzips version data blobs on write (see SetCompressStorage)
+	compressStorage bool
+
+	// enforceContiguity rejects writes to configurations with version gaps (see SetEnforceContiguity)
+	enforceContiguity bool
+}
+
+// newSQLStore creates a store writing to primary and reading from replica, which may be primary
+func newSQLStore(primary, replica *sql.DB, readAfterWriteWindow time.Duration, d dialect) *sqlStore {
+	return &sqlStore{
+		db:                   primary,
+		dialect:              d,
+		readDB:               replica,
+		readAfterWriteWindow: readAfterWriteWindow,
+		recentWrites:         make(map[string]time.Time),
+	}
+}
+
+// beginWrite begins a write transaction on the primary
+func (s *sqlStore) beginWrite() (*sql.Tx, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dialect.beginWrite(tx); err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("failed to lock for writing: %w", err)
+	}
+	return tx, nil
+}
+
+// reader picks the handle for reading the named configuration; an empty name
+// stands for reads spanning all configurations
+func (s *sqlStore) reader(name string) *sql.DB {
+	if s.readDB == s.db || s.readAfterWriteWindow <= 0 {
+		return s.readDB
+	}
+
+	s.writesMu.Lock()
+	defer s.writesMu.Unlock()
+
+	cutoff := time.Now().Add(-s.readAfterWriteWindow)
+	if name == "" {
+		for _, writtenAt := range s.recentWrites {
+			if writtenAt.After(cutoff) {
+				return s.db
+			}
+		}
+		return s.readDB
+	}
+
+	if writtenAt, ok := s.recentWrites[name]; ok && writtenAt.After(cutoff) {
+		return s.db
+	}
+	return s.readDB
+}
+
+// markWritten records a write to the named configuration and forgets expired writes
+func (s *sqlStore) markWritten(names ...string) {
+	if s.readDB == s.db || s.readAfterWriteWindow <= 0 {
+		return
+	}
+
+	s.writesMu.Lock()
+	defer s.writesMu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-s.readAfterWriteWindow)
+	for name, writtenAt := range s.recentWrites {
+		if !writtenAt.After(cutoff) {
+			delete(s.recentWrites, name)
+		}
+	}
+	for _, name := range names {
+		s.recentWrites[name] = now
+	}
+}
@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"config-manager/src/models"
+)
+
+// SetConfigurationTags sets the given tags on a configuration, replacing the value of keys it
+// already has and keeping its other tags, and returns the resulting tag set
+func (s *sqlStore) SetConfigurationTags(name string, tags map[string]string) (map[string]string, error) {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	if deleted, err := isDeleted(tx, name); err != nil {
+		return nil, err
+	} else if deleted {
+		return nil, &ConfigNotFoundError{ConfigName: name}
+	}
+
+	query := `
+		INSERT INTO tags (configuration_name, key, value) VALUES (?, ?, ?)
+		ON CONFLICT(configuration_name, key) DO UPDATE SET value = excluded.value`
+	for key, value := range tags {
+		if _, err := tx.Exec(query, name, key, value); err != nil {
+			return nil, fmt.Errorf("failed to store tag: %w", err)
+		}
+	}
+
+	current, err := queryTags(tx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	return current, nil
+}
+
+// DeleteConfigurationTag removes the tag with the given key from a configuration
+func (s *sqlStore) DeleteConfigurationTag(name, key string) error {
+	tx, err := s.beginWrite()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	if deleted, err := isDeleted(tx, name); err != nil {
+		return err
+	} else if deleted {
+		return &ConfigNotFoundError{ConfigName: name}
+	}
+
+	result, err := tx.Exec(`DELETE FROM tags WHERE configuration_name = ? AND key = ?`, name, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return &TagNotFoundError{ConfigName: name, Key: key}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.markWritten(name)
+
+	return nil
+}
+
+// GetConfigurationTags retrieves the tags of a configuration, empty when it has none
+func (s *sqlStore) GetConfigurationTags(name string) (map[string]string, error) {
+	var exists int
+	err := s.reader(name).QueryRow(`SELECT COUNT(*) FROM configurations WHERE name = ? AND deleted_at IS NULL`, name).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query configuration: %w", err)
+	}
+	if exists == 0 {
+		return nil, &ConfigNotFoundError{ConfigName: name}
+	}
+
+	return queryTags(s.reader(name), name)
+}
+
+// allTags reads the tags of every configuration, keyed by configuration name
+func allTags(q queryer) (map[string]map[string]string, error) {
+	rows, err := q.Query(`SELECT configuration_name, key, value FROM tags`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	tags := map[string]map[string]string{}
+	for rows.Next() {
+		var name, key, value string
+		if err := rows.Scan(&name, &key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		if tags[name] == nil {
+			tags[name] = map[string]string{}
+		}
+		tags[name][key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// queryer runs queries on a database or within a transaction
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryTags reads the tags of a configuration
+func queryTags(q queryer, name string) (map[string]string, error) {
+	rows, err := q.Query(`SELECT key, value FROM tags WHERE configuration_name = ?`, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	tags := map[string]string{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// tagFilter builds the condition restricting the configurations table to those carrying every
+// selected tag, and its arguments; it is empty without selectors
+func tagFilter(selectors []models.TagSelector) (string, []interface{}) {
+	var conditions strings.Builder
+	args := make([]interface{}, 0, 2*len(selectors))
+	for _, selector := range selectors {
+		conditions.WriteString(` AND EXISTS (SELECT 1 FROM tags t WHERE t.configuration_name = configurations.name AND t.key = ? AND t.value = ?)`)
+		args = append(args, selector.Key, selector.Value)
+	}
+	return conditions.String(), args
+}
+
+// TagNotFoundError is returned when a configuration has no tag with the given key
+type TagNotFoundError struct {
+	ConfigName string
+	Key        string
+}
+
+func (e *TagNotFoundError) Error() string {
+	return fmt.Sprintf("TAG_NOT_FOUND: Configuration '%s' has no tag '%s'", e.ConfigName, e.Key)
+}
@@ -1,16 +1,32 @@
+//go:build sqlite || !postgres
+
 package contract
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"config-manager/src/handlers"
+	appmiddleware "config-manager/src/middleware"
+	"config-manager/src/models"
+	"config-manager/src/server"
 	"config-manager/src/services"
 	"config-manager/src/storage"
+	"config-manager/tests/testutil"
 
 	"github.com/labstack/echo/v4"
 	_ "github.com/mattn/go-sqlite3"
@@ -22,43 +38,10 @@ func setupTestServer(t *testing.T) (*echo.Echo, func()) {
 	// Create temporary test database
 	testDB := "./test_contract.db"
 
-	// Remove existing test database
-	if err := os.Remove(testDB); err != nil && !os.IsNotExist(err) {
-		t.Fatalf("Failed to remove test database: %v", err)
-	}
-
-	db, err := sql.Open("sqlite3", testDB)
-	if err != nil {
-		t.Fatal("Failed to open test database:", err)
-	}
-
-	// Create tables using the exact schema from migrations
-	schema := `
-	CREATE TABLE configurations (
-		name TEXT PRIMARY KEY,
-		current_version INTEGER NOT NULL,
-		created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-		updated_at TEXT DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE versions (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		configuration_name TEXT NOT NULL,
-		version_number INTEGER NOT NULL,
-		json_data TEXT NOT NULL,
-		created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (configuration_name) REFERENCES configurations(name),
-		UNIQUE(configuration_name, version_number)
-	);
-
-	CREATE INDEX idx_configurations_name ON configurations(name);
-	CREATE INDEX idx_versions_config_version ON versions(configuration_name, version_number);
-	CREATE INDEX idx_versions_config_created ON versions(configuration_name, created_at DESC);
-	`
-
-	_, err = db.Exec(schema)
+	// Create tables by applying the real migrations
+	db, err := testutil.OpenMigratedDB(testDB)
 	if err != nil {
-		t.Fatal("Failed to create schema:", err)
+		t.Fatal("Failed to create test database:", err)
 	}
 
 	// Initialize services
@@ -69,18 +52,62 @@ func setupTestServer(t *testing.T) (*echo.Echo, func()) {
 
 	sqliteStore := storage.NewSQLiteStore(db)
 	configService := services.NewConfigService(sqliteStore, validationService)
+	// Only configurations flagged sensitive are audited, so other tests are unaffected
+	configService.SetAccessLogEnabled(true)
 	configHandler := handlers.NewConfigHandler(configService)
 
 	// Create Echo instance and register routes
 	e := echo.New()
-	api := e.Group("/api/v1")
+	e.Use(appmiddleware.RequestID())
+	api := e.Group("/api/v1", appmiddleware.CacheControl(appmiddleware.CacheRevalidate),
+		appmiddleware.ConfigRequestBudget(configService.AllowRequest))
 
+	api.GET("/configs", configHandler.ListConfigs)
+	api.GET("/configs/all", configHandler.ListAllLatestConfigs)
+	api.GET("/diff", configHandler.CompareConfigs)
 	api.POST("/configs", configHandler.CreateConfig)
+	api.POST("/configs\\:exists", configHandler.ConfigsExist)
+	api.POST("/configs/sync", configHandler.SyncConfigs)
+	api.POST("/configs/batch", configHandler.CreateConfigsBatch)
+	api.DELETE("/configs", configHandler.DeleteConfigs)
 	api.PUT("/configs/:name", configHandler.UpdateConfig)
+	api.PATCH("/configs/:name", configHandler.PatchConfig)
+	api.PUT("/configs/:name/sensitive", configHandler.SetSensitive)
+	api.DELETE("/configs/:name", configHandler.DeleteConfig)
+	api.POST("/configs/:name/restore", configHandler.RestoreConfig)
 	api.POST("/configs/:name/rollback", configHandler.RollbackConfig)
+	api.POST("/configs/:name/undo", configHandler.UndoConfig)
+	api.POST("/configs/:name/redo", configHandler.RedoConfig)
+	api.POST("/configs/:name/squash", configHandler.SquashConfig)
+	api.POST("/configs/:name/prune", configHandler.PruneVersions)
+	api.POST("/configs/:name/rename", configHandler.RenameConfig)
 	api.GET("/configs/:name", configHandler.GetLatestConfig)
 	api.GET("/configs/:name/versions/:version", configHandler.GetConfigVersion)
+	api.GET("/configs/:name/meta", configHandler.GetConfigMeta)
+	api.GET("/configs/:name/storage", configHandler.GetConfigStorage)
+	api.GET("/configs/:name/diff", configHandler.DiffVersions)
+	api.GET("/configs/:name/evaluate", configHandler.EvaluateConfig)
+	api.POST("/configs/:name/validate", configHandler.ValidateConfig)
+	api.GET("/configs/:name/versions/:version/schema", configHandler.GetVersionSchema)
+	api.POST("/configs/:name/versions/:version/protect", configHandler.ProtectVersion)
+	api.POST("/configs/:name/versions/:version/publish", configHandler.PublishVersion)
 	api.GET("/configs/:name/versions", configHandler.ListVersions)
+	api.GET("/configs/:name/duplicates", configHandler.ListDuplicateVersions)
+	api.GET("/configs/:name/tags", configHandler.GetConfigTags)
+	api.PUT("/configs/:name/tags", configHandler.SetConfigTags)
+	api.DELETE("/configs/:name/tags/:key", configHandler.DeleteConfigTag)
+	api.GET("/export", configHandler.ExportConfigs)
+	api.POST("/import", configHandler.ImportConfigs)
+	api.GET("/export/env", configHandler.ExportEnvironment)
+	e.POST("/rpc", configHandler.RPC)
+	e.PUT("/admin/defaults", configHandler.SetDefaultConfig)
+	e.PUT("/admin/defaults/:name", configHandler.SetDefaultConfig)
+	e.DELETE("/admin/defaults/:name", configHandler.DeleteDefaultConfig)
+	e.GET("/admin/configs/:name/versions/:version/raw", configHandler.GetRawVersionData)
+	e.POST("/admin/schema/impact", configHandler.SchemaImpact)
+	e.GET("/admin/budgets", configHandler.ListConfigBudgets)
+	e.PUT("/admin/budgets/:name", configHandler.SetConfigBudget)
+	e.DELETE("/admin/budgets/:name", configHandler.DeleteConfigBudget)
 
 	// Return cleanup function
 	cleanup := func() {
@@ -181,6 +208,72 @@ func TestCreateConfigInvalidNameError(t *testing.T) {
 	assert.Contains(t, response, `"Configuration name contains invalid characters"`)
 }
 
+// TestCreateConfigNameTooLong tests that names over the length limit are rejected with the limit in the details
+func TestCreateConfigNameTooLong(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	reqBody := `{"name": "` + strings.Repeat("a", handlers.DefaultMaxNameLength+1) + `", "data": {"max_limit": 10, "enabled": true}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_CONFIG_NAME"`)
+	assert.Contains(t, rec.Body.String(), `"max_length":100`)
+}
+
+// TestCreateConfigNameShape tests that malformed names are rejected with the specific reason
+func TestCreateConfigNameShape(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	cases := map[string]string{
+		"---":      "no_alphanumeric",
+		"-app":     "leading_separator",
+		"app_":     "trailing_separator",
+		"app--cfg": "consecutive_separators",
+		"app_-cfg": "consecutive_separators",
+		"app cfg":  "invalid_character",
+		"appаpp":   "invalid_character",
+	}
+	for name, reason := range cases {
+		reqBody := fmt.Sprintf(`{"name": %q, "data": {"max_limit": 10, "enabled": true}}`, name)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, name)
+		assert.Contains(t, rec.Body.String(), `"reason":"`+reason+`"`, name)
+	}
+
+	// A position is given for invalid characters
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(`{"name": "app.cfg", "data": {"max_limit": 10, "enabled": true}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), `"position":3`)
+
+	// and for consecutive separators, the first of them
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(`{"name": "app-_cfg", "data": {"max_limit": 10, "enabled": true}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), `"position":3`)
+
+	for _, name := range []string{"a", "app-cfg_2", "A1-b2-c3"} {
+		reqBody := fmt.Sprintf(`{"name": %q, "data": {"max_limit": 10, "enabled": true}}`, name)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code, name)
+	}
+}
+
 // TestUpdateConfigEndpoint tests PUT /api/v1/configs/{name}
 func TestUpdateConfigEndpoint(t *testing.T) {
 	e, cleanup := setupTestServer(t)
@@ -228,6 +321,87 @@ func TestUpdateConfigEndpoint(t *testing.T) {
 	assert.Contains(t, response, `"name":"app-settings"`)
 }
 
+// TestPatchConfigEndpoint tests PATCH /api/v1/configs/{name} with JSON Patch and merge patch documents
+func TestPatchConfigEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "patched", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	patch := func(name, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/configs/"+name, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	latest := func() string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/patched", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	// add and replace
+	rec := patch("patched", "application/json-patch+json",
+		`[{"op": "add", "path": "/rollout_percent", "value": 25}, {"op": "replace", "path": "/max_limit", "value": 500}]`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"message":"Configuration patched successfully"`)
+	assert.Contains(t, rec.Body.String(), `"version":2`)
+	body := latest()
+	assert.Contains(t, body, `"max_limit":500`)
+	assert.Contains(t, body, `"rollout_percent":25`)
+	assert.Contains(t, body, `"enabled":true`)
+
+	// remove
+	rec = patch("patched", "application/json-patch+json", `[{"op": "remove", "path": "/rollout_percent"}]`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":3`)
+	body = latest()
+	assert.NotContains(t, body, `"rollout_percent"`)
+	assert.Contains(t, body, `"max_limit":500`)
+
+	// merge patch; charset parameters are ignored
+	rec = patch("patched", "application/merge-patch+json; charset=utf-8", `{"enabled": false, "rollout_seed": "cohort-a"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":4`)
+	body = latest()
+	assert.Contains(t, body, `"enabled":false`)
+	assert.Contains(t, body, `"rollout_seed":"cohort-a"`)
+
+	// A result violating the schema is rejected and stores nothing
+	rec = patch("patched", "application/merge-patch+json", `{"max_limit": null}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"SCHEMA_VALIDATION_FAILED"`)
+
+	// Operations that do not apply to the current data conflict with it
+	rec = patch("patched", "application/json-patch+json", `[{"op": "test", "path": "/max_limit", "value": 1}]`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"PATCH_CONFLICT"`)
+	rec = patch("patched", "application/json-patch+json", `[{"op": "remove", "path": "/rollout_percent"}]`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = patch("patched", "application/json-patch+json", `[{"op": "rename", "path": "/max_limit"}]`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_PATCH"`)
+
+	rec = patch("patched", echo.MIMEApplicationJSON, `{"enabled": true}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"UNSUPPORTED_PATCH_TYPE"`)
+
+	rec = patch("missing", "application/merge-patch+json", `{"enabled": true}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_NOT_FOUND"`)
+
+	// Rejected patches leave the configuration at the last stored version
+	assert.Contains(t, latest(), `"version":4`)
+}
+
 // TestUpdateConfigNotFoundError tests PUT /api/v1/configs/{name} with non-existent config
 func TestUpdateConfigNotFoundError(t *testing.T) {
 	e, cleanup := setupTestServer(t)
@@ -491,6 +665,31 @@ func TestListVersionsEndpoint(t *testing.T) {
 	assert.Contains(t, response, `"version":1`)
 	assert.Contains(t, response, `"version":2`)
 	assert.Contains(t, response, `"created_at"`)
+	assert.NotContains(t, response, `"config_data"`)
+
+	// include_data adds each version's data
+	listReq = httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions?include_data=true", nil)
+	listRec = httptest.NewRecorder()
+	e.ServeHTTP(listRec, listReq)
+
+	assert.Equal(t, http.StatusOK, listRec.Code)
+	response = listRec.Body.String()
+	assert.Contains(t, response, `"config_data":{"max_limit":1000,"enabled":true}`)
+	assert.Contains(t, response, `"config_data":{"max_limit":2000,"enabled":false}`)
+
+	// missing_ok turns the 404 for an unknown configuration into an empty list
+	listReq = httptest.NewRequest(http.MethodGet, "/api/v1/configs/missing/versions", nil)
+	listRec = httptest.NewRecorder()
+	e.ServeHTTP(listRec, listReq)
+	assert.Equal(t, http.StatusNotFound, listRec.Code)
+
+	listReq = httptest.NewRequest(http.MethodGet, "/api/v1/configs/missing/versions?missing_ok=true", nil)
+	listRec = httptest.NewRecorder()
+	e.ServeHTTP(listRec, listReq)
+	assert.Equal(t, http.StatusOK, listRec.Code)
+	response = listRec.Body.String()
+	assert.Contains(t, response, `"versions":[]`)
+	assert.Contains(t, response, `"total":0`)
 }
 
 // TestConfigNotFoundError tests 404 error scenario
@@ -540,3 +739,3308 @@ func TestSchemaValidationError(t *testing.T) {
 	assert.Contains(t, response, `"success":false`)
 	assert.Contains(t, response, `"SCHEMA_VALIDATION_FAILED"`)
 }
+
+// TestBulkDeleteConfigsByPrefix tests DELETE /api/v1/configs?name_prefix=...
+func TestSchemaValidationErrorLocalized(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	reqBody := `{"name": "test-config", "data": {"max_limit": "invalid-type", "enabled": true}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Accept-Language", "es-ES,es;q=0.9,en;q=0.8")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	// Codes stay stable while messages are translated
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	response := rec.Body.String()
+	assert.Contains(t, response, `"SCHEMA_VALIDATION_FAILED"`)
+	assert.Contains(t, response, `"type":"invalid_type"`)
+	assert.Contains(t, response, "Tipo no válido")
+
+	// Unsupported languages fall back to English
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Accept-Language", "ja")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Contains(t, rec.Body.String(), "Invalid type")
+}
+
+func TestBulkDeleteConfigsByPrefix(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	for _, name := range []string{"oldsvc-limits", "oldsvc-toggle", "newsvc-limits"} {
+		createBody := `{"name": "` + name + `", "data": {"max_limit": 1000, "enabled": true}}`
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+		createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		createRec := httptest.NewRecorder()
+		e.ServeHTTP(createRec, createReq)
+		assert.Equal(t, http.StatusCreated, createRec.Code)
+	}
+
+	// Without confirmation nothing is deleted
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/configs?name_prefix=oldsvc-", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIRMATION_REQUIRED"`)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/configs?name_prefix=oldsvc-&confirm=true", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	// Should return 200 OK with the deleted names
+	assert.Equal(t, http.StatusOK, rec.Code)
+	response := rec.Body.String()
+	assert.Contains(t, response, `"deleted":2`)
+	assert.Contains(t, response, `"names":["oldsvc-limits","oldsvc-toggle"]`)
+
+	// Non-matching configuration survives
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/newsvc-limits", nil)
+	getRec := httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Equal(t, http.StatusOK, getRec.Code)
+
+	getReq = httptest.NewRequest(http.MethodGet, "/api/v1/configs/oldsvc-limits", nil)
+	getRec = httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Equal(t, http.StatusNotFound, getRec.Code)
+}
+
+// TestExportEnvironmentEndpoint tests GET /api/v1/export/env
+func TestExportEnvironmentEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	updateBody := `{"data": {"max_limit": 2000, "enabled": false}}`
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/app-settings", strings.NewReader(updateBody))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	updateRec := httptest.NewRecorder()
+	e.ServeHTTP(updateRec, updateReq)
+	assert.Equal(t, http.StatusOK, updateRec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export/env", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	// Should return the latest data keyed by configuration name
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"success":true,"data":{"app-settings":{"max_limit":2000,"enabled":false}}}`, rec.Body.String())
+}
+
+// TestExportConfigsEndpoint tests GET /api/v1/export
+func TestExportConfigsEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Less(t, rec.Code, 300, "%s %s: %s", method, path, rec.Body.String())
+	}
+
+	// An empty database exports an empty archive
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/export", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var archive models.ExportArchive
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &archive))
+	assert.Equal(t, models.ExportArchiveFormat, archive.FormatVersion)
+	assert.Empty(t, archive.Configurations)
+
+	send(http.MethodPost, "/api/v1/configs", `{"name": "rate-limits", "data": {"max_limit": 1000, "enabled": true}}`)
+	send(http.MethodPost, "/api/v1/configs", `{"name": "checkout", "data": {"max_limit": 1, "enabled": true}}`)
+	send(http.MethodPut, "/api/v1/configs/checkout", `{"data": {"max_limit": 2, "enabled": true}}`)
+	send(http.MethodPut, "/api/v1/configs/checkout", `{"data": {"max_limit": 3, "enabled": false}}`)
+	send(http.MethodPut, "/api/v1/configs/checkout?draft=true", `{"data": {"max_limit": 4, "enabled": false}}`)
+	send(http.MethodPost, "/api/v1/configs", `{"name": "retired", "data": {"max_limit": 5, "enabled": true}}`)
+	send(http.MethodDelete, "/api/v1/configs/retired", "")
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/export", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), `attachment; filename="config-export-`)
+
+	archive = models.ExportArchive{}
+	if !assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &archive)) {
+		return
+	}
+	assert.Equal(t, models.ExportArchiveFormat, archive.FormatVersion)
+	assert.False(t, archive.ExportedAt.IsZero())
+
+	// Live configurations only, ordered by name, with every version in order
+	if !assert.Len(t, archive.Configurations, 2) {
+		return
+	}
+	checkout := archive.Configurations[0]
+	assert.Equal(t, "checkout", checkout.Name)
+	assert.Equal(t, 3, checkout.CurrentVersion)
+	assert.False(t, checkout.CreatedAt.IsZero())
+	assert.False(t, checkout.UpdatedAt.IsZero())
+	if assert.Len(t, checkout.Versions, 4) {
+		for i, version := range checkout.Versions {
+			assert.Equal(t, i+1, version.Version)
+			assert.Equal(t, "json", version.Format)
+			assert.False(t, version.CreatedAt.IsZero())
+		}
+		assert.JSONEq(t, `{"max_limit": 1, "enabled": true}`, string(checkout.Versions[0].Data))
+		assert.JSONEq(t, `{"max_limit": 3, "enabled": false}`, string(checkout.Versions[2].Data))
+		assert.Equal(t, models.VersionStatusPublished, checkout.Versions[2].Status)
+		assert.Equal(t, models.VersionStatusDraft, checkout.Versions[3].Status)
+	}
+
+	rateLimits := archive.Configurations[1]
+	assert.Equal(t, "rate-limits", rateLimits.Name)
+	assert.Equal(t, 1, rateLimits.CurrentVersion)
+	if assert.Len(t, rateLimits.Versions, 1) {
+		assert.JSONEq(t, `{"max_limit": 1000, "enabled": true}`, string(rateLimits.Versions[0].Data))
+	}
+}
+
+// TestImportConfigsRoundTrip exports a database, wipes it, imports the archive and compares
+func TestImportConfigsRoundTrip(t *testing.T) {
+	send := func(e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	export := func(e *echo.Echo) (string, models.ExportArchive) {
+		rec := send(e, http.MethodGet, "/api/v1/export", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var archive models.ExportArchive
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &archive))
+		return rec.Body.String(), archive
+	}
+
+	e, cleanup := setupTestServer(t)
+	send(e, http.MethodPost, "/api/v1/configs", `{"name": "checkout", "data": {"max_limit": 1, "enabled": true}}`)
+	send(e, http.MethodPut, "/api/v1/configs/checkout", `{"data": {"max_limit": 2, "enabled": false}}`)
+	send(e, http.MethodPut, "/api/v1/configs/checkout?draft=true", `{"data": {"max_limit": 3, "enabled": true}}`)
+	send(e, http.MethodPost, "/api/v1/configs", `{"name": "rate-limits", "format": "yaml", "data": "max_limit: 10\nenabled: true\n"}`)
+	send(e, http.MethodPut, "/api/v1/configs/checkout/sensitive", `{"sensitive": true}`)
+	send(e, http.MethodPut, "/api/v1/configs/checkout/tags", `{"tags": {"team": "payments", "env": "prod"}}`)
+	body, original := export(e)
+	cleanup()
+	assert.Len(t, original.Configurations, 2)
+	assert.True(t, original.Configurations[0].Sensitive)
+	assert.Equal(t, map[string]string{"team": "payments", "env": "prod"}, original.Configurations[0].Tags)
+	assert.False(t, original.Configurations[1].Sensitive)
+	assert.Empty(t, original.Configurations[1].Tags)
+
+	// Import into a fresh database
+	e, cleanup = setupTestServer(t)
+	defer cleanup()
+	rec := send(e, http.MethodPost, "/api/v1/import", body)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var imported struct {
+		Data models.ImportResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &imported))
+	assert.Equal(t, "fail", imported.Data.Mode)
+	assert.Equal(t, []string{"checkout", "rate-limits"}, imported.Data.Created)
+	assert.Equal(t, 4, imported.Data.Versions)
+
+	_, roundTripped := export(e)
+	assert.Equal(t, original.Configurations, roundTripped.Configurations)
+
+	// The imported history is live: the draft publishes and reads serve the imported data
+	assert.Equal(t, http.StatusOK, send(e, http.MethodPost, "/api/v1/configs/checkout/versions/3/publish", "").Code)
+	rec = send(e, http.MethodGet, "/api/v1/configs/rate-limits", "")
+	assert.Contains(t, rec.Body.String(), `"max_limit":10`)
+
+	// Existing names: fail rejects the whole import, skip keeps them, overwrite replaces them
+	rec = send(e, http.MethodPost, "/api/v1/import?mode=fail", body)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_ALREADY_EXISTS"`)
+
+	rec = send(e, http.MethodPost, "/api/v1/import?mode=skip", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"skipped":["checkout","rate-limits"]`)
+	assert.Contains(t, send(e, http.MethodGet, "/api/v1/configs/checkout", "").Body.String(), `"version":3`)
+
+	// Overwriting also replaces the sensitive flag and tags changed since
+	send(e, http.MethodPut, "/api/v1/configs/checkout/sensitive", `{"sensitive": false}`)
+	send(e, http.MethodPut, "/api/v1/configs/checkout/tags", `{"tags": {"team": "checkout", "stale": "yes"}}`)
+	send(e, http.MethodPut, "/api/v1/configs/rate-limits/tags", `{"tags": {"stale": "yes"}}`)
+	rec = send(e, http.MethodPost, "/api/v1/import?mode=overwrite", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"overwritten":["checkout","rate-limits"]`)
+	_, overwritten := export(e)
+	assert.Equal(t, original.Configurations, overwritten.Configurations)
+
+	rec = send(e, http.MethodPost, "/api/v1/import?mode=merge", body)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_IMPORT_MODE"`)
+
+	// Names stored before the current shape rules, which updates still accept, round-trip too;
+	// only their character set is checked
+	legacy := strings.ReplaceAll(body, `"name":"checkout"`, `"name":"-checkout"`)
+	legacy = strings.ReplaceAll(legacy, `"name":"rate-limits"`, `"name":"rate--limits"`)
+	rec = send(e, http.MethodPost, "/api/v1/import", legacy)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"created":["-checkout","rate--limits"]`)
+	legacyBody, _ := export(e)
+	cleanup()
+	e, cleanup = setupTestServer(t)
+	defer cleanup()
+	rec = send(e, http.MethodPost, "/api/v1/import", legacyBody)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	_, legacyRoundTripped := export(e)
+	assert.Len(t, legacyRoundTripped.Configurations, 4)
+
+	rec = send(e, http.MethodPost, "/api/v1/import", strings.ReplaceAll(body, `"name":"checkout"`, `"name":"check out"`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_CONFIG_NAME"`)
+	assert.Contains(t, rec.Body.String(), `"position":5`)
+}
+
+// TestImportConfigsDryRun tests that a dry-run import writes nothing and reports every conflict
+func TestImportConfigsDryRun(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	send(http.MethodPost, "/api/v1/configs", `{"name": "dry-a", "data": {"max_limit": 1, "enabled": true}}`)
+	send(http.MethodPost, "/api/v1/configs", `{"name": "dry-b", "data": {"max_limit": 2, "enabled": true}}`)
+	send(http.MethodPost, "/api/v1/configs/dry-b/versions/1/protect", "")
+	archive := `{"format_version": 1, "configurations": [
+		{"name": "dry-a", "current_version": 1, "created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-07T12:00:00Z",
+		 "versions": [{"version": 1, "data": {"max_limit": 10, "enabled": true}, "created_at": "2025-09-07T12:00:00Z"}]},
+		{"name": "dry-b", "current_version": 1, "created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-07T12:00:00Z",
+		 "versions": [{"version": 1, "data": {"max_limit": 20, "enabled": true}, "created_at": "2025-09-07T12:00:00Z"}]},
+		{"name": "dry-c", "current_version": 1, "created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-07T12:00:00Z",
+		 "versions": [{"version": 1, "data": {"max_limit": 30, "enabled": true}, "created_at": "2025-09-07T12:00:00Z"}]}
+	]}`
+
+	// Every existing name is reported, not just the first
+	rec := send(http.MethodPost, "/api/v1/import?dry_run=true", archive)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var failed struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Conflicts []models.ErrorDetail `json:"conflicts"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &failed))
+	assert.Equal(t, "IMPORT_CONFLICTS", failed.Error.Code)
+	if assert.Len(t, failed.Error.Details.Conflicts, 2) {
+		assert.Equal(t, "CONFIG_ALREADY_EXISTS", failed.Error.Details.Conflicts[0].Code)
+		assert.Contains(t, failed.Error.Details.Conflicts[1].Message, "dry-b")
+	}
+
+	// Overwriting would be refused only for the protected configuration
+	rec = send(http.MethodPost, "/api/v1/import?dry_run=true&mode=overwrite", archive)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &failed))
+	if assert.Len(t, failed.Error.Details.Conflicts, 1) {
+		assert.Equal(t, "VERSION_PROTECTED", failed.Error.Details.Conflicts[0].Code)
+	}
+
+	rec = send(http.MethodPost, "/api/v1/import?dry_run=true&mode=skip", archive)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var imported struct {
+		Data models.ImportResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &imported))
+	assert.True(t, imported.Data.DryRun)
+	assert.Equal(t, []string{"dry-c"}, imported.Data.Created)
+	assert.Equal(t, []string{"dry-a", "dry-b"}, imported.Data.Skipped)
+	assert.Equal(t, 1, imported.Data.Versions)
+
+	// Nothing was written
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/dry-c", "").Code)
+	assert.Contains(t, send(http.MethodGet, "/api/v1/configs/dry-a", "").Body.String(), `"max_limit":1`)
+}
+
+// TestImportConfigsValidation tests that invalid archives and version data import nothing
+func TestImportConfigsValidation(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	importArchive := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/import", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := importArchive(`{
+		"format_version": 1,
+		"configurations": [
+			{"name": "good", "current_version": 1, "created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-07T12:00:00Z",
+			 "versions": [{"version": 1, "data": {"max_limit": 1, "enabled": true}, "created_at": "2025-09-07T12:00:00Z"}]},
+			{"name": "bad", "current_version": 2, "created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-07T12:05:00Z",
+			 "versions": [
+				{"version": 1, "data": {"max_limit": "many", "enabled": true}, "created_at": "2025-09-07T12:00:00Z"},
+				{"version": 2, "data": {"max_limit": 1, "enabled": true, "colour": "red"}, "created_at": "2025-09-07T12:05:00Z"}
+			 ]}
+		]
+	}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var response struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				InvalidVersions []models.ImportVersionFailure `json:"invalid_versions"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "IMPORT_VALIDATION_FAILED", response.Error.Code)
+	if assert.Len(t, response.Error.Details.InvalidVersions, 2) {
+		assert.Equal(t, "bad", response.Error.Details.InvalidVersions[0].Name)
+		assert.Equal(t, 1, response.Error.Details.InvalidVersions[0].Version)
+		assert.Equal(t, 2, response.Error.Details.InvalidVersions[1].Version)
+		assert.NotEmpty(t, response.Error.Details.InvalidVersions[1].Errors)
+	}
+
+	// The valid configuration was not imported either
+	getRec := httptest.NewRecorder()
+	e.ServeHTTP(getRec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/good", nil))
+	assert.Equal(t, http.StatusNotFound, getRec.Code)
+
+	for name, body := range map[string]string{
+		"unknown format": `{"format_version": 9, "configurations": []}`,
+		"current is a draft": `{"format_version": 1, "configurations": [{"name": "c", "current_version": 1,
+			"created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-07T12:00:00Z",
+			"versions": [{"version": 1, "status": "draft", "data": {"max_limit": 1, "enabled": true}, "created_at": "2025-09-07T12:00:00Z"}]}]}`,
+		"versions out of order": `{"format_version": 1, "configurations": [{"name": "c", "current_version": 1,
+			"created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-07T12:00:00Z",
+			"versions": [{"version": 2, "data": {"max_limit": 1, "enabled": true}, "created_at": "2025-09-07T12:00:00Z"},
+			             {"version": 1, "data": {"max_limit": 1, "enabled": true}, "created_at": "2025-09-07T12:00:00Z"}]}]}`,
+	} {
+		rec := importArchive(body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, name)
+		assert.Contains(t, rec.Body.String(), `"INVALID_ARCHIVE"`, name)
+	}
+}
+
+// TestImportConfigsVersionNumbers tests that repeated version numbers, and gaps when contiguous
+// histories are required, are rejected with the offending numbers before anything is imported
+func TestImportConfigsVersionNumbers(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	importArchive := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	version := func(n int) string {
+		return fmt.Sprintf(`{"version": %d, "data": {"max_limit": %d, "enabled": true}, "created_at": "2025-09-07T12:00:00Z"}`, n, n)
+	}
+	config := func(name string, current int, versions ...int) string {
+		parts := make([]string, 0, len(versions))
+		for _, n := range versions {
+			parts = append(parts, version(n))
+		}
+		return fmt.Sprintf(`{"name": %q, "current_version": %d, "created_at": "2025-09-07T12:00:00Z", "updated_at": "2025-09-07T12:00:00Z", "versions": [%s]}`,
+			name, current, strings.Join(parts, ", "))
+	}
+
+	rec := importArchive("/api/v1/import", `{"format_version": 1, "configurations": [`+
+		config("dup-a", 3, 1, 2, 2, 3, 3)+", "+config("dup-b", 1, 1)+", "+config("dup-c", 1, 1, 1)+`]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var duplicated struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Duplicates []models.ImportDuplicateVersions `json:"duplicates"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &duplicated))
+	assert.Equal(t, "DUPLICATE_VERSION_IN_IMPORT", duplicated.Error.Code)
+	assert.Equal(t, []models.ImportDuplicateVersions{
+		{Name: "dup-a", Versions: []int{2, 3}},
+		{Name: "dup-c", Versions: []int{1}},
+	}, duplicated.Error.Details.Duplicates)
+
+	// Gaps are allowed unless contiguous histories are required
+	gapped := `{"format_version": 1, "configurations": [` +
+		config("gap-a", 6, 2, 3, 6) + ", " + config("gap-b", 2, 1, 2) + `]}`
+	rec = importArchive("/api/v1/import?require_contiguous=true", gapped)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var gaps struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Gaps []models.ImportVersionGap `json:"gaps"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &gaps))
+	assert.Equal(t, "VERSION_GAP_IN_IMPORT", gaps.Error.Code)
+	assert.Equal(t, []models.ImportVersionGap{
+		{Name: "gap-a", From: 1, To: 1},
+		{Name: "gap-a", From: 4, To: 5},
+	}, gaps.Error.Details.Gaps)
+
+	getRec := httptest.NewRecorder()
+	e.ServeHTTP(getRec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/gap-b", nil))
+	assert.Equal(t, http.StatusNotFound, getRec.Code)
+
+	rec = importArchive("/api/v1/import", gapped)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+// TestRPCBatchEndpoint tests POST /rpc with a JSON-RPC 2.0 batch
+func TestRPCBatchEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	reqBody := `[
+		{"jsonrpc": "2.0", "method": "createConfig", "params": {"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}, "id": 1},
+		{"jsonrpc": "2.0", "method": "getLatestConfig", "params": {"name": "app-settings"}, "id": 2},
+		{"jsonrpc": "2.0", "method": "getLatestConfig", "params": {"name": "non-existent"}, "id": 3},
+		{"jsonrpc": "2.0", "method": "unknownMethod", "id": 4},
+		{"jsonrpc": "2.0", "method": "getLatestConfig", "params": {"name": "app-settings"}}
+	]`
+
+	req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	// Should return 200 OK with one response per non-notification request
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var responses []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responses))
+	assert.Len(t, responses, 4)
+
+	response := rec.Body.String()
+	assert.Contains(t, response, `"name":"app-settings"`)
+	assert.Contains(t, response, `"max_limit":1000`)
+	assert.Contains(t, response, `"code":"CONFIG_NOT_FOUND"`)
+	assert.Contains(t, response, `"code":-32601`)
+}
+
+// TestUndoConfigEndpoint tests POST /api/v1/configs/{name}/undo
+func TestUndoConfigEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	// Nothing to undo at version 1
+	undoReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs/app-settings/undo", nil)
+	undoRec := httptest.NewRecorder()
+	e.ServeHTTP(undoRec, undoReq)
+	assert.Equal(t, http.StatusConflict, undoRec.Code)
+	assert.Contains(t, undoRec.Body.String(), `"NOTHING_TO_UNDO"`)
+
+	updateBody := `{"data": {"max_limit": 2000, "enabled": false}}`
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/app-settings", strings.NewReader(updateBody))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	updateRec := httptest.NewRecorder()
+	e.ServeHTTP(updateRec, updateReq)
+	assert.Equal(t, http.StatusOK, updateRec.Code)
+
+	undoReq = httptest.NewRequest(http.MethodPost, "/api/v1/configs/app-settings/undo", nil)
+	undoRec = httptest.NewRecorder()
+	e.ServeHTTP(undoRec, undoReq)
+
+	// Should create version 3 with version 1 data
+	assert.Equal(t, http.StatusOK, undoRec.Code)
+	response := undoRec.Body.String()
+	assert.Contains(t, response, `"new_version":3`)
+	assert.Contains(t, response, `"target_version":1`)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings", nil)
+	getRec := httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Contains(t, getRec.Body.String(), `"max_limit":1000`)
+}
+
+// TestRollbackConfigStrictBody tests POST /api/v1/configs/{name}/rollback body validation
+func TestRollbackConfigStrictBody(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	testCases := []struct {
+		body         string
+		expectedCode string
+	}{
+		{`{}`, `"MISSING_REQUIRED_FIELD"`},
+		{`{"target_version": null}`, `"MISSING_REQUIRED_FIELD"`},
+		{`{"target_version": "1"}`, `"INVALID_VERSION_NUMBER"`},
+		{`{"target_version": 1.5}`, `"INVALID_VERSION_NUMBER"`},
+		{`{"target_version": 1, "force": true}`, `"INVALID_REQUEST_FORMAT"`},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs/app-settings/rollback", strings.NewReader(tc.body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		// Should return 400 Bad Request with a precise error code
+		assert.Equal(t, http.StatusBadRequest, rec.Code, tc.body)
+		assert.Contains(t, rec.Body.String(), tc.expectedCode, tc.body)
+	}
+}
+
+func TestListDuplicateVersionsEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	// Version 3 repeats version 1 with a different key order
+	for _, updateBody := range []string{
+		`{"data": {"max_limit": 2000, "enabled": false}}`,
+		`{"data": {"enabled": true, "max_limit": 1000}}`,
+	} {
+		updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/app-settings", strings.NewReader(updateBody))
+		updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		updateRec := httptest.NewRecorder()
+		e.ServeHTTP(updateRec, updateReq)
+		assert.Equal(t, http.StatusOK, updateRec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/duplicates", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data models.DuplicateVersions `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	if assert.Len(t, response.Data.Clusters, 1) {
+		assert.Equal(t, []int{1, 3}, response.Data.Clusters[0].Versions)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/configs/non-existent/duplicates", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestConfigStatusDerivesEnabled(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "status": "on"}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings", nil)
+	getRec := httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Contains(t, getRec.Body.String(), `"config_data":{"max_limit":1000,"enabled":true,"status":"on"}`)
+
+	// Scheduled configurations are not enabled yet
+	updateBody := `{"data": {"max_limit": 1000, "status": "scheduled"}}`
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/app-settings", strings.NewReader(updateBody))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	updateRec := httptest.NewRecorder()
+	e.ServeHTTP(updateRec, updateReq)
+	assert.Equal(t, http.StatusOK, updateRec.Code)
+
+	getReq = httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings", nil)
+	getRec = httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Contains(t, getRec.Body.String(), `"enabled":false,"status":"scheduled"`)
+
+	for _, invalidBody := range []string{
+		`{"data": {"max_limit": 1000, "status": "paused"}}`,
+		`{"data": {"max_limit": 1000, "status": "off", "enabled": true}}`,
+		`{"data": {"max_limit": 1000}}`,
+	} {
+		updateReq = httptest.NewRequest(http.MethodPut, "/api/v1/configs/app-settings", strings.NewReader(invalidBody))
+		updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		updateRec = httptest.NewRecorder()
+		e.ServeHTTP(updateRec, updateReq)
+		assert.Equal(t, http.StatusUnprocessableEntity, updateRec.Code, invalidBody)
+	}
+}
+
+func TestGetVersionSchemaEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/1/schema", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data models.VersionSchema `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.Data.SchemaHash)
+	assert.JSONEq(t, services.ConfigDataSchema, string(response.Data.Schema))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/2/schema", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"VERSION_NOT_FOUND"`)
+}
+
+func TestListConfigsSorting(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	for _, name := range []string{"charlie", "alpha", "bravo"} {
+		createBody := `{"name": "` + name + `", "data": {"max_limit": 1000, "enabled": true}}`
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+		createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		createRec := httptest.NewRecorder()
+		e.ServeHTTP(createRec, createReq)
+		assert.Equal(t, http.StatusCreated, createRec.Code)
+	}
+
+	updateBody := `{"data": {"max_limit": 2000, "enabled": false}}`
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/charlie", strings.NewReader(updateBody))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	updateRec := httptest.NewRecorder()
+	e.ServeHTTP(updateRec, updateReq)
+	assert.Equal(t, http.StatusOK, updateRec.Code)
+
+	listNames := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data models.ConfigurationList `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		names := []string{}
+		for _, config := range response.Data.Configurations {
+			names = append(names, config.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"alpha", "bravo", "charlie"}, listNames(""))
+	assert.Equal(t, []string{"charlie", "bravo", "alpha"}, listNames("?order=desc"))
+	assert.Equal(t, []string{"charlie", "alpha", "bravo"}, listNames("?sort=version&order=desc"))
+	assert.Equal(t, []string{"charlie", "alpha", "bravo"}, listNames("?sort=created_at"))
+	assert.Equal(t, "charlie", listNames("?sort=updated_at&order=desc")[0])
+
+	for _, query := range []string{"?sort=json_data", "?sort=name%3BDROP%20TABLE%20configurations", "?order=sideways"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestListConfigsPagination(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	for _, name := range []string{"alpha", "bravo", "charlie", "delta", "echo"} {
+		createBody := `{"name": "` + name + `", "data": {"max_limit": 1000, "enabled": true}}`
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+		createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		createRec := httptest.NewRecorder()
+		e.ServeHTTP(createRec, createReq)
+		assert.Equal(t, http.StatusCreated, createRec.Code)
+	}
+
+	listPage := func(target string) models.ConfigurationList {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data models.ConfigurationList `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	// Follow the next links through every page
+	names := []string{}
+	target := "/api/v1/configs?limit=2&order=desc"
+	for pages := 0; pages < 3; pages++ {
+		page := listPage(target)
+		assert.Equal(t, 5, page.Pagination.Total)
+		for _, config := range page.Configurations {
+			names = append(names, config.Name)
+		}
+		if page.Pagination.Next == nil {
+			assert.Equal(t, 4, page.Pagination.Offset)
+			assert.Equal(t, "/api/v1/configs?limit=2&offset=2&order=desc", *page.Pagination.Prev)
+			break
+		}
+		target = *page.Pagination.Next
+	}
+	assert.Equal(t, []string{"echo", "delta", "charlie", "bravo", "alpha"}, names)
+
+	first := listPage("/api/v1/configs?limit=2")
+	assert.Nil(t, first.Pagination.Prev)
+
+	all := listPage("/api/v1/configs")
+	assert.Len(t, all.Configurations, 5)
+	assert.Equal(t, 50, all.Pagination.Limit)
+	assert.Nil(t, all.Pagination.Next)
+
+	past := listPage("/api/v1/configs?offset=10")
+	assert.Empty(t, past.Configurations)
+	assert.Equal(t, 5, past.Pagination.Total)
+
+	for _, query := range []string{"?limit=0", "?limit=-1", "?offset=-1", "?limit=ten", "?limit=201"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		assert.Contains(t, rec.Body.String(), `"INVALID_PAGINATION"`, query)
+	}
+}
+
+func TestConfigsExistEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	reqBody := `{"names": ["app-settings", "missing-config"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs:exists", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data map[string]bool `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, map[string]bool{"app-settings": true, "missing-config": false}, response.Data)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/configs:exists", strings.NewReader(`{"names": []}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetConfigVersionETag(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/1", nil)
+	getRec := httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Equal(t, http.StatusOK, getRec.Code)
+	assert.Equal(t, "no-cache", getRec.Header().Get("Cache-Control"))
+	etag := getRec.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// A matching If-None-Match revalidates without a body
+	getReq = httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/1", nil)
+	getReq.Header.Set("If-None-Match", etag)
+	getRec = httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Equal(t, http.StatusNotModified, getRec.Code)
+	assert.Empty(t, getRec.Body.String())
+
+	getReq = httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/1", nil)
+	getReq.Header.Set("If-None-Match", `"stale"`)
+	getRec = httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Equal(t, http.StatusOK, getRec.Code)
+}
+
+// TestGetLatestConfigETag tests conditional GET /api/v1/configs/{name} across updates and rollbacks
+func TestGetLatestConfigETag(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	getLatest := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	createRec := send(http.MethodPost, "/api/v1/configs", `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	rec := getLatest("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, etag, getLatest("").Header().Get("ETag"), "ETag must be stable for the same version")
+
+	// A matching If-None-Match revalidates without a body
+	rec = getLatest(etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+
+	// An update changes the ETag, so the old one no longer matches
+	updateRec := send(http.MethodPut, "/api/v1/configs/app-settings", `{"data": {"max_limit": 2000, "enabled": true}}`)
+	assert.Equal(t, http.StatusOK, updateRec.Code)
+	rec = getLatest(etag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	updatedETag := rec.Header().Get("ETag")
+	assert.NotEqual(t, etag, updatedETag)
+
+	// So does a rollback, even to data that was served before
+	rollbackRec := send(http.MethodPost, "/api/v1/configs/app-settings/rollback", `{"target_version": 1}`)
+	assert.Equal(t, http.StatusOK, rollbackRec.Code)
+	rec = getLatest(updatedETag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	assert.NotEqual(t, updatedETag, rec.Header().Get("ETag"))
+}
+
+func TestConfigChecksumHeader(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	latestReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings", nil)
+	latestRec := httptest.NewRecorder()
+	e.ServeHTTP(latestRec, latestReq)
+	assert.Equal(t, http.StatusOK, latestRec.Code)
+	checksum := latestRec.Header().Get("X-Config-Checksum")
+	assert.Regexp(t, `^[0-9a-f]{64}$`, checksum)
+
+	// The version read carries the same checksum, embedded in its ETag
+	versionReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/1", nil)
+	versionRec := httptest.NewRecorder()
+	e.ServeHTTP(versionRec, versionReq)
+	assert.Equal(t, http.StatusOK, versionRec.Code)
+	assert.Equal(t, checksum, versionRec.Header().Get("X-Config-Checksum"))
+	assert.Contains(t, versionRec.Header().Get("ETag"), checksum)
+
+	// Key order and whitespace do not change the checksum
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/app-settings",
+		strings.NewReader(`{"data": {"enabled": true,   "max_limit": 1000}}`))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	updateRec := httptest.NewRecorder()
+	e.ServeHTTP(updateRec, updateReq)
+	assert.Equal(t, http.StatusOK, updateRec.Code)
+
+	latestRec = httptest.NewRecorder()
+	e.ServeHTTP(latestRec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings", nil))
+	assert.Equal(t, checksum, latestRec.Header().Get("X-Config-Checksum"))
+
+	missingRec := httptest.NewRecorder()
+	e.ServeHTTP(missingRec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/missing", nil))
+	assert.Equal(t, http.StatusNotFound, missingRec.Code)
+	assert.Empty(t, missingRec.Header().Get("X-Config-Checksum"))
+}
+
+func TestRedoConfigEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	put := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, post("/api/v1/configs", `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusOK, put("/api/v1/configs/app-settings", `{"data": {"max_limit": 2000, "enabled": false}}`).Code)
+
+	// Nothing has been rolled back yet
+	rec := post("/api/v1/configs/app-settings/redo", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CANNOT_REDO"`)
+
+	// Undo to version 1 (creating version 3), then redo back to version 2 (creating version 4)
+	assert.Equal(t, http.StatusOK, post("/api/v1/configs/app-settings/undo", "").Code)
+	rec = post("/api/v1/configs/app-settings/redo", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"new_version":4`)
+	assert.Contains(t, rec.Body.String(), `"target_version":2`)
+
+	// A redo cannot be repeated
+	assert.Equal(t, http.StatusConflict, post("/api/v1/configs/app-settings/redo", "").Code)
+
+	// An edit after a rollback discards the redo target
+	assert.Equal(t, http.StatusOK, post("/api/v1/configs/app-settings/rollback", `{"target_version": 1}`).Code)
+	assert.Equal(t, http.StatusOK, put("/api/v1/configs/app-settings", `{"data": {"max_limit": 3000, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusConflict, post("/api/v1/configs/app-settings/redo", "").Code)
+}
+
+// TestConfigTextFormats tests creating and updating from YAML/TOML text and reading it back as authored
+func TestConfigTextFormats(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	yamlText := "# launch limits\nmax_limit: 500\nenabled: true\n"
+	createBody, _ := json.Marshal(map[string]string{"name": "yaml-config", "format": "yaml", "data": yamlText})
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", bytes.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code, createRec.Body.String())
+
+	// The JSON view is converted from the text
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/yaml-config", nil)
+	getRec := httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Equal(t, http.StatusOK, getRec.Code)
+	assert.Contains(t, getRec.Body.String(), `"max_limit":500`)
+	assert.Contains(t, getRec.Body.String(), `"format":"yaml"`)
+
+	// The original view returns the text with its comments
+	originalReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/yaml-config?format=original", nil)
+	originalRec := httptest.NewRecorder()
+	e.ServeHTTP(originalRec, originalReq)
+	assert.Equal(t, http.StatusOK, originalRec.Code)
+	assert.Equal(t, "application/yaml", originalRec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, yamlText, originalRec.Body.String())
+
+	// TOML is detected when no format is given
+	tomlText := "max_limit = 700 # raised\nenabled = false\n"
+	updateBody, _ := json.Marshal(map[string]string{"data": tomlText})
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/yaml-config", bytes.NewReader(updateBody))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	updateRec := httptest.NewRecorder()
+	e.ServeHTTP(updateRec, updateReq)
+	assert.Equal(t, http.StatusOK, updateRec.Code, updateRec.Body.String())
+
+	versionReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/yaml-config/versions/2?format=original", nil)
+	versionRec := httptest.NewRecorder()
+	e.ServeHTTP(versionRec, versionReq)
+	assert.Equal(t, http.StatusOK, versionRec.Code)
+	assert.Equal(t, "application/toml", versionRec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, tomlText, versionRec.Body.String())
+
+	// Full TOML is parsed: multi-line and literal strings, and TOML's own escapes
+	richTOML := "max_limit = 8_00\nenabled = true\nstatus = 'on'\nrollout_seed = \"\"\"\nseed-\\u00e9\\\n  2024\"\"\"\n"
+	richBody, _ := json.Marshal(map[string]string{"name": "toml-config", "format": "toml", "data": richTOML})
+	richReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", bytes.NewReader(richBody))
+	richReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	richRec := httptest.NewRecorder()
+	e.ServeHTTP(richRec, richReq)
+	assert.Equal(t, http.StatusCreated, richRec.Code, richRec.Body.String())
+
+	richGetRec := httptest.NewRecorder()
+	e.ServeHTTP(richGetRec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/toml-config", nil))
+	assert.Equal(t, http.StatusOK, richGetRec.Code)
+	assert.Contains(t, richGetRec.Body.String(), `"max_limit":800`)
+	assert.Contains(t, richGetRec.Body.String(), `"status":"on"`)
+	assert.Contains(t, richGetRec.Body.String(), `"rollout_seed":"seed-é2024"`)
+
+	// Tables, dotted keys, arrays and dates parse, reaching schema validation as nested fields
+	nestedTOML := "max_limit = 1\nenabled = true\nowner.team = \"payments\"\nreleased = 2024-05-27\n\n[limits]\nregions = [\"eu\", \"us\"]\n"
+	nestedBody, _ := json.Marshal(map[string]string{"data": nestedTOML})
+	nestedReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/toml-config", bytes.NewReader(nestedBody))
+	nestedReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	nestedRec := httptest.NewRecorder()
+	e.ServeHTTP(nestedRec, nestedReq)
+	assert.Equal(t, http.StatusUnprocessableEntity, nestedRec.Code)
+	assert.Contains(t, nestedRec.Body.String(), `"unknown_fields":["limits","owner","released"]`)
+
+	// Unparseable text and object data with a text format are rejected
+	for _, body := range []string{
+		`{"data": "max_limit = [1, 2", "format": "toml"}`,
+		`{"data": "max_limit = 1\nmax_limit = 2", "format": "toml"}`,
+		`{"data": {"max_limit": 1, "enabled": true}, "format": "yaml"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/configs/yaml-config", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		assert.Contains(t, rec.Body.String(), `"INVALID_CONFIG_FORMAT"`, body)
+	}
+}
+
+// TestSyncConfigsEndpoint tests POST /api/v1/configs/sync
+func TestSyncConfigsEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	for _, name := range []string{"unchanged", "changed", "unknown"} {
+		createBody := `{"name": "` + name + `", "data": {"max_limit": 1000, "enabled": true}}`
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+		createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		createRec := httptest.NewRecorder()
+		e.ServeHTTP(createRec, createReq)
+		assert.Equal(t, http.StatusCreated, createRec.Code)
+	}
+
+	updateBody := `{"data": {"max_limit": 2000, "enabled": false}}`
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/changed", strings.NewReader(updateBody))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	updateRec := httptest.NewRecorder()
+	e.ServeHTTP(updateRec, updateReq)
+	assert.Equal(t, http.StatusOK, updateRec.Code)
+
+	syncBody := `{"known": {"unchanged": 1, "changed": 1, "gone": 4}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs/sync", strings.NewReader(syncBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data models.SyncResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	if assert.Len(t, response.Data.Updated, 1) {
+		assert.Equal(t, "changed", response.Data.Updated[0].Name)
+		assert.Equal(t, 2, response.Data.Updated[0].Version)
+		assert.Equal(t, 2000, response.Data.Updated[0].ConfigData.MaxLimit)
+	}
+	if assert.Len(t, response.Data.New, 1) {
+		assert.Equal(t, "unknown", response.Data.New[0].Name)
+	}
+	assert.Equal(t, []string{"gone"}, response.Data.Removed)
+}
+
+// TestCreateConfigsBatch tests POST /api/v1/configs/batch in best-effort and atomic mode
+func TestCreateConfigsBatch(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	batch := func(target, body string) (int, models.BatchCreateResult) {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var response struct {
+			Data models.BatchCreateResult `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return rec.Code, response.Data
+	}
+	exists := func(name string) bool {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/"+name, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code == http.StatusOK
+	}
+
+	t.Run("every item valid", func(t *testing.T) {
+		code, result := batch("/api/v1/configs/batch", `[
+			{"name": "batch-a", "data": {"max_limit": 1, "enabled": true}},
+			{"name": "batch-b", "data": {"max_limit": 2, "enabled": false}}
+		]`)
+		assert.Equal(t, http.StatusCreated, code)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 0, result.Failed)
+		if assert.Len(t, result.Results, 2) {
+			assert.True(t, result.Results[1].Created)
+			assert.Equal(t, "batch-b", result.Results[1].Name)
+			assert.Equal(t, 1, result.Results[1].Version)
+		}
+		assert.True(t, exists("batch-a"))
+		assert.True(t, exists("batch-b"))
+	})
+
+	t.Run("best effort creates the valid items", func(t *testing.T) {
+		code, result := batch("/api/v1/configs/batch", `[
+			{"name": "batch-c", "data": {"max_limit": 3, "enabled": true}},
+			{"name": "bad name", "data": {"max_limit": 4, "enabled": true}},
+			{"name": "batch-a", "data": {"max_limit": 5, "enabled": true}},
+			{"name": "batch-d", "data": {"max_limit": -1, "enabled": true}}
+		]`)
+		assert.Equal(t, http.StatusMultiStatus, code)
+		assert.False(t, result.Atomic)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 3, result.Failed)
+		if assert.Len(t, result.Results, 4) {
+			assert.True(t, result.Results[0].Created)
+			assert.Equal(t, "INVALID_CONFIG_NAME", result.Results[1].Error.Code)
+			assert.Equal(t, "CONFIG_ALREADY_EXISTS", result.Results[2].Error.Code)
+			assert.Equal(t, "SCHEMA_VALIDATION_FAILED", result.Results[3].Error.Code)
+		}
+		assert.True(t, exists("batch-c"))
+		assert.False(t, exists("batch-d"))
+	})
+
+	t.Run("atomic creates nothing on a failure", func(t *testing.T) {
+		code, result := batch("/api/v1/configs/batch?atomic=true", `[
+			{"name": "batch-e", "data": {"max_limit": 6, "enabled": true}},
+			{"name": "bad name", "data": {"max_limit": 7, "enabled": true}}
+		]`)
+		assert.Equal(t, http.StatusMultiStatus, code)
+		assert.True(t, result.Atomic)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, 2, result.Failed)
+		if assert.Len(t, result.Results, 2) {
+			assert.Equal(t, "BATCH_ROLLED_BACK", result.Results[0].Error.Code)
+			assert.Equal(t, "INVALID_CONFIG_NAME", result.Results[1].Error.Code)
+		}
+		assert.False(t, exists("batch-e"))
+
+		// A conflict found while inserting rolls back the items inserted before it
+		code, result = batch("/api/v1/configs/batch?atomic=true", `[
+			{"name": "batch-f", "data": {"max_limit": 8, "enabled": true}},
+			{"name": "batch-b", "data": {"max_limit": 9, "enabled": true}}
+		]`)
+		assert.Equal(t, http.StatusMultiStatus, code)
+		if assert.Len(t, result.Results, 2) {
+			assert.Equal(t, "BATCH_ROLLED_BACK", result.Results[0].Error.Code)
+			assert.Equal(t, "CONFIG_ALREADY_EXISTS", result.Results[1].Error.Code)
+		}
+		assert.False(t, exists("batch-f"))
+
+		code, result = batch("/api/v1/configs/batch?atomic=true", `[
+			{"name": "batch-f", "data": {"max_limit": 8, "enabled": true}}
+		]`)
+		assert.Equal(t, http.StatusCreated, code)
+		assert.Equal(t, 1, result.Created)
+		assert.True(t, exists("batch-f"))
+	})
+
+	t.Run("dry run reports every item and creates nothing", func(t *testing.T) {
+		code, result := batch("/api/v1/configs/batch?dry_run=true", `[
+			{"name": "batch-h", "data": {"max_limit": 1, "enabled": true}},
+			{"name": "batch-i", "data": {"max_limit": 2, "enabled": true}}
+		]`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 2, result.Created)
+		assert.False(t, exists("batch-h"))
+		assert.False(t, exists("batch-i"))
+
+		// Every failure is reported, even atomically where the first one would stop the batch
+		code, result = batch("/api/v1/configs/batch?dry_run=true&atomic=true", `[
+			{"name": "batch-h", "data": {"max_limit": 1, "enabled": true}},
+			{"name": "batch-a", "data": {"max_limit": 2, "enabled": true}},
+			{"name": "batch-b", "data": {"max_limit": 3, "enabled": true}},
+			{"name": "batch-i", "data": {"max_limit": -1, "enabled": true}}
+		]`)
+		assert.Equal(t, http.StatusMultiStatus, code)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, 4, result.Failed)
+		if assert.Len(t, result.Results, 4) {
+			assert.Equal(t, "BATCH_ROLLED_BACK", result.Results[0].Error.Code)
+			assert.Equal(t, "CONFIG_ALREADY_EXISTS", result.Results[1].Error.Code)
+			assert.Equal(t, "CONFIG_ALREADY_EXISTS", result.Results[2].Error.Code)
+			assert.Equal(t, "SCHEMA_VALIDATION_FAILED", result.Results[3].Error.Code)
+		}
+		assert.False(t, exists("batch-h"))
+	})
+
+	t.Run("rejects a body that is not an array", func(t *testing.T) {
+		code, _ := batch("/api/v1/configs/batch", `{"name": "batch-g"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = batch("/api/v1/configs/batch", `[]`)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+// TestStrictQueryParams tests that strict mode rejects undeclared query parameters and lenient mode ignores them
+func TestStrictQueryParams(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	list := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/strict/configs", list, appmiddleware.AllowedQueryParams(true, "limit", "offset"))
+	e.GET("/lenient/configs", list, appmiddleware.AllowedQueryParams(false, "limit", "offset"))
+
+	for target, want := range map[string]int{
+		"/strict/configs?limit=10":  http.StatusOK,
+		"/strict/configs?limt=10":   http.StatusBadRequest,
+		"/lenient/configs?limt=10":  http.StatusOK,
+		"/strict/configs":           http.StatusOK,
+		"/strict/configs?offset=5&": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, want, rec.Code, target)
+		if want == http.StatusBadRequest {
+			assert.Contains(t, rec.Body.String(), `"UNKNOWN_QUERY_PARAM"`)
+			assert.Contains(t, rec.Body.String(), `"limt"`)
+		}
+	}
+}
+
+// TestStrictQueryParamsDeclared tests that the parameters handlers read are declared for their
+// routes, so strict mode does not reject them
+func TestStrictQueryParamsDeclared(t *testing.T) {
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e := echo.New()
+	api := e.Group("/api/v1")
+	api.POST("/configs/batch", ok, appmiddleware.AllowedQueryParams(true, handlers.QueryParams["POST /configs/batch"]...))
+	api.POST("/import", ok, appmiddleware.AllowedQueryParams(true, handlers.QueryParams["POST /import"]...))
+
+	for _, target := range []string{
+		"/api/v1/configs/batch?atomic=true&dry_run=true",
+		"/api/v1/import?mode=overwrite&dry_run=true&require_contiguous=true",
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, target)
+	}
+}
+
+// TestSquashConfigEndpoint tests POST /api/v1/configs/{name}/squash
+func TestSquashConfigEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "long-history", "data": {"max_limit": 1, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	for limit := 2; limit <= 6; limit++ {
+		updateBody := fmt.Sprintf(`{"data": {"max_limit": %d, "enabled": true}}`, limit)
+		updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/long-history", strings.NewReader(updateBody))
+		updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		updateRec := httptest.NewRecorder()
+		e.ServeHTTP(updateRec, updateReq)
+		assert.Equal(t, http.StatusOK, updateRec.Code)
+	}
+
+	// Guards: a valid cutoff and explicit confirmation are required
+	for _, query := range []string{"?keep_from=4", "?keep_from=1&confirm=true", "?keep_from=abc&confirm=true"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs/long-history/squash"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs/long-history/squash?keep_from=4&confirm=true", nil)
+	req.Header.Set("X-Actor", "ops@example.com")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data models.SquashResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Data.RemovedVersions)
+	assert.Equal(t, 6, response.Data.PreviousVersion)
+	assert.Equal(t, 3, response.Data.CurrentVersion)
+
+	// Old version 4 is the new baseline and the latest data is unchanged
+	versionReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/long-history/versions/1", nil)
+	versionRec := httptest.NewRecorder()
+	e.ServeHTTP(versionRec, versionReq)
+	assert.Equal(t, http.StatusOK, versionRec.Code)
+	assert.Contains(t, versionRec.Body.String(), `"max_limit":4`)
+
+	latestReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/long-history", nil)
+	latestRec := httptest.NewRecorder()
+	e.ServeHTTP(latestRec, latestReq)
+	assert.Contains(t, latestRec.Body.String(), `"version":3`)
+	assert.Contains(t, latestRec.Body.String(), `"max_limit":6`)
+
+	// Squashing past the current version is refused
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/configs/long-history/squash?keep_from=9&confirm=true", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestPruneVersionsEndpoint tests that pruning keeps the newest versions and the current one
+func TestPruneVersionsEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "retained", "data": {"max_limit": 1, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	for limit := 2; limit <= 5; limit++ {
+		updateBody := fmt.Sprintf(`{"data": {"max_limit": %d, "enabled": true}}`, limit)
+		updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/retained", strings.NewReader(updateBody))
+		updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		updateRec := httptest.NewRecorder()
+		e.ServeHTTP(updateRec, updateReq)
+		assert.Equal(t, http.StatusOK, updateRec.Code)
+	}
+
+	for _, query := range []string{"", "?keep=0", "?keep=abc"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs/retained/prune"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		assert.Contains(t, rec.Body.String(), "INVALID_KEEP", query)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs/retained/prune?keep=2", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data models.PruneResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []int{1, 2, 3}, response.Data.RemovedVersions)
+	assert.Equal(t, 5, response.Data.CurrentVersion)
+
+	// Pruned versions are gone, the kept ones and the current version are untouched
+	versionReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/retained/versions/2", nil)
+	versionRec := httptest.NewRecorder()
+	e.ServeHTTP(versionRec, versionReq)
+	assert.Equal(t, http.StatusNotFound, versionRec.Code)
+
+	versionReq = httptest.NewRequest(http.MethodGet, "/api/v1/configs/retained/versions/4", nil)
+	versionRec = httptest.NewRecorder()
+	e.ServeHTTP(versionRec, versionReq)
+	assert.Equal(t, http.StatusOK, versionRec.Code)
+	assert.Contains(t, versionRec.Body.String(), `"max_limit":4`)
+
+	latestReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/retained", nil)
+	latestRec := httptest.NewRecorder()
+	e.ServeHTTP(latestRec, latestReq)
+	assert.Contains(t, latestRec.Body.String(), `"version":5`)
+	assert.Contains(t, latestRec.Body.String(), `"max_limit":5`)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/configs/missing/prune?keep=2", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestVersionCreatedBy tests that the X-Actor of each write is returned with its version
+func TestVersionCreatedBy(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body, actor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if actor != "" {
+			req.Header.Set("X-Actor", actor)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/api/v1/configs", `{"name": "audited", "data": {"max_limit": 1, "enabled": true}}`, "alice@example.com")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = send(http.MethodPut, "/api/v1/configs/audited", `{"data": {"max_limit": 2, "enabled": true}}`, "bob@example.com")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = send(http.MethodPost, "/api/v1/configs/audited/rollback", `{"target_version": 1}`, "carol@example.com")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = send(http.MethodPut, "/api/v1/configs/audited", `{"data": {"max_limit": 4, "enabled": true}}`, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = send(http.MethodGet, "/api/v1/configs/audited/versions", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Data models.VersionList `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	createdBy := make(map[int]string)
+	for _, version := range list.Data.Versions {
+		createdBy[version.Version] = version.CreatedBy
+	}
+	assert.Equal(t, map[int]string{
+		1: "alice@example.com",
+		2: "bob@example.com",
+		3: "carol@example.com",
+		4: "anonymous",
+	}, createdBy)
+
+	rec = send(http.MethodGet, "/api/v1/configs/audited/versions/2", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"created_by":"bob@example.com"`)
+
+	rec = send(http.MethodGet, "/api/v1/configs/audited", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"created_by":"anonymous"`)
+}
+
+// TestReadinessGateDuringMigration tests that requests arriving before migrations finish are rejected with 503
+func TestReadinessGateDuringMigration(t *testing.T) {
+	testDB := "./test_readiness.db"
+	_ = os.Remove(testDB)
+	defer os.Remove(testDB)
+
+	// An unmigrated database stands in for a server that is still migrating
+	db, err := sql.Open("sqlite3", storage.SQLiteDSN(testDB))
+	if err != nil {
+		t.Fatal("Failed to open test database:", err)
+	}
+	defer db.Close()
+
+	validationService, err := services.NewValidationService()
+	if err != nil {
+		t.Fatal("Failed to create validation service:", err)
+	}
+	configHandler := handlers.NewConfigHandler(services.NewConfigService(storage.NewSQLiteStore(db), validationService))
+
+	readiness := appmiddleware.NewReadiness()
+	e := echo.New()
+	e.GET("/ready", readiness.Handler)
+	api := e.Group("/api/v1", readiness.Gate())
+	api.GET("/configs", configHandler.ListConfigs)
+
+	readyReq := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	readyRec := httptest.NewRecorder()
+	e.ServeHTTP(readyRec, readyReq)
+	assert.Equal(t, http.StatusServiceUnavailable, readyRec.Code)
+	assert.Contains(t, readyRec.Body.String(), `"migrating"`)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/configs", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), `"SERVICE_NOT_READY"`)
+
+	// Once migrations complete the gate opens
+	if err := storage.Migrate(db); err != nil {
+		t.Fatal("Failed to run migrations:", err)
+	}
+	readiness.MarkReady()
+
+	readyRec = httptest.NewRecorder()
+	e.ServeHTTP(readyRec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusOK, readyRec.Code)
+	assert.Contains(t, readyRec.Body.String(), `"ready"`)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/configs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// TestGetConfigMetaEndpoint tests GET /api/v1/configs/{name}/meta
+func TestGetConfigMetaEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "catalog-entry", "data": {"max_limit": 10, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	updateBody := `{"data": {"max_limit": 20, "enabled": true}}`
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/catalog-entry", strings.NewReader(updateBody))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	updateRec := httptest.NewRecorder()
+	e.ServeHTTP(updateRec, updateReq)
+	assert.Equal(t, http.StatusOK, updateRec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/catalog-entry/meta", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "max_limit")
+
+	var response struct {
+		Data models.ConfigurationMeta `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "catalog-entry", response.Data.Name)
+	assert.Equal(t, 2, response.Data.CurrentVersion)
+	assert.False(t, response.Data.CreatedAt.IsZero())
+
+	missingReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/missing/meta", nil)
+	missingRec := httptest.NewRecorder()
+	e.ServeHTTP(missingRec, missingReq)
+	assert.Equal(t, http.StatusNotFound, missingRec.Code)
+	assert.Contains(t, missingRec.Body.String(), `"CONFIG_NOT_FOUND"`)
+}
+
+// TestSchemaValidationErrorGrouped tests ?errors=grouped on POST /api/v1/configs
+func TestSchemaValidationErrorGrouped(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	reqBody := `{"name": "grouped-errors", "data": {"max_limit": "invalid-type"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs?errors=grouped", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var response struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				ValidationErrors map[string][]string `json:"validation_errors"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "SCHEMA_VALIDATION_FAILED", response.Error.Code)
+	assert.Len(t, response.Error.Details.ValidationErrors["max_limit"], 1)
+	assert.Len(t, response.Error.Details.ValidationErrors["enabled"], 1)
+
+	// Without the option the array shape is kept
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), `"validation_errors":[`)
+}
+
+// TestUnknownConfigFieldError tests that a stray key is reported as UNKNOWN_CONFIG_FIELD
+func TestUnknownConfigFieldError(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	reqBody := `{"name": "stray-key", "data": {"max_limit": 10, "enabled": true, "max_limt": 20}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var response struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Details struct {
+				UnknownFields []string `json:"unknown_fields"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "UNKNOWN_CONFIG_FIELD", response.Error.Code)
+	assert.Contains(t, response.Error.Message, "'max_limt'")
+	assert.Equal(t, []string{"max_limt"}, response.Error.Details.UnknownFields)
+
+	// Mixed with other failures the generic code is kept
+	reqBody = `{"name": "stray-key", "data": {"max_limit": "ten", "enabled": true, "max_limt": 20}}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), `"SCHEMA_VALIDATION_FAILED"`)
+}
+
+// TestCreateConfigSchemaMismatch tests conditional creation with schema_hash on POST /api/v1/configs
+func TestCreateConfigSchemaMismatch(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	validationService, err := services.NewValidationService()
+	if err != nil {
+		t.Fatal("Failed to create validation service:", err)
+	}
+	activeHash, _ := validationService.ActiveSchema()
+
+	staleBody := `{"name": "schema-bound", "schema_hash": "0000", "data": {"max_limit": 1, "enabled": true}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(staleBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"SCHEMA_MISMATCH"`)
+	assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"active_schema_hash":"%s"`, activeHash))
+
+	// Nothing was created
+	getReq := httptest.NewRequest(http.MethodGet, "/api/v1/configs/schema-bound", nil)
+	getRec := httptest.NewRecorder()
+	e.ServeHTTP(getRec, getReq)
+	assert.Equal(t, http.StatusNotFound, getRec.Code)
+
+	matchingBody := fmt.Sprintf(`{"name": "schema-bound", "schema_hash": "%s", "data": {"max_limit": 1, "enabled": true}}`, activeHash)
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(matchingBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+// TestGetConfigVersionInvalidNumber tests that malformed version path parameters are rejected
+// with INVALID_VERSION_NUMBER, telling non-numeric input apart from out-of-range numbers
+func TestGetConfigVersionInvalidNumber(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	for version, reason := range map[string]string{
+		"99999999999999999999": "out_of_range",
+		"2147483648":           "out_of_range",
+		"0":                    "out_of_range",
+		"-1":                   "out_of_range",
+		"abc":                  "not_a_number",
+		"1.5":                  "not_a_number",
+	} {
+		for _, suffix := range []string{"", "/schema"} {
+			target := "/api/v1/configs/app-settings/versions/" + version + suffix
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+			assert.Contains(t, rec.Body.String(), `"INVALID_VERSION_NUMBER"`, target)
+			assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"reason":"%s"`, reason), target)
+		}
+	}
+
+	// The largest accepted number is a well-formed request for a version that does not exist
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/2147483647", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestGetLatestConfigDefaultFallback tests GET /api/v1/configs/{name}?default=true
+func TestGetLatestConfigDefaultFallback(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+	put := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Without any default the flag changes nothing
+	rec := get("/api/v1/configs/first-run?default=true")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_NOT_FOUND"`)
+
+	assert.Equal(t, http.StatusOK, put("/admin/defaults", `{"data": {"max_limit": 10, "enabled": false}}`).Code)
+	assert.Equal(t, http.StatusOK, put("/admin/defaults/first-run", `{"data": {"max_limit": 50, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, put("/admin/defaults/first-run", `{"data": {"max_limit": "x"}}`).Code)
+
+	// Without the flag behavior stays 404
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/configs/first-run").Code)
+
+	rec = get("/api/v1/configs/first-run?default=true")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "name", rec.Header().Get("X-Config-Default"))
+	assert.Contains(t, rec.Body.String(), `"max_limit":50`)
+	assert.Contains(t, rec.Body.String(), `"version":0`)
+
+	rec = get("/api/v1/configs/other-app?default=true")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "global", rec.Header().Get("X-Config-Default"))
+	assert.Contains(t, rec.Body.String(), `"max_limit":10`)
+
+	// Once the real configuration exists it wins
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(`{"name": "first-run", "data": {"max_limit": 99, "enabled": true}}`))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	rec = get("/api/v1/configs/first-run?default=true")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("X-Config-Default"))
+	assert.Contains(t, rec.Body.String(), `"max_limit":99`)
+
+	deleteRec := httptest.NewRecorder()
+	e.ServeHTTP(deleteRec, httptest.NewRequest(http.MethodDelete, "/admin/defaults/missing", nil))
+	assert.Equal(t, http.StatusNotFound, deleteRec.Code)
+	assert.Contains(t, deleteRec.Body.String(), `"DEFAULT_CONFIG_NOT_FOUND"`)
+}
+
+// TestGetRawVersionDataEndpoint tests GET /admin/configs/{name}/versions/{version}/raw
+func TestGetRawVersionDataEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(`{"name": "raw-test", "data": {"max_limit": 100, "enabled": true}}`))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/configs/raw-test/versions/1/raw", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data models.RawVersionData `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "plain", response.Data.Encoding)
+	assert.JSONEq(t, `{"max_limit": 100, "enabled": true}`, response.Data.Stored)
+	assert.Equal(t, len(response.Data.Stored), response.Data.StoredBytes)
+	assert.NotEmpty(t, response.Data.BlobHash)
+	assert.Empty(t, response.Data.Decoded)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/configs/raw-test/versions/2/raw", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"VERSION_NOT_FOUND"`)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/configs/raw-test/versions/abc/raw", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// TestRenameConfigEndpoint tests POST /api/v1/configs/{name}/rename
+func TestRenameConfigEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "old-name", "data": {"max_limit": 1, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/old-name", `{"data": {"max_limit": 2, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "taken", "data": {"max_limit": 3, "enabled": true}}`).Code)
+
+	rec := send(http.MethodPost, "/api/v1/configs/old-name/rename", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"MISSING_REQUIRED_FIELD"`)
+
+	rec = send(http.MethodPost, "/api/v1/configs/old-name/rename", `{"new_name": "bad name"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_CONFIG_NAME"`)
+
+	rec = send(http.MethodPost, "/api/v1/configs/old-name/rename", `{"new_name": "taken"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_ALREADY_EXISTS"`)
+
+	rec = send(http.MethodPost, "/api/v1/configs/missing/rename", `{"new_name": "anything"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = send(http.MethodPost, "/api/v1/configs/old-name/rename", `{"new_name": "new-name"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"new-name"`)
+	assert.Contains(t, rec.Body.String(), `"current_version":2`)
+
+	// History moves with the configuration and the old name is gone
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/old-name", "").Code)
+	rec = send(http.MethodGet, "/api/v1/configs/new-name/versions/1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"max_limit":1`)
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/new-name", `{"data": {"max_limit": 4, "enabled": true}}`).Code)
+	rec = send(http.MethodGet, "/api/v1/configs/new-name", "")
+	assert.Contains(t, rec.Body.String(), `"version":3`)
+}
+
+// TestCacheControlPolicies checks the Cache-Control policy declared for each kind of route
+func TestCacheControlPolicies(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(`{"name": "cached", "data": {"max_limit": 1, "enabled": true}}`))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	tests := []struct {
+		target string
+		status int
+		policy string
+	}{
+		{"/api/v1/configs/cached", http.StatusOK, "no-cache"},
+		{"/api/v1/configs/cached/versions", http.StatusOK, "no-cache"},
+		{"/api/v1/configs/cached/versions/1", http.StatusOK, "no-cache"},
+		// A version that does not exist yet must not be cached as missing
+		{"/api/v1/configs/cached/versions/2", http.StatusNotFound, "no-store"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		assert.Equal(t, tt.status, rec.Code, tt.target)
+		assert.Equal(t, tt.policy, rec.Header().Get(echo.HeaderCacheControl), tt.target)
+	}
+}
+
+// TestValidateConfigEndpoint tests POST /api/v1/configs/{name}/validate
+func TestValidateConfigEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "checked", "data": {"max_limit": 1, "enabled": true}}`).Code)
+
+	var response struct {
+		Data models.ValidationResult `json:"data"`
+	}
+
+	rec := send(http.MethodPost, "/api/v1/configs/checked/validate", `{"data": {"max_limit": 5, "enabled": false}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.Data.Valid)
+	assert.NotEmpty(t, response.Data.SchemaHash)
+	assert.Empty(t, response.Data.ValidationErrors)
+
+	rec = send(http.MethodPost, "/api/v1/configs/checked/validate", `{"data": {"max_limit": "lots", "enabled": true, "colour": "red"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	response.Data = models.ValidationResult{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Data.Valid)
+	assert.NotEmpty(t, response.Data.ValidationErrors)
+
+	// A status disagreeing with enabled is invalid, as it would be on write
+	rec = send(http.MethodPost, "/api/v1/configs/checked/validate", `{"data": {"max_limit": 5, "status": "off", "enabled": true}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	response.Data = models.ValidationResult{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Data.Valid)
+	if assert.Len(t, response.Data.ValidationErrors, 1) {
+		assert.Equal(t, "enabled", response.Data.ValidationErrors[0].Field)
+		assert.Equal(t, "status_enabled_mismatch", response.Data.ValidationErrors[0].Type)
+	}
+
+	// Data over the size budget is refused like a write
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/admin/budgets/checked", `{"max_data_bytes": 40}`).Code)
+	rec = send(http.MethodPost, "/api/v1/configs/checked/validate", `{"data": {"max_limit": 5, "enabled": true, "note": "well over forty bytes"}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_BUDGET_EXCEEDED"`)
+
+	// Validation never writes
+	rec = send(http.MethodGet, "/api/v1/configs/checked", "")
+	assert.Contains(t, rec.Body.String(), `"version":1`)
+
+	rec = send(http.MethodPost, "/api/v1/configs/checked/validate", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"MISSING_REQUIRED_FIELD"`)
+
+	rec = send(http.MethodPost, "/api/v1/configs/missing/validate", `{"data": {"max_limit": 5, "enabled": true}}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_NOT_FOUND"`)
+}
+
+// TestEvaluateConfigEndpoint tests GET /api/v1/configs/{name}/evaluate
+func TestEvaluateConfigEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(`{"name": "checkout-flag", "data": {"max_limit": 1000, "enabled": true}}`))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/checkout-flag/evaluate?key=user-42", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// The body is flat, without the success envelope
+	assert.JSONEq(t, `{"name": "checkout-flag", "version": 1, "enabled": true, "max_limit": 1000}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/missing/evaluate", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_NOT_FOUND"`)
+}
+
+// TestEvaluateConfigRollout tests gradual rollouts evaluated per key
+func TestEvaluateConfigRollout(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	evaluate := func(key string) models.FlagEvaluation {
+		rec := send(http.MethodGet, "/api/v1/configs/gradual/evaluate?key="+key, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var evaluation models.FlagEvaluation
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &evaluation))
+		return evaluation
+	}
+	enabledCount := func() int {
+		count := 0
+		for i := 0; i < 200; i++ {
+			if evaluate(fmt.Sprintf("user-%d", i)).Enabled {
+				count++
+			}
+		}
+		return count
+	}
+
+	rec := send(http.MethodPost, "/api/v1/configs", `{"name": "gradual", "data": {"max_limit": 10, "enabled": true, "rollout_percent": 150}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = send(http.MethodPost, "/api/v1/configs", `{"name": "gradual", "data": {"max_limit": 10, "enabled": true, "rollout_percent": 30, "rollout_seed": "checkout"}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// Bucketing is deterministic per key and roughly matches the percentage
+	first := evaluate("user-7")
+	assert.Equal(t, first, evaluate("user-7"))
+	assert.NotNil(t, first.Bucket)
+	assert.Equal(t, *first.Bucket < 30, first.Enabled)
+	atThirty := enabledCount()
+	assert.InDelta(t, 60, atThirty, 25)
+
+	// Without a key only a full rollout is on
+	rec = send(http.MethodGet, "/api/v1/configs/gradual/evaluate", "")
+	assert.Contains(t, rec.Body.String(), `"enabled":false`)
+	assert.NotContains(t, rec.Body.String(), `"bucket"`)
+
+	// Raising the percentage keeps everyone already enabled
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/gradual", `{"data": {"max_limit": 10, "enabled": true, "rollout_percent": 60, "rollout_seed": "checkout"}}`).Code)
+	assert.True(t, evaluate("user-7").Enabled || !first.Enabled)
+	assert.Greater(t, enabledCount(), atThirty)
+
+	// A disabled flag stays off whatever the bucket
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/gradual", `{"data": {"max_limit": 10, "enabled": false, "rollout_percent": 100}}`).Code)
+	assert.Zero(t, enabledCount())
+
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/gradual", `{"data": {"max_limit": 10, "enabled": true, "rollout_percent": 100}}`).Code)
+	assert.Equal(t, 200, enabledCount())
+}
+
+// TestListAllLatestConfigsEndpoint tests GET /api/v1/configs/all in JSON and JSON Lines
+func TestListAllLatestConfigsEndpoint(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	get := func(target, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set(echo.HeaderAccept, accept)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// An empty catalog is an empty list, not an error
+	rec := get("/api/v1/configs/all", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"data":[]`)
+	rec = get("/api/v1/configs/all?format=jsonl", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	for _, body := range []string{
+		`{"name": "bravo", "data": {"max_limit": 2, "enabled": true}}`,
+		`{"name": "alpha", "data": {"max_limit": 1, "enabled": false}}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		createRec := httptest.NewRecorder()
+		e.ServeHTTP(createRec, req)
+		assert.Equal(t, http.StatusCreated, createRec.Code)
+	}
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/v1/configs/bravo", strings.NewReader(`{"data": {"max_limit": 3, "enabled": true}}`))
+	updateReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(httptest.NewRecorder(), updateReq)
+
+	rec = get("/api/v1/configs/all", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data []models.ConfigurationData `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 2) {
+		assert.Equal(t, "alpha", response.Data[0].Name)
+		assert.Equal(t, "bravo", response.Data[1].Name)
+		assert.Equal(t, 2, response.Data[1].Version)
+		assert.Equal(t, 3, response.Data[1].ConfigData.MaxLimit)
+	}
+
+	for _, rec := range []*httptest.ResponseRecorder{
+		get("/api/v1/configs/all?format=jsonl", ""),
+		get("/api/v1/configs/all", "application/x-ndjson"),
+	} {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		if assert.Len(t, lines, 2) {
+			var line models.ConfigurationData
+			assert.NoError(t, json.Unmarshal([]byte(lines[1]), &line))
+			assert.Equal(t, "bravo", line.Name)
+			assert.Equal(t, 3, line.ConfigData.MaxLimit)
+		}
+	}
+
+	rec = get("/api/v1/configs/all?format=xml", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_RESPONSE_FORMAT"`)
+}
+
+// TestDuplicateJSONKeyRejected tests that data repeating a key in any object is rejected before validation
+func TestDuplicateJSONKeyRejected(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/api/v1/configs", `{"name": "dupes", "data": {"max_limit": 1, "max_limit": 2, "enabled": true}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"DUPLICATE_JSON_KEY"`)
+	assert.Contains(t, rec.Body.String(), `"key":"max_limit"`)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/dupes", "").Code)
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "dupes", "data": {"max_limit": 1, "enabled": true}}`).Code)
+
+	// Nested objects are checked too, and the path locates the object
+	rec = send(http.MethodPut, "/api/v1/configs/dupes", `{"data": {"max_limit": 1, "enabled": true, "rules": [{"id": 1}, {"id": 2, "id": 3}]}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"DUPLICATE_JSON_KEY"`)
+	assert.Contains(t, rec.Body.String(), `"path":"rules[1]"`)
+
+	// The same key in sibling objects is not a duplicate; this fails only on the schema
+	rec = send(http.MethodPut, "/api/v1/configs/dupes", `{"data": {"max_limit": 1, "enabled": true, "rules": [{"id": 1}, {"id": 2}]}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestConfigBudgets(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "hot", "data": {"max_limit": 10, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "quiet", "data": {"max_limit": 10, "enabled": true}}`).Code)
+
+	rec := send(http.MethodPut, "/admin/budgets/hot", `{"max_requests_per_minute": -1}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_BUDGET"`)
+
+	rec = send(http.MethodPut, "/admin/budgets/hot", `{"max_requests_per_minute": 3, "max_data_bytes": 40}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"max_requests_per_minute":3`)
+
+	// Oversized data is rejected with 413
+	rec = send(http.MethodPut, "/api/v1/configs/hot", `{"data": {"max_limit": 1000, "enabled": true, "rollout_percent": 50}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_BUDGET_EXCEEDED"`)
+
+	// The rejected write counted as the first of three requests this minute
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/configs/hot", "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/configs/hot", "").Code)
+	rec = send(http.MethodGet, "/api/v1/configs/hot", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_BUDGET_EXCEEDED"`)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	// Other configurations are unaffected
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/configs/quiet", "").Code)
+	}
+
+	// JSON-RPC calls count against the same budget
+	rpc := `{"jsonrpc": "2.0", "method": "getLatestConfig", "params": {"name": "hot"}, "id": 1}`
+	rec = send(http.MethodPost, "/rpc", rpc)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_BUDGET_EXCEEDED"`)
+	assert.Contains(t, rec.Body.String(), `"code":-32000`)
+	rec = send(http.MethodPost, "/rpc", strings.Replace(rpc, `"hot"`, `"quiet"`, 1))
+	assert.NotContains(t, rec.Body.String(), `"error"`)
+
+	rec = send(http.MethodGet, "/admin/budgets", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"hot"`)
+
+	assert.Equal(t, http.StatusOK, send(http.MethodDelete, "/admin/budgets/hot", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodDelete, "/admin/budgets/hot", "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/configs/hot", "").Code)
+
+	// RPC calls use up the budget for REST requests as well
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/admin/budgets/quiet", `{"max_requests_per_minute": 1}`).Code)
+	rec = send(http.MethodPost, "/rpc", strings.Replace(rpc, `"hot"`, `"quiet"`, 1))
+	assert.NotContains(t, rec.Body.String(), `"error"`)
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodGet, "/api/v1/configs/quiet", "").Code)
+}
+
+func TestSchemaImpact(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "small", "data": {"max_limit": 10, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "large", "data": {"max_limit": 5000, "enabled": true}}`).Code)
+
+	candidate := `{"schema": {"type": "object", "properties": {"max_limit": {"type": "integer", "maximum": 1000}}}}`
+	rec := send(http.MethodPost, "/admin/schema/impact", candidate)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data models.SchemaImpactReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Data.Checked)
+	assert.Equal(t, 1, response.Data.Passing)
+	assert.Equal(t, 1, response.Data.Failing)
+	if assert.Len(t, response.Data.Violations, 1) {
+		assert.Equal(t, "large", response.Data.Violations[0].Name)
+		assert.Equal(t, "max_limit", response.Data.Violations[0].ValidationErrors[0].Field)
+	}
+	assert.NotEqual(t, response.Data.ActiveSchemaHash, response.Data.SchemaHash)
+
+	// The candidate was not activated, so the large limit is still accepted
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/small", `{"data": {"max_limit": 9000, "enabled": true}}`).Code)
+
+	rec = send(http.MethodPost, "/admin/schema/impact", `{"schema": {"type": 12}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_SCHEMA"`)
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/admin/schema/impact", `{}`).Code)
+}
+
+func TestGetConfigFlatFormats(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createBody := `{"name": "app-settings", "data": {"max_limit": 1000, "enabled": true, "rollout_seed": "it's"}}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(createBody))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	createRec := httptest.NewRecorder()
+	e.ServeHTTP(createRec, createReq)
+	assert.Equal(t, http.StatusCreated, createRec.Code)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings?format=flat", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"max_limit": 1000, "enabled": true, "rollout_seed": "it's"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings?format=env", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/plain")
+	assert.Equal(t, "export ENABLED=true\nexport MAX_LIMIT=1000\nexport ROLLOUT_SEED='it'\\''s'\n", rec.Body.String())
+
+	// Each representation of a version has its own ETag
+	jsonRec := httptest.NewRecorder()
+	e.ServeHTTP(jsonRec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/1", nil))
+	envRec := httptest.NewRecorder()
+	e.ServeHTTP(envRec, httptest.NewRequest(http.MethodGet, "/api/v1/configs/app-settings/versions/1?format=env", nil))
+	assert.Equal(t, http.StatusOK, envRec.Code)
+	assert.NotEqual(t, jsonRec.Header().Get("ETag"), envRec.Header().Get("ETag"))
+}
+
+func TestProtectVersion(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "certified-prod", "data": {"max_limit": 1, "enabled": true}}`).Code)
+	for limit := 2; limit <= 3; limit++ {
+		body := fmt.Sprintf(`{"data": {"max_limit": %d, "enabled": true}}`, limit)
+		assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/certified-prod", body).Code)
+	}
+
+	rec := send(http.MethodPost, "/api/v1/configs/certified-prod/versions/2/protect", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"protected":true`)
+	// Protecting again is a no-op
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/configs/certified-prod/versions/2/protect", "").Code)
+
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPost, "/api/v1/configs/certified-prod/versions/9/protect", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPost, "/api/v1/configs/missing/versions/1/protect", "").Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/configs/certified-prod/versions/0/protect", "").Code)
+
+	rec = send(http.MethodGet, "/api/v1/configs/certified-prod/versions", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var listing struct {
+		Data models.VersionList `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listing))
+	for _, version := range listing.Data.Versions {
+		assert.Equal(t, version.Version == 2, version.Protected, "version %d", version.Version)
+	}
+
+	// Squashing and deleting would remove or renumber version 2, so both refuse
+	rec = send(http.MethodPost, "/api/v1/configs/certified-prod/squash?keep_from=3&confirm=true", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"VERSION_PROTECTED"`)
+
+	rec = send(http.MethodDelete, "/api/v1/configs?name_prefix=certified-&confirm=true", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"VERSION_PROTECTED"`)
+
+	rec = send(http.MethodGet, "/api/v1/configs/certified-prod/versions/2", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGetConfigStorage(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "app-settings", "data": {"max_limit": 1, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/app-settings", `{"data": {"max_limit": 1000000, "enabled": false}}`).Code)
+
+	rec := send(http.MethodGet, "/api/v1/configs/app-settings/storage", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data models.StorageFootprint `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "app-settings", response.Data.Name)
+	assert.Equal(t, 2, response.Data.VersionCount)
+	// Data is stored as submitted
+	assert.Equal(t, int64(len(`{"max_limit": 1, "enabled": true}`)+len(`{"max_limit": 1000000, "enabled": false}`)), response.Data.TotalBytes)
+	assert.Equal(t, int64(36), response.Data.AverageBytesPerVersion)
+
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/missing/storage", "").Code)
+}
+
+func TestConfigBase64Data(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	encode := func(data string) string {
+		return base64.StdEncoding.EncodeToString([]byte(data))
+	}
+
+	body := fmt.Sprintf(`{"name": "binary-safe", "data": %q, "data_encoding": "base64"}`, encode(`{"max_limit": 100, "enabled": true}`))
+	rec := send(http.MethodPost, "/api/v1/configs", body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	body = fmt.Sprintf(`{"data": %q, "data_encoding": "base64"}`, encode(`{"max_limit": 200, "enabled": false}`))
+	rec = send(http.MethodPut, "/api/v1/configs/binary-safe", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = send(http.MethodGet, "/api/v1/configs/binary-safe", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"max_limit":200`)
+
+	// Decoded data is validated against the schema like any other
+	body = fmt.Sprintf(`{"data": %q, "data_encoding": "base64"}`, encode(`{"max_limit": -1, "enabled": true}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, send(http.MethodPut, "/api/v1/configs/binary-safe", body).Code)
+
+	for _, body := range []string{
+		`{"name": "bad-base64", "data": "not*base64!", "data_encoding": "base64"}`,
+		fmt.Sprintf(`{"name": "bad-base64", "data": %q, "data_encoding": "base64"}`, encode(`max_limit: 1`)),
+		`{"name": "bad-base64", "data": {"max_limit": 1, "enabled": true}, "data_encoding": "base64"}`,
+		`{"name": "bad-base64", "data": {"max_limit": 1, "enabled": true}, "data_encoding": "hex"}`,
+	} {
+		rec = send(http.MethodPost, "/api/v1/configs", body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		assert.Contains(t, rec.Body.String(), "INVALID_DATA_ENCODING", body)
+	}
+}
+
+func TestUpdateConfigCreateIfMissing(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Strict by default
+	body := `{"data": {"max_limit": 10, "enabled": true}}`
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPut, "/api/v1/configs/provisioned", body).Code)
+
+	rec := send(http.MethodPut, "/api/v1/configs/provisioned?create_if_missing=true", body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":1`)
+
+	// Once it exists the same request is a plain update
+	rec = send(http.MethodPut, "/api/v1/configs/provisioned?create_if_missing=true", `{"data": {"max_limit": 20, "enabled": true}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":2`)
+
+	// Data is validated before anything is created
+	assert.Equal(t, http.StatusUnprocessableEntity, send(http.MethodPut, "/api/v1/configs/never-created?create_if_missing=true", `{"data": {"max_limit": -1}}`).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/never-created", "").Code)
+
+	// Names are checked as on create
+	rec = send(http.MethodPut, "/api/v1/configs/bad--name?create_if_missing=true", body)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_CONFIG_NAME")
+}
+
+func TestDraftPublish(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "regulated", "data": {"max_limit": 1, "enabled": true}}`).Code)
+
+	rec := send(http.MethodPut, "/api/v1/configs/regulated?draft=true", `{"data": {"max_limit": 2, "enabled": true}}`)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":2`)
+	assert.Contains(t, rec.Body.String(), `"status":"draft"`)
+	assert.Contains(t, rec.Body.String(), `"current_version":1`)
+
+	// The draft is invisible unless asked for
+	rec = send(http.MethodGet, "/api/v1/configs/regulated", "")
+	assert.Contains(t, rec.Body.String(), `"version":1`)
+	rec = send(http.MethodGet, "/api/v1/configs/regulated?include_drafts=true", "")
+	assert.Contains(t, rec.Body.String(), `"version":2`)
+	assert.Contains(t, rec.Body.String(), `"draft":true`)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/regulated/versions/2", "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/configs/regulated/versions/2?include_drafts=true", "").Code)
+
+	var listResponse struct {
+		Data models.VersionList `json:"data"`
+	}
+	rec = send(http.MethodGet, "/api/v1/configs/regulated/versions", "")
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listResponse))
+	assert.Len(t, listResponse.Data.Versions, 1)
+	rec = send(http.MethodGet, "/api/v1/configs/regulated/versions?include_drafts=true", "")
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listResponse))
+	if assert.Len(t, listResponse.Data.Versions, 2) {
+		assert.Equal(t, models.VersionStatusDraft, listResponse.Data.Versions[0].Status)
+		assert.Equal(t, models.VersionStatusPublished, listResponse.Data.Versions[1].Status)
+	}
+
+	// A direct update goes above the pending draft, which can then no longer be published
+	rec = send(http.MethodPut, "/api/v1/configs/regulated", `{"data": {"max_limit": 3, "enabled": true}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":3`)
+	rec = send(http.MethodPost, "/api/v1/configs/regulated/versions/2/publish", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "DRAFT_SUPERSEDED")
+
+	assert.Equal(t, http.StatusAccepted, send(http.MethodPut, "/api/v1/configs/regulated?draft=true", `{"data": {"max_limit": 4, "enabled": true}}`).Code)
+	// Rolling back to a draft would skip the approval
+	rec = send(http.MethodPost, "/api/v1/configs/regulated/rollback", `{"target_version": 4}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "VERSION_IS_DRAFT")
+
+	rec = send(http.MethodPost, "/api/v1/configs/regulated/versions/4/publish", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"previous_version":3`)
+	rec = send(http.MethodGet, "/api/v1/configs/regulated", "")
+	assert.Contains(t, rec.Body.String(), `"version":4`)
+	assert.Contains(t, rec.Body.String(), `"max_limit":4`)
+
+	rec = send(http.MethodPost, "/api/v1/configs/regulated/versions/4/publish", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "VERSION_NOT_DRAFT")
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPost, "/api/v1/configs/regulated/versions/9/publish", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPut, "/api/v1/configs/missing?draft=true", `{"data": {"max_limit": 1, "enabled": true}}`).Code)
+}
+
+func TestCompareConfigs(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "checkout-staging", "data": {"max_limit": 100, "enabled": true, "rollout_percent": 50}}`).Code)
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "checkout-prod", "data": {"max_limit": 100, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/checkout-prod", `{"data": {"max_limit": 500, "enabled": true, "status": "on"}}`).Code)
+
+	var response struct {
+		Data models.ConfigComparison `json:"data"`
+	}
+	rec := send(http.MethodGet, "/api/v1/diff?a=checkout-staging&b=checkout-prod", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, models.DiffSide{Name: "checkout-staging", Version: 1}, response.Data.A)
+	assert.Equal(t, models.DiffSide{Name: "checkout-prod", Version: 2}, response.Data.B)
+	assert.False(t, response.Data.Identical)
+	assert.Equal(t, map[string]interface{}{"status": "on"}, response.Data.Added)
+	assert.Equal(t, map[string]interface{}{"rollout_percent": float64(50)}, response.Data.Removed)
+	assert.Equal(t, map[string]models.ValueChange{"max_limit": {Old: float64(100), New: float64(500)}}, response.Data.Changed)
+
+	// Per-side versions
+	rec = send(http.MethodGet, "/api/v1/diff?a=checkout-prod&a_version=1&b=checkout-prod&b_version=1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"identical":true`)
+
+	rec = send(http.MethodGet, "/api/v1/diff?a=checkout-staging&b=checkout-dev", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "checkout-dev")
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/diff?a=checkout-prod&b=checkout-prod&b_version=9", "").Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/api/v1/diff?a=checkout-prod", "").Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/api/v1/diff?a=checkout-prod&b=checkout-staging&a_version=x", "").Code)
+}
+
+func TestSoftDeleteConfig(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "retired", "data": {"max_limit": 1, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/retired", `{"data": {"max_limit": 2, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "kept", "data": {"max_limit": 1, "enabled": true}}`).Code)
+
+	rec := send(http.MethodDelete, "/api/v1/configs/retired", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"deleted_at"`)
+
+	// A deleted configuration is hidden from reads and writes
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/retired", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/retired/versions", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/retired/versions/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPut, "/api/v1/configs/retired", `{"data": {"max_limit": 3, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodDelete, "/api/v1/configs/retired", "").Code)
+
+	list := func(query string) models.ConfigurationList {
+		rec := send(http.MethodGet, "/api/v1/configs"+query, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			Data models.ConfigurationList `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response.Data
+	}
+	live := list("")
+	assert.Equal(t, 1, live.Pagination.Total)
+	assert.Equal(t, "kept", live.Configurations[0].Name)
+
+	all := list("?include_deleted=true")
+	assert.Equal(t, 2, all.Pagination.Total)
+	assert.Equal(t, "retired", all.Configurations[1].Name)
+	assert.NotNil(t, all.Configurations[1].DeletedAt)
+	assert.Nil(t, all.Configurations[0].DeletedAt)
+
+	// The name stays taken: creating it again asks for a restore
+	rec = send(http.MethodPost, "/api/v1/configs", `{"name": "retired", "data": {"max_limit": 5, "enabled": true}}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_DELETED"`)
+
+	rec = send(http.MethodPost, "/api/v1/configs/retired/restore", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"current_version":2`)
+
+	rec = send(http.MethodGet, "/api/v1/configs/retired", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"max_limit":2`)
+
+	rec = send(http.MethodPost, "/api/v1/configs/retired/restore", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"CONFIG_NOT_DELETED"`)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPost, "/api/v1/configs/missing/restore", "").Code)
+}
+
+func TestDiffVersions(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "feature-toggle", "data": {"max_limit": 100, "enabled": true, "rollout_seed": "a"}}`).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/feature-toggle", `{"data": {"max_limit": 200, "enabled": true}}`).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/feature-toggle", `{"data": {"max_limit": 500, "enabled": true, "rollout_percent": 25}}`).Code)
+
+	var response struct {
+		Data models.VersionDiff `json:"data"`
+	}
+	rec := send(http.MethodGet, "/api/v1/configs/feature-toggle/diff?from=1&to=3", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Data.From)
+	assert.Equal(t, 3, response.Data.To)
+	assert.False(t, response.Data.Identical)
+	assert.Equal(t, map[string]interface{}{"rollout_percent": float64(25)}, response.Data.Added)
+	assert.Equal(t, map[string]interface{}{"rollout_seed": "a"}, response.Data.Removed)
+	assert.Equal(t, map[string]models.ValueChange{"max_limit": {Old: float64(100), New: float64(500)}}, response.Data.Changed)
+
+	rec = send(http.MethodGet, "/api/v1/configs/feature-toggle/diff?from=2&to=2", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"identical":true`)
+
+	// A unified diff of the pretty-printed data for pasting into review
+	rec = send(http.MethodGet, "/api/v1/configs/feature-toggle/diff?from=1&to=3&format=unified", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMETextPlainCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `--- feature-toggle@1
++++ feature-toggle@3
+@@ -1,5 +1,5 @@
+ {
+   "enabled": true,
+-  "max_limit": 100,
+-  "rollout_seed": "a"
++  "max_limit": 500,
++  "rollout_percent": 25
+ }
+`, rec.Body.String())
+	rec = send(http.MethodGet, "/api/v1/configs/feature-toggle/diff?from=2&to=2&format=unified", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	rec = send(http.MethodGet, "/api/v1/configs/feature-toggle/diff?from=1&to=3&format=patch", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVALID_RESPONSE_FORMAT"`)
+
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/feature-toggle/diff?from=1&to=9", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/configs/missing/diff?from=1&to=2", "").Code)
+	for _, query := range []string{"?from=1", "?to=2", "?from=0&to=2", "?from=1&to=-3", "?from=one&to=2"} {
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/api/v1/configs/feature-toggle/diff"+query, "").Code, query)
+	}
+
+	// Nested objects are diffed key by key rather than replaced wholesale
+	diff, err := services.DiffData(`{"limits": {"read": 1, "write": 2}, "tags": ["a"]}`, `{"limits": {"read": 1, "write": 3, "burst": 9}, "tags": ["a", "b"]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"limits.burst": json.Number("9"), "tags.1": "b"}, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Equal(t, map[string]models.ValueChange{"limits.write": {Old: json.Number("2"), New: json.Number("3")}}, diff.Changed)
+}
+
+func TestConfigTags(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	listNames := func(target string) []string {
+		rec := do(http.MethodGet, target, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			Data models.ConfigurationList `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		names := []string{}
+		for _, config := range response.Data.Configurations {
+			names = append(names, config.Name)
+		}
+		return names
+	}
+
+	for _, name := range []string{"checkout", "payouts", "search"} {
+		rec := do(http.MethodPost, "/api/v1/configs", `{"name": "`+name+`", "data": {"max_limit": 1000, "enabled": true}}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	// Tag the configurations
+	rec := do(http.MethodPut, "/api/v1/configs/checkout/tags", `{"tags": {"team": "payments", "env": "prod"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = do(http.MethodPut, "/api/v1/configs/payouts/tags", `{"tags": {"team": "payments", "env": "staging"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = do(http.MethodPut, "/api/v1/configs/search/tags", `{"tags": {"team": "discovery", "env": "prod"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Setting a tag again replaces its value and keeps the others
+	rec = do(http.MethodPut, "/api/v1/configs/payouts/tags", `{"tags": {"env": "prod", "owner": "alice"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var tagsResponse struct {
+		Data models.ConfigurationTags `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tagsResponse))
+	assert.Equal(t, map[string]string{"team": "payments", "env": "prod", "owner": "alice"}, tagsResponse.Data.Tags)
+
+	rec = do(http.MethodGet, "/api/v1/configs/checkout/tags", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	tagsResponse.Data = models.ConfigurationTags{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tagsResponse))
+	assert.Equal(t, map[string]string{"team": "payments", "env": "prod"}, tagsResponse.Data.Tags)
+
+	// One tag filter
+	assert.Equal(t, []string{"checkout", "payouts"}, listNames("/api/v1/configs?tag=team:payments"))
+
+	// Two tag filters must both match
+	rec = do(http.MethodDelete, "/api/v1/configs/payouts/tags/env", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"checkout"}, listNames("/api/v1/configs?tag=team:payments&tag=env:prod"))
+	assert.Equal(t, []string{"checkout", "search"}, listNames("/api/v1/configs?tag=env:prod"))
+	assert.Empty(t, listNames("/api/v1/configs?tag=team:payments&tag=team:discovery"))
+
+	// Removing a tag the configuration does not have
+	rec = do(http.MethodDelete, "/api/v1/configs/payouts/tags/env", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "TAG_NOT_FOUND")
+
+	// Malformed selectors and tags
+	rec = do(http.MethodGet, "/api/v1/configs?tag=team", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_TAG")
+	rec = do(http.MethodPut, "/api/v1/configs/search/tags", `{"tags": {"bad key": "x"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_TAG")
+	rec = do(http.MethodPut, "/api/v1/configs/search/tags", `{"tags": {}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Unknown configurations
+	rec = do(http.MethodPut, "/api/v1/configs/missing/tags", `{"tags": {"team": "payments"}}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = do(http.MethodGet, "/api/v1/configs/missing/tags", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRequestID(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	var logs bytes.Buffer
+	e.Use(appmiddleware.RequestLogger(slog.New(slog.NewJSONHandler(&logs, nil))))
+
+	get := func(target, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if requestID != "" {
+			req.Header.Set(echo.HeaderXRequestID, requestID)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Generated when the caller sends none
+	rec := get("/api/v1/configs", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	generated := rec.Header().Get(echo.HeaderXRequestID)
+	assert.Regexp(t, "^[0-9a-f]{32}$", generated)
+	assert.NotEqual(t, generated, get("/api/v1/configs", "").Header().Get(echo.HeaderXRequestID))
+
+	// The caller's ID is kept, and unusable ones are replaced
+	rec = get("/api/v1/configs", "trace-123")
+	assert.Equal(t, "trace-123", rec.Header().Get(echo.HeaderXRequestID))
+	rec = get("/api/v1/configs", strings.Repeat("x", 200))
+	assert.Regexp(t, "^[0-9a-f]{32}$", rec.Header().Get(echo.HeaderXRequestID))
+
+	// Service errors carry the ID
+	rec = get("/api/v1/configs/missing", "trace-456")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "trace-456", rec.Header().Get(echo.HeaderXRequestID))
+	var errorResponse models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errorResponse))
+	assert.Equal(t, "trace-456", errorResponse.RequestID)
+
+	// Every request is logged as one JSON line
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	assert.Len(t, lines, 5)
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &entry))
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/api/v1/configs/missing", entry["path"])
+	assert.Equal(t, float64(http.StatusNotFound), entry["status"])
+	assert.Equal(t, "trace-456", entry["request_id"])
+	assert.Contains(t, entry, "latency_ms")
+}
+
+// TestGracefulShutdown checks that a request in flight when shutdown starts still completes
+// before the server stops
+func TestGracefulShutdown(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	e.GET("/test/slow", func(c echo.Context) error {
+		close(started)
+		<-release
+		return c.JSON(http.StatusOK, map[string]string{"status": "done"})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run(ctx, e, "127.0.0.1:0", 5*time.Second, nil)
+	}()
+	for i := 0; i < 100 && e.ListenerAddr() == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !assert.NotNil(t, e.ListenerAddr(), "server did not start listening") {
+		return
+	}
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Get("http://" + e.ListenerAddr().String() + "/test/slow")
+		assert.NoError(t, err)
+		responses <- resp
+	}()
+	<-started
+
+	// Shut down while the request is still being handled, then let it finish
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	resp := <-responses
+	if assert.NotNil(t, resp) {
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.NoError(t, <-runErr)
+}
+
+// TestRequestTimeout checks that a handler overrunning its route timeout gets a 503
+// REQUEST_TIMEOUT in place of its response, while a handler within it is unaffected
+func TestRequestTimeout(t *testing.T) {
+	e := echo.New()
+	timeout := appmiddleware.RequestTimeout(50 * time.Millisecond)
+	e.GET("/slow", func(c echo.Context) error {
+		time.Sleep(200 * time.Millisecond)
+		c.Response().Header().Set("X-Late", "true")
+		return c.JSON(http.StatusOK, map[string]string{"status": "late"})
+	}, timeout)
+	e.GET("/fast", func(c echo.Context) error {
+		c.Response().Header().Set("X-Fast", "true")
+		return c.JSON(http.StatusOK, map[string]string{"status": "done"})
+	}, timeout)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"REQUEST_TIMEOUT"`)
+	assert.NotContains(t, rec.Body.String(), "late")
+	assert.Empty(t, rec.Header().Get("X-Late"))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get("X-Fast"))
+	assert.Contains(t, rec.Body.String(), `"done"`)
+
+	// The 503 goes out when the timeout fires, not when the handler eventually returns
+	server := httptest.NewServer(e)
+	defer server.Close()
+	start := time.Now()
+	resp, err := http.Get(server.URL + "/slow")
+	if assert.NoError(t, err) {
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Less(t, time.Since(start), 150*time.Millisecond)
+	}
+}
+
+// TestServerAnswersDuringStartup checks that the listener is bound while startup work such as
+// migrations runs, with /ready reporting migrating and the gated API rejecting requests until it
+// is done
+func TestServerAnswersDuringStartup(t *testing.T) {
+	readiness := appmiddleware.NewReadiness()
+	e := echo.New()
+	e.GET("/ready", readiness.Handler)
+	e.GET("/api/v1/configs", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "served"})
+	}, readiness.Gate())
+
+	migrating := make(chan struct{})
+	finish := make(chan struct{})
+	startup := func() error {
+		close(migrating)
+		<-finish
+		readiness.MarkReady()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run(ctx, e, "127.0.0.1:0", 5*time.Second, startup)
+	}()
+	<-migrating
+
+	// The listener is already bound when startup runs
+	if !assert.NotNil(t, e.ListenerAddr(), "server not listening during startup") {
+		close(finish)
+		return
+	}
+	baseURL := "http://" + e.ListenerAddr().String()
+	get := func(path string) (int, string) {
+		resp, err := http.Get(baseURL + path)
+		if !assert.NoError(t, err) {
+			return 0, ""
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, `"migrating"`)
+	code, body = get("/api/v1/configs")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, `"SERVICE_NOT_READY"`)
+
+	// Once startup finishes the gate opens
+	close(finish)
+	for i := 0; i < 100 && readiness.State() != appmiddleware.ReadinessReady; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	code, body = get("/ready")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"ready"`)
+	code, _ = get("/api/v1/configs")
+	assert.Equal(t, http.StatusOK, code)
+
+	cancel()
+	assert.NoError(t, <-runErr)
+}
+
+// TestServerStopsWhenStartupFails checks that a failed startup, such as a broken migration,
+// stops the server and is returned
+func TestServerStopsWhenStartupFails(t *testing.T) {
+	e := echo.New()
+	err := server.Run(context.Background(), e, "127.0.0.1:0", 5*time.Second, func() error {
+		return errors.New("migration failed")
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "migration failed")
+	}
+}
+
+// logBody sends a request through the body logger, with "vault" flagged sensitive, and returns
+// what it logged
+func logBody(t *testing.T, method, path, contentType, body string) string {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	e := echo.New()
+	e.Use(appmiddleware.BodyLogger(appmiddleware.BodyLogConfig{
+		MaxBytes:    4096,
+		IsSensitive: func(name string) bool { return name == "vault" },
+	}))
+	ok := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	}
+	e.PATCH("/api/v1/configs/:name", ok)
+	echoBody := func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.JSONBlob(http.StatusOK, body)
+	}
+	e.POST("/api/v1/configs/batch", echoBody)
+	e.POST("/api/v1/import", echoBody)
+	e.POST("/rpc", echoBody)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	return logged.String()
+}
+
+// TestBodyLoggerRedactsPatches tests that patch documents of sensitive configurations are never
+// logged, whatever their content type
+func TestBodyLoggerRedactsPatches(t *testing.T) {
+	jsonPatch := `[{"op": "replace", "path": "/api_key", "value": "s3cret"}]`
+	mergePatch := `{"api_key": "s3cret"}`
+
+	for _, tc := range []struct{ contentType, body string }{
+		{services.PatchTypeJSONPatch, jsonPatch},
+		{services.PatchTypeMergePatch, mergePatch},
+	} {
+		logged := logBody(t, http.MethodPatch, "/api/v1/configs/vault", tc.contentType, tc.body)
+		assert.NotContains(t, logged, "s3cret", tc.contentType)
+		assert.Contains(t, logged, `request="[REDACTED]"`, tc.contentType)
+
+		// Other configurations are logged as sent
+		logged = logBody(t, http.MethodPatch, "/api/v1/configs/public", tc.contentType, tc.body)
+		assert.Contains(t, logged, "s3cret", tc.contentType)
+	}
+}
+
+// TestBodyLoggerRedactsBulkBodies tests that bodies not tied to a single configuration, which
+// may carry sensitive data, are logged fully redacted in both directions
+func TestBodyLoggerRedactsBulkBodies(t *testing.T) {
+	for _, tc := range []struct{ path, body string }{
+		{"/api/v1/configs/batch", `[{"name": "vault", "data": {"api_key": "s3cret"}}]`},
+		{"/api/v1/import", `{"format_version": 1, "configurations": [{"name": "vault", "versions": [{"version": 1, "data": {"api_key": "s3cret"}}]}]}`},
+		{"/rpc", `{"jsonrpc": "2.0", "method": "updateConfig", "params": {"name": "vault", "data": {"api_key": "s3cret"}}, "id": 1}`},
+	} {
+		logged := logBody(t, http.MethodPost, tc.path, echo.MIMEApplicationJSON, tc.body)
+		assert.NotContains(t, logged, "s3cret", tc.path)
+		assert.Contains(t, logged, `request="[REDACTED]" response="[REDACTED]"`, tc.path)
+	}
+}
+
+// createSensitiveConfig creates a configuration flagged sensitive, for access log tests
+func createSensitiveConfig(t *testing.T, e *echo.Echo, name string) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(fmt.Sprintf(`{"name": %q, "data": {"max_limit": 1, "enabled": true}}`, name)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/configs/"+name+"/sensitive", strings.NewReader(`{"sensitive": true}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// accessLogReads counts the recorded reads of a configuration by actor in the contract test database
+func accessLogReads(t *testing.T, name, actor string) int {
+	db, err := sql.Open("sqlite3", "./test_contract.db")
+	if err != nil {
+		t.Fatal("Failed to open test database:", err)
+	}
+	defer db.Close()
+
+	var count int
+	err = db.QueryRow(`SELECT COUNT(*) FROM access_log WHERE configuration_name = ? AND actor = ? AND action = ?`,
+		name, actor, models.AccessActionRead).Scan(&count)
+	if err != nil {
+		t.Fatal("Failed to count access log entries:", err)
+	}
+	return count
+}
+
+// TestListVersionsWithDataLogsAccess tests that listing a sensitive configuration's versions
+// with their data is audited, and listing without data is not
+func TestListVersionsWithDataLogsAccess(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createSensitiveConfig(t, e, "vault")
+
+	for _, query := range []string{"", "?include_data=true"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/vault/versions"+query, nil)
+		req.Header.Set("X-Actor", "auditor")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, query)
+	}
+
+	assert.Equal(t, 1, accessLogReads(t, "vault", "auditor"))
+
+	// The JSON-RPC listVersions audits the same way
+	for _, includeData := range []bool{false, true} {
+		reqBody := fmt.Sprintf(`{"jsonrpc": "2.0", "method": "listVersions", "params": {"name": "vault", "include_data": %t}, "id": 1}`, includeData)
+		req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Actor", "rpc-auditor")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), `"error"`)
+	}
+
+	assert.Equal(t, 1, accessLogReads(t, "vault", "rpc-auditor"))
+}
+
+// TestExportEnvironmentLogsAccess tests that exporting the environment audits each sensitive
+// configuration it returns
+func TestExportEnvironmentLogsAccess(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createSensitiveConfig(t, e, "vault")
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/configs", strings.NewReader(`{"name": "public", "data": {"max_limit": 1, "enabled": true}}`))
+	createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(httptest.NewRecorder(), createReq)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export/env", nil)
+	req.Header.Set("X-Actor", "auditor")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, 1, accessLogReads(t, "vault", "auditor"))
+	assert.Equal(t, 0, accessLogReads(t, "public", "auditor"))
+}
+
+// TestListAllLatestConfigsLogsAccess tests that listing every latest configuration audits the
+// sensitive ones, in both the json and jsonl formats
+func TestListAllLatestConfigsLogsAccess(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createSensitiveConfig(t, e, "vault")
+
+	for _, format := range []string{"json", "jsonl"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/all?format="+format, nil)
+		req.Header.Set("X-Actor", "auditor-"+format)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, format)
+		assert.Equal(t, 1, accessLogReads(t, "vault", "auditor-"+format), format)
+	}
+}
+
+// TestExportConfigsLogsAccess tests that the full export audits each sensitive configuration
+func TestExportConfigsLogsAccess(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createSensitiveConfig(t, e, "vault")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export", nil)
+	req.Header.Set("X-Actor", "auditor")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"vault"`)
+
+	assert.Equal(t, 1, accessLogReads(t, "vault", "auditor"))
+}
+
+// TestSyncConfigsLogsAccess tests that sync audits the sensitive configurations whose data it
+// returns, and not those the client is already up to date with
+func TestSyncConfigsLogsAccess(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createSensitiveConfig(t, e, "vault")
+
+	for _, body := range []string{`{"known": {}}`, `{"known": {"vault": 1}}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs/sync", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Actor", "auditor")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, body)
+	}
+
+	assert.Equal(t, 1, accessLogReads(t, "vault", "auditor"))
+}
+
+// TestGetRawVersionDataLogsAccess tests that reading a sensitive configuration's raw stored data
+// is audited
+func TestGetRawVersionDataLogsAccess(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createSensitiveConfig(t, e, "vault")
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/configs/vault/versions/1/raw", nil)
+	req.Header.Set("X-Actor", "auditor")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, 1, accessLogReads(t, "vault", "auditor"))
+}
+
+// TestVersionRevalidatedAfterSquash tests that a cached version cannot outlive a squash: the
+// version URL is revalidated, and the old ETag no longer matches once it serves other data
+func TestVersionRevalidatedAfterSquash(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "squashed", "data": {"max_limit": 1, "enabled": true}}`, "").Code)
+	for limit := 2; limit <= 3; limit++ {
+		assert.Equal(t, http.StatusOK, send(http.MethodPut, "/api/v1/configs/squashed", fmt.Sprintf(`{"data": {"max_limit": %d, "enabled": true}}`, limit), "").Code)
+	}
+
+	rec := send(http.MethodGet, "/api/v1/configs/squashed/versions/1", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	etag := rec.Header().Get("ETag")
+
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/configs/squashed/squash?keep_from=3&confirm=true", "", "").Code)
+
+	// Version 1 now holds what was version 3, so revalidating returns the new data
+	rec = send(http.MethodGet, "/api/v1/configs/squashed/versions/1", "", etag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), `"max_limit":3`)
+}
+
+// TestDraftVersionRevalidatedAfterPublish tests that a draft served on request is revalidated, and
+// that publishing it invalidates the cached draft response
+func TestDraftVersionRevalidatedAfterPublish(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path, body, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/configs", `{"name": "staged", "data": {"max_limit": 1, "enabled": true}}`, "").Code)
+	assert.Equal(t, http.StatusAccepted, send(http.MethodPut, "/api/v1/configs/staged?draft=true", `{"data": {"max_limit": 2, "enabled": true}}`, "").Code)
+
+	rec := send(http.MethodGet, "/api/v1/configs/staged/versions/2?include_drafts=true", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-cache", rec.Header().Get(echo.HeaderCacheControl))
+	assert.Contains(t, rec.Body.String(), `"draft":true`)
+	draftETag := rec.Header().Get("ETag")
+
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/configs/staged/versions/2/publish", "", "").Code)
+
+	rec = send(http.MethodGet, "/api/v1/configs/staged/versions/2?include_drafts=true", "", draftETag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"draft":true`)
+}
@@ -1,15 +1,28 @@
+//go:build sqlite || !postgres
+
 package integration
 
 import (
 	"config-manager/src/models"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"config-manager/migrations"
 	"config-manager/src/services"
 	"config-manager/src/storage"
+	"config-manager/tests/testutil"
 
 	_ "github.com/mattn/go-sqlite3"
 	_ "github.com/stretchr/testify/assert"
@@ -27,37 +40,8 @@ func (suite *DatabaseTestSuite) SetupTest() {
 	// Create temporary test database
 	testDB := "./test_config.db"
 
-	// Remove existing test database
-	_ = os.Remove(testDB)
-
-	db, err := sql.Open("sqlite3", testDB)
-	suite.Require().NoError(err)
-
-	// Create tables using the exact schema from migrations
-	schema := `
-	CREATE TABLE configurations (
-		name TEXT PRIMARY KEY,
-		current_version INTEGER NOT NULL,
-		created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-		updated_at TEXT DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE versions (
-		id INTEGER PRIMARY KEY,
-		configuration_name TEXT NOT NULL,
-		version_number INTEGER NOT NULL,
-		json_data TEXT NOT NULL,
-		created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (configuration_name) REFERENCES configurations(name),
-		UNIQUE(configuration_name, version_number)
-	);
-
-	CREATE INDEX idx_configurations_name ON configurations(name);
-	CREATE INDEX idx_versions_config_version ON versions(configuration_name, version_number);
-	CREATE INDEX idx_versions_config_created ON versions(configuration_name, created_at DESC);
-	`
-
-	_, err = db.Exec(schema)
+	// Create tables by applying the real migrations
+	db, err := testutil.OpenMigratedDB(testDB)
 	suite.Require().NoError(err)
 
 	suite.db = db
@@ -91,7 +75,7 @@ func (suite *DatabaseTestSuite) TestCreateConfiguration() {
 
 	// This will fail until ConfigService is implemented
 	service := services.NewConfigService(store, validationService)
-	config, err := service.CreateConfig(configName, jsonData)
+	config, err := service.CreateConfig(configName, jsonData, "")
 	suite.NoError(err)
 	suite.Equal(configName, config.Name)
 	suite.Equal(1, config.CurrentVersion)
@@ -120,11 +104,11 @@ func (suite *DatabaseTestSuite) TestUpdateConfiguration() {
 	service := services.NewConfigService(store, validationService)
 
 	// Create initial config
-	_, err = service.CreateConfig(configName, initialData)
+	_, err = service.CreateConfig(configName, initialData, "")
 	suite.NoError(err)
 
 	// Update config
-	updatedConfig, err := service.UpdateConfig(configName, updatedData)
+	updatedConfig, err := service.UpdateConfig(configName, updatedData, "")
 	suite.NoError(err)
 	suite.Equal(2, updatedConfig.CurrentVersion)
 
@@ -152,13 +136,13 @@ func (suite *DatabaseTestSuite) TestRollbackConfiguration() {
 	service := services.NewConfigService(store, validationService)
 
 	// Create and update config
-	_, err = service.CreateConfig(configName, version1Data)
+	_, err = service.CreateConfig(configName, version1Data, "")
 	suite.NoError(err)
-	_, err = service.UpdateConfig(configName, version2Data)
+	_, err = service.UpdateConfig(configName, version2Data, "")
 	suite.NoError(err)
 
 	// Rollback to version 1
-	rolledBackConfig, err := service.RollbackConfig(configName, 1)
+	rolledBackConfig, err := service.RollbackConfig(configName, 1, "")
 	suite.NoError(err)
 	suite.Equal(3, rolledBackConfig.CurrentVersion)
 
@@ -177,7 +161,7 @@ func (suite *DatabaseTestSuite) TestRetrieveLatestConfiguration() {
 	suite.Require().NoError(err)
 
 	service := services.NewConfigService(store, validationService)
-	_, err = service.CreateConfig(configName, jsonData)
+	_, err = service.CreateConfig(configName, jsonData, "")
 	suite.NoError(err)
 	config, err := service.GetLatestConfig(configName)
 	suite.NoError(err)
@@ -198,9 +182,9 @@ func (suite *DatabaseTestSuite) TestRetrieveSpecificVersion() {
 	service := services.NewConfigService(store, validationService)
 	version1Data := `{"max_limit": 1000, "enabled": true}`
 	version2Data := `{"max_limit": 2000, "enabled": false}`
-	_, err = service.CreateConfig(configName, version1Data)
+	_, err = service.CreateConfig(configName, version1Data, "")
 	suite.NoError(err)
-	_, err = service.UpdateConfig(configName, version2Data)
+	_, err = service.UpdateConfig(configName, version2Data, "")
 	suite.NoError(err)
 	config, err := service.GetConfigVersion(configName, 1)
 	suite.NoError(err)
@@ -223,11 +207,11 @@ func (suite *DatabaseTestSuite) TestListAllVersions() {
 	service := services.NewConfigService(store, validationService)
 	version1Data := `{"max_limit": 1000, "enabled": true}`
 	version2Data := `{"max_limit": 2000, "enabled": false}`
-	_, err = service.CreateConfig(configName, version1Data)
+	_, err = service.CreateConfig(configName, version1Data, "")
 	suite.NoError(err)
-	_, err = service.UpdateConfig(configName, version2Data)
+	_, err = service.UpdateConfig(configName, version2Data, "")
 	suite.NoError(err)
-	versions, err := service.ListVersions(configName)
+	versions, err := service.ListVersions(configName, services.ListVersionsOptions{})
 	suite.NoError(err)
 	suite.Len(versions.Versions, 2) // Assuming 2 versions exist
 
@@ -259,7 +243,7 @@ func (suite *DatabaseTestSuite) TestVersionNotFoundError() {
 	configName := "test-config"
 	service := services.NewConfigService(store, validationService)
 	version1Data := `{"max_limit": 1000, "enabled": true}`
-	_, _ = service.CreateConfig(version1Data, configName)
+	_, _ = service.CreateConfig(version1Data, configName, "")
 	_, err = service.GetConfigVersion(configName, 999)
 	suite.Error(err)
 	suite.Contains(err.Error(), "VERSION_NOT_FOUND")
@@ -281,7 +265,7 @@ func (suite *DatabaseTestSuite) TestDatabasePerformance() {
 	suite.Require().NoError(err)
 
 	service := services.NewConfigService(store, validationService)
-	_, err = service.CreateConfig(configName, jsonData)
+	_, err = service.CreateConfig(configName, jsonData, "")
 	suite.NoError(err)
 
 	elapsed := time.Since(start)
@@ -293,7 +277,889 @@ func (suite *DatabaseTestSuite) TestDatabasePerformance() {
 	//suite.Fail("ConfigService performance test not ready - no implementation")
 }
 
+// TestSensitiveConfigAccessLog tests that only accesses to sensitive configs are recorded
+func (suite *DatabaseTestSuite) TestSensitiveConfigAccessLog() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+
+	service := services.NewConfigService(store, validationService)
+	service.SetAccessLogEnabled(true)
+
+	jsonData := `{"max_limit": 1000, "enabled": true}`
+	_, err = service.CreateConfig("public-config", jsonData, "")
+	suite.NoError(err)
+	_, err = service.CreateConfig("secret-config", jsonData, "")
+	suite.NoError(err)
+
+	sensitivity, err := service.SetSensitive("secret-config", true)
+	suite.NoError(err)
+	suite.True(sensitivity.Sensitive)
+
+	service.LogAccess("public-config", "alice", models.AccessActionRead)
+	service.LogAccess("secret-config", "alice", models.AccessActionRead)
+	service.LogAccess("secret-config", "bob", models.AccessActionWrite)
+
+	var count int
+	err = suite.db.QueryRow(`SELECT COUNT(*) FROM access_log WHERE configuration_name = 'public-config'`).Scan(&count)
+	suite.NoError(err)
+	suite.Equal(0, count)
+
+	err = suite.db.QueryRow(`SELECT COUNT(*) FROM access_log WHERE configuration_name = 'secret-config'`).Scan(&count)
+	suite.NoError(err)
+	suite.Equal(2, count)
+
+	_, err = service.SetSensitive("non-existent", true)
+	suite.Error(err)
+	suite.Contains(err.Error(), "CONFIG_NOT_FOUND")
+}
+
+// TestPrunedVersionUnreachable tests that pruned versions cannot be rolled back to or listed
+func (suite *DatabaseTestSuite) TestPrunedVersionUnreachable() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+
+	configName := "test-config"
+	service := services.NewConfigService(store, validationService)
+	_, err = service.CreateConfig(configName, `{"max_limit": 1000, "enabled": true}`, "")
+	suite.NoError(err)
+	_, err = service.UpdateConfig(configName, `{"max_limit": 2000, "enabled": false}`, "")
+	suite.NoError(err)
+
+	pruned, err := service.PruneVersions(configName, 1)
+	suite.Require().NoError(err)
+	suite.Equal([]int{1}, pruned.RemovedVersions)
+
+	_, err = service.RollbackConfig(configName, 1, "")
+	suite.Error(err)
+	suite.Contains(err.Error(), "VERSION_NOT_FOUND")
+
+	versions, err := service.ListVersions(configName, services.ListVersionsOptions{})
+	suite.NoError(err)
+	suite.Len(versions.Versions, 1)
+	suite.Equal(2, versions.Versions[0].Version)
+}
+
+// TestReadReplicaRouting tests that reads use the replica except right after a write
+func (suite *DatabaseTestSuite) TestReadReplicaRouting() {
+	replicaDB := "./test_replica.db"
+	defer func() { _ = os.Remove(replicaDB) }()
+
+	replica, err := testutil.OpenMigratedDB(replicaDB)
+	suite.Require().NoError(err)
+	defer func() { _ = replica.Close() }()
+
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+
+	jsonData := `{"max_limit": 1000, "enabled": true}`
+
+	// Without a read-after-write window the lagging replica does not see the write
+	store := storage.NewSQLiteStoreWithReplica(suite.db, replica, 0)
+	service := services.NewConfigService(store, validationService)
+	_, err = service.CreateConfig("lagging-config", jsonData, "")
+	suite.NoError(err)
+	_, err = service.GetLatestConfig("lagging-config")
+	suite.Error(err)
+	suite.Contains(err.Error(), "CONFIG_NOT_FOUND")
+
+	// Within the window reads of the written configuration go to the primary
+	store = storage.NewSQLiteStoreWithReplica(suite.db, replica, time.Minute)
+	service = services.NewConfigService(store, validationService)
+	_, err = service.CreateConfig("fresh-config", jsonData, "")
+	suite.NoError(err)
+	config, err := service.GetLatestConfig("fresh-config")
+	suite.NoError(err)
+	suite.Equal("fresh-config", config.Name)
+}
+
+// TestRepairCurrentVersions tests that drifted current_version values are reset to the max version
+func (suite *DatabaseTestSuite) TestRepairCurrentVersions() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+
+	configName := "test-config"
+	service := services.NewConfigService(store, validationService)
+	_, err = service.CreateConfig(configName, `{"max_limit": 1000, "enabled": true}`, "")
+	suite.NoError(err)
+	_, err = service.UpdateConfig(configName, `{"max_limit": 2000, "enabled": false}`, "")
+	suite.NoError(err)
+
+	// Simulate a botched manual edit
+	_, err = suite.db.Exec(`UPDATE configurations SET current_version = 7 WHERE name = ?`, configName)
+	suite.Require().NoError(err)
+	_, err = service.GetLatestConfig(configName)
+	suite.Error(err)
+
+	report, err := service.RepairCurrentVersions()
+	suite.NoError(err)
+	suite.Equal(1, report.Repaired)
+	suite.Require().Len(report.Issues, 1)
+	suite.Equal(7, report.Issues[0].PreviousVersion)
+	suite.Equal(2, report.Issues[0].CurrentVersion)
+
+	config, err := service.GetLatestConfig(configName)
+	suite.NoError(err)
+	suite.Equal(2, config.Version)
+
+	// A second run finds nothing to repair
+	report, err = service.RepairCurrentVersions()
+	suite.NoError(err)
+	suite.Empty(report.Issues)
+}
+
 // TestInTransaction runs the test suite
 func TestDatabaseIntegration(t *testing.T) {
 	suite.Run(t, new(DatabaseTestSuite))
 }
+
+func (suite *DatabaseTestSuite) TestCompressedStorage() {
+	store := storage.NewSQLiteStore(suite.db)
+
+	configName := "test-config"
+	_, err := store.CreateConfiguration(configName, `{"max_limit": 1000, "enabled": true}`, "", nil, "")
+	suite.NoError(err)
+
+	// Versions written after enabling compression coexist with plain rows
+	store.SetCompressStorage(true)
+	_, err = store.UpdateConfiguration(configName, `{"max_limit": 2000, "enabled": false}`, "", nil, "")
+	suite.NoError(err)
+
+	var stored string
+	err = suite.db.QueryRow(`
+		SELECT b.data FROM versions v JOIN version_blobs b ON b.id = v.blob_id
+		WHERE v.configuration_name = ? AND v.version_number = 2`, configName).Scan(&stored)
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(stored, "gzip:"))
+
+	_, versions, err := store.ListVersions(configName)
+	suite.NoError(err)
+	suite.Require().Len(versions, 2)
+	suite.JSONEq(`{"max_limit": 2000, "enabled": false}`, versions[0].JsonData)
+	suite.JSONEq(`{"max_limit": 1000, "enabled": true}`, versions[1].JsonData)
+
+	// Rolling back after disabling compression reuses the compressed blob and reads it transparently
+	store.SetCompressStorage(false)
+	_, err = store.RollbackConfiguration(configName, 2, "", "")
+	suite.NoError(err)
+
+	_, latest, err := store.GetLatestConfiguration(configName)
+	suite.NoError(err)
+	suite.Equal(`{"max_limit": 2000, "enabled": false}`, latest.JsonData)
+}
+
+// TestRawVersionData checks that compressed versions are reported as stored alongside their
+// decoded data, and that a corrupted value is reported instead of failing the request
+func (suite *DatabaseTestSuite) TestRawVersionData() {
+	store := storage.NewSQLiteStore(suite.db)
+	store.SetCompressStorage(true)
+
+	configName := "test-config"
+	_, err := store.CreateConfiguration(configName, `{"max_limit": 1000, "enabled": true}`, "", nil, "")
+	suite.Require().NoError(err)
+
+	raw, err := store.GetRawVersionData(configName, 1)
+	suite.Require().NoError(err)
+	suite.Equal("gzip", raw.Encoding)
+	suite.True(strings.HasPrefix(raw.Stored, "gzip:"))
+	suite.Equal(`{"max_limit": 1000, "enabled": true}`, raw.Decoded)
+	suite.Empty(raw.DecodeError)
+
+	_, err = suite.db.Exec(`UPDATE version_blobs SET data = 'gzip:not-base64!' WHERE hash = ?`, raw.BlobHash)
+	suite.Require().NoError(err)
+
+	raw, err = store.GetRawVersionData(configName, 1)
+	suite.Require().NoError(err)
+	suite.Equal("gzip:not-base64!", raw.Stored)
+	suite.Empty(raw.Decoded)
+	suite.Contains(raw.DecodeError, "failed to decode compressed json data")
+}
+
+// TestVersionAges checks that listed versions carry their age, in seconds and as an ISO-8601
+// duration, only when asked for
+func (suite *DatabaseTestSuite) TestVersionAges() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(store, validationService)
+
+	configName := "test-config"
+	_, err = service.CreateConfig(configName, `{"max_limit": 1, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	_, err = service.UpdateConfig(configName, `{"max_limit": 2, "enabled": true}`, "")
+	suite.Require().NoError(err)
+
+	backdated := time.Now().Add(-(26*time.Hour + 3*time.Minute + 4*time.Second))
+	_, err = suite.db.Exec(`UPDATE versions SET created_at = ? WHERE configuration_name = ? AND version_number = 1`, backdated, configName)
+	suite.Require().NoError(err)
+
+	list, err := service.ListVersions(configName, services.ListVersionsOptions{})
+	suite.Require().NoError(err)
+	suite.Empty(list.Versions[0].Age)
+	suite.Nil(list.Versions[0].AgeSeconds)
+
+	list, err = service.ListVersions(configName, services.ListVersionsOptions{IncludeAge: true})
+	suite.Require().NoError(err)
+	suite.Require().Len(list.Versions, 2)
+
+	latest, first := list.Versions[0], list.Versions[1]
+	suite.Equal(2, latest.Version)
+	suite.Require().NotNil(latest.AgeSeconds)
+	suite.Less(*latest.AgeSeconds, int64(5))
+	suite.Regexp(`^PT\d+S$`, latest.Age)
+
+	suite.Equal(1, first.Version)
+	suite.Require().NotNil(first.AgeSeconds)
+	suite.InDelta(26*3600+3*60+4, *first.AgeSeconds, 2)
+	suite.Regexp(`^P1DT2H3M\d+S$`, first.Age)
+}
+
+// TestConcurrentWrites fires concurrent updates, rollbacks and reads at one configuration
+// and checks that the committed versions stay strictly increasing without gaps or duplicates
+func (suite *DatabaseTestSuite) TestConcurrentWrites() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(store, validationService)
+
+	configName := "test-config"
+	_, err = service.CreateConfig(configName, `{"max_limit": 0, "enabled": true}`, "")
+	suite.Require().NoError(err)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	committed := []int{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			config, err := service.UpdateConfig(configName, fmt.Sprintf(`{"max_limit": %d, "enabled": true}`, i+1), "")
+			if err == nil {
+				mu.Lock()
+				committed = append(committed, config.CurrentVersion)
+				mu.Unlock()
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			config, err := service.RollbackConfig(configName, 1, "")
+			if err == nil {
+				mu.Lock()
+				committed = append(committed, config.CurrentVersion)
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = service.GetLatestConfig(configName)
+		}()
+	}
+	wg.Wait()
+
+	// Immediate transactions serialize the writers, so none of them fails
+	suite.Len(committed, 2*workers)
+
+	// Every successful write got its own version number
+	sort.Ints(committed)
+	for i := 1; i < len(committed); i++ {
+		suite.NotEqual(committed[i-1], committed[i], "duplicate version %d", committed[i])
+	}
+
+	// Stored versions are exactly 1..N and current_version points at N
+	versions, err := service.ListVersions(configName, services.ListVersionsOptions{})
+	suite.Require().NoError(err)
+	suite.Len(versions.Versions, len(committed)+1)
+	for i, version := range versions.Versions {
+		suite.Equal(len(versions.Versions)-i, version.Version)
+	}
+	suite.Equal(len(versions.Versions), versions.CurrentVersion)
+}
+
+// TestApplySchemaDefaults tests that schema defaults are overlaid on reads without changing storage
+func (suite *DatabaseTestSuite) TestApplySchemaDefaults() {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+  "type": "object",
+  "properties": {
+    "max_limit": {"type": "integer", "minimum": 0, "default": 10},
+    "enabled": {"type": "boolean", "default": false},
+    "status": {"type": "string", "enum": ["on", "off", "scheduled"], "default": "off"}
+  },
+  "required": ["max_limit"],
+  "additionalProperties": false
+}`))
+	}))
+	defer registry.Close()
+
+	validationService, err := services.NewRegistryValidationService(registry.URL)
+	suite.Require().NoError(err)
+	service := services.NewConfigService(storage.NewSQLiteStore(suite.db), validationService)
+
+	_, err = service.CreateConfig("defaults-off", `{"max_limit": 5, "enabled": false}`, "")
+	suite.Require().NoError(err)
+
+	withDefaults, err := service.GetLatestConfigWithDefaults("defaults-off")
+	suite.Require().NoError(err)
+	suite.Equal("off", withDefaults.ConfigData.Status)
+	suite.Equal(5, withDefaults.ConfigData.MaxLimit)
+
+	stored, err := service.GetLatestConfig("defaults-off")
+	suite.Require().NoError(err)
+	suite.Empty(stored.ConfigData.Status)
+
+	// A default that contradicts the stored data is not applied
+	_, err = service.CreateConfig("defaults-on", `{"max_limit": 5, "enabled": true}`, "")
+	suite.Require().NoError(err)
+
+	withDefaults, err = service.GetLatestConfigWithDefaults("defaults-on")
+	suite.Require().NoError(err)
+	suite.Empty(withDefaults.ConfigData.Status)
+	suite.True(withDefaults.ConfigData.Enabled)
+}
+
+// TestExpectedVersionUpdates fires two compare-and-swap updates based on the same version and
+// checks that exactly one applies while the other fails as stale
+func (suite *DatabaseTestSuite) TestExpectedVersionUpdates() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(store, validationService)
+
+	_, err = service.CreateConfig("cas", `{"max_limit": 1, "enabled": true}`, "")
+	suite.Require().NoError(err)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := fmt.Sprintf(`{"max_limit": %d, "enabled": true}`, 10+i)
+			_, _, errs[i] = service.UpdateConfigWithOptions("cas", data, services.UpdateOptions{ExpectedVersion: 1})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		stale, ok := err.(*storage.StaleVersionError)
+		suite.Require().True(ok, "unexpected error: %v", err)
+		suite.Equal(1, stale.ExpectedVersion)
+		suite.Equal(2, stale.CurrentVersion)
+	}
+	suite.Equal(1, succeeded)
+
+	versions, err := service.ListVersions("cas", services.ListVersionsOptions{})
+	suite.Require().NoError(err)
+	suite.Equal(2, versions.CurrentVersion)
+	suite.Len(versions.Versions, 2)
+
+	// The check happens in the store's transaction, not only behind the service's lock
+	_, err = store.UpdateConfigurationIfCurrent("cas", `{"max_limit": 3, "enabled": true}`, "", nil, 1, "")
+	suite.IsType(&storage.StaleVersionError{}, err)
+	config, err := store.UpdateConfigurationIfCurrent("cas", `{"max_limit": 3, "enabled": true}`, "", nil, 2, "")
+	suite.Require().NoError(err)
+	suite.Equal(3, config.CurrentVersion)
+
+	// An update expecting a version never creates the configuration
+	_, _, err = service.UpdateConfigWithOptions("cas-missing", `{"max_limit": 1, "enabled": true}`,
+		services.UpdateOptions{CreateIfMissing: true, ExpectedVersion: 1})
+	suite.IsType(&storage.ConfigNotFoundError{}, err)
+}
+
+// TestSchemaUnavailableServesReads tests that without a usable schema reads keep working, writes
+// fail with ValidationUnavailableError, and a registry refresh restores writes
+func (suite *DatabaseTestSuite) TestSchemaUnavailableServesReads() {
+	var schemaFixed atomic.Bool
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !schemaFixed.Load() {
+			_, _ = w.Write([]byte(`{"type": "object", "properties": {"max_limit": {"type": 42}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(services.ConfigDataSchema))
+	}))
+	defer registry.Close()
+
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	_, err = services.NewConfigService(store, validationService).CreateConfig("degraded", `{"max_limit": 1, "enabled": true}`, "")
+	suite.Require().NoError(err)
+
+	_, err = services.NewRegistryValidationService(registry.URL)
+	suite.Require().Error(err)
+	degraded := services.NewUnavailableValidationService(registry.URL, err)
+	service := services.NewConfigService(store, degraded)
+
+	latest, err := service.GetLatestConfigWithDrafts("degraded", true)
+	suite.Require().NoError(err)
+	suite.Equal(1, latest.ConfigData.MaxLimit)
+
+	_, err = service.UpdateConfig("degraded", `{"max_limit": 2, "enabled": true}`, "")
+	suite.True(services.IsValidationUnavailableError(err), "unexpected error: %v", err)
+	_, err = service.CreateConfig("degraded-new", `{"max_limit": 2, "enabled": true}`, "")
+	suite.True(services.IsValidationUnavailableError(err), "unexpected error: %v", err)
+	_, err = service.RollbackConfig("degraded", 1, "")
+	suite.True(services.IsValidationUnavailableError(err), "unexpected error: %v", err)
+
+	// The schema is still broken, so refreshing keeps writes rejected
+	suite.Error(degraded.RefreshSchema())
+	suite.Error(degraded.Unavailable())
+
+	schemaFixed.Store(true)
+	suite.Require().NoError(degraded.RefreshSchema())
+	suite.NoError(degraded.Unavailable())
+
+	config, err := service.UpdateConfig("degraded", `{"max_limit": 2, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	suite.Equal(2, config.CurrentVersion)
+}
+
+// TestVersionContiguity tests gap detection and the optional write-time enforcement
+func (suite *DatabaseTestSuite) TestVersionContiguity() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+
+	service := services.NewConfigService(store, validationService)
+	for _, name := range []string{"contiguous", "gapped"} {
+		_, err = service.CreateConfig(name, `{"max_limit": 1000, "enabled": true}`, "")
+		suite.Require().NoError(err)
+		for i := 0; i < 3; i++ {
+			_, err = service.UpdateConfig(name, fmt.Sprintf(`{"max_limit": %d, "enabled": true}`, i), "")
+			suite.Require().NoError(err)
+		}
+	}
+
+	report, err := service.CheckVersionContiguity()
+	suite.NoError(err)
+	suite.True(report.Contiguous)
+
+	// Simulate an import that skipped versions 2 and 3
+	_, err = suite.db.Exec(`DELETE FROM versions WHERE configuration_name = 'gapped' AND version_number IN (2, 3)`)
+	suite.Require().NoError(err)
+
+	report, err = service.CheckVersionContiguity()
+	suite.NoError(err)
+	suite.False(report.Contiguous)
+	suite.Require().Len(report.Gaps, 1)
+	suite.Equal("gapped", report.Gaps[0].Name)
+	suite.Equal([]int{2, 3}, report.Gaps[0].Missing)
+
+	// Writes to gapped configurations are allowed until enforcement is switched on
+	_, err = service.UpdateConfig("gapped", `{"max_limit": 1, "enabled": true}`, "")
+	suite.NoError(err)
+
+	store.SetEnforceContiguity(true)
+	_, err = service.UpdateConfig("gapped", `{"max_limit": 2, "enabled": true}`, "")
+	var gapErr *storage.VersionGapError
+	suite.ErrorAs(err, &gapErr)
+	_, err = service.RollbackConfig("gapped", 1, "")
+	suite.ErrorAs(err, &gapErr)
+
+	_, err = service.UpdateConfig("contiguous", `{"max_limit": 2, "enabled": true}`, "")
+	suite.NoError(err)
+}
+
+// TestContentAddressedVersions checks that identical data shares one blob, that legacy inline
+// rows are backfilled on migration, and that deleting the last reference removes the blob
+func (suite *DatabaseTestSuite) TestContentAddressedVersions() {
+	store := storage.NewSQLiteStore(suite.db)
+
+	countBlobs := func() int {
+		var count int
+		suite.Require().NoError(suite.db.QueryRow(`SELECT COUNT(*) FROM version_blobs`).Scan(&count))
+		return count
+	}
+
+	_, err := store.CreateConfiguration("dedup-a", `{"max_limit": 10, "enabled": true}`, "", nil, "")
+	suite.Require().NoError(err)
+	// Same data with different key order and whitespace reuses the blob
+	_, err = store.CreateConfiguration("dedup-b", `{"enabled":true,"max_limit":10}`, "", nil, "")
+	suite.Require().NoError(err)
+	_, err = store.UpdateConfiguration("dedup-a", `{"max_limit": 20, "enabled": true}`, "", nil, "")
+	suite.Require().NoError(err)
+	_, err = store.RollbackConfiguration("dedup-a", 1, "", "")
+	suite.Require().NoError(err)
+	suite.Equal(2, countBlobs())
+
+	// A row written before content-addressed storage keeps its data inline until backfilled
+	_, err = suite.db.Exec(`INSERT INTO configurations (name, current_version, created_at, updated_at) VALUES ('legacy', 1, ?, ?)`, time.Now(), time.Now())
+	suite.Require().NoError(err)
+	_, err = suite.db.Exec(`INSERT INTO versions (configuration_name, version_number, json_data, created_at) VALUES ('legacy', 1, '{"max_limit": 20, "enabled": true}', ?)`, time.Now())
+	suite.Require().NoError(err)
+
+	_, legacy, err := store.GetLatestConfiguration("legacy")
+	suite.Require().NoError(err)
+	suite.JSONEq(`{"max_limit": 20, "enabled": true}`, legacy.JsonData)
+
+	suite.Require().NoError(storage.Migrate(suite.db))
+	var unlinked int
+	suite.Require().NoError(suite.db.QueryRow(`SELECT COUNT(*) FROM versions WHERE blob_id IS NULL`).Scan(&unlinked))
+	suite.Zero(unlinked)
+	suite.Equal(2, countBlobs())
+
+	_, legacy, err = store.GetLatestConfiguration("legacy")
+	suite.Require().NoError(err)
+	suite.JSONEq(`{"max_limit": 20, "enabled": true}`, legacy.JsonData)
+
+	// Deletes are soft, so the deleted configurations' versions and their blobs are kept
+	_, err = store.DeleteConfigurationsByPrefix("dedup-")
+	suite.Require().NoError(err)
+	suite.Equal(2, countBlobs())
+}
+
+// TestTimestampNormalization checks that timestamps are written in one format and that the
+// migration rewrites rows stored in the older CURRENT_TIMESTAMP and driver formats
+func (suite *DatabaseTestSuite) TestTimestampNormalization() {
+	store := storage.NewSQLiteStore(suite.db)
+	rawTimestamp := func(query string, args ...interface{}) string {
+		var value string
+		// Concatenation hides the DATETIME type so the driver returns the stored text
+		suite.Require().NoError(suite.db.QueryRow(query, args...).Scan(&value))
+		return value
+	}
+
+	_, err := store.CreateConfiguration("current", `{"max_limit": 1, "enabled": true}`, "", nil, "")
+	suite.Require().NoError(err)
+	suite.Regexp(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{9}Z$`,
+		rawTimestamp(`SELECT created_at || '' FROM versions WHERE configuration_name = 'current'`))
+
+	_, err = suite.db.Exec(`INSERT INTO configurations (name, current_version, created_at, updated_at) VALUES ('legacy', 1, '2025-09-15 10:30:00', '2025-09-15 12:30:00.123456789+02:00')`)
+	suite.Require().NoError(err)
+	_, err = suite.db.Exec(`INSERT INTO versions (configuration_name, version_number, json_data, created_at) VALUES ('legacy', 1, '{"max_limit": 20, "enabled": true}', '2025-09-15 10:30:00')`)
+	suite.Require().NoError(err)
+
+	normalize, err := fs.ReadFile(migrations.FS, "000011_normalize_timestamps.up.sql")
+	suite.Require().NoError(err)
+	_, err = suite.db.Exec(string(normalize))
+	suite.Require().NoError(err)
+
+	suite.Equal("2025-09-15T10:30:00.000000000Z", rawTimestamp(`SELECT created_at || '' FROM configurations WHERE name = 'legacy'`))
+	suite.Equal("2025-09-15T10:30:00.123000000Z", rawTimestamp(`SELECT updated_at || '' FROM configurations WHERE name = 'legacy'`))
+	suite.Equal("2025-09-15T10:30:00.000000000Z", rawTimestamp(`SELECT created_at || '' FROM versions WHERE configuration_name = 'legacy'`))
+
+	meta, err := store.GetConfigurationMeta("legacy")
+	suite.Require().NoError(err)
+	suite.True(meta.CreatedAt.Equal(time.Date(2025, 9, 15, 10, 30, 0, 0, time.UTC)))
+	suite.True(meta.UpdatedAt.Equal(time.Date(2025, 9, 15, 10, 30, 0, 123000000, time.UTC)))
+}
+
+// TestAuditLogPurge checks that only access log entries past the retention window are purged
+func (suite *DatabaseTestSuite) TestAuditLogPurge() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(store, validationService)
+
+	_, err = service.PurgeAuditLog()
+	suite.True(services.IsAuditRetentionNotSetError(err))
+
+	now := time.Now().UTC()
+	for _, age := range []time.Duration{400 * 24 * time.Hour, 31 * 24 * time.Hour, 29 * 24 * time.Hour, time.Minute} {
+		_, err := suite.db.Exec(`INSERT INTO access_log (configuration_name, actor, action, created_at) VALUES ('audited', 'ops', 'read', ?)`,
+			now.Add(-age).Format("2006-01-02T15:04:05.000000000Z"))
+		suite.Require().NoError(err)
+	}
+
+	service.SetAuditRetention(30)
+	result, err := service.PurgeAuditLog()
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), result.Removed)
+	suite.Equal(30, result.RetentionDays)
+
+	var remaining int
+	suite.Require().NoError(suite.db.QueryRow(`SELECT COUNT(*) FROM access_log`).Scan(&remaining))
+	suite.Equal(2, remaining)
+
+	// Purging again finds nothing left outside the window
+	result, err = service.PurgeAuditLog()
+	suite.Require().NoError(err)
+	suite.Zero(result.Removed)
+}
+
+// TestVerifyChecksums corrupts stored data behind the store's back and checks that every
+// version sharing the corrupted blob is reported
+func (suite *DatabaseTestSuite) TestVerifyChecksums() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(store, validationService)
+
+	_, err = service.CreateConfig("checked-a", `{"max_limit": 10, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	_, err = service.CreateConfig("checked-b", `{"max_limit": 10, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	_, err = service.UpdateConfig("checked-a", `{"max_limit": 20, "enabled": true}`, "")
+	suite.Require().NoError(err)
+
+	report, err := service.VerifyChecksums()
+	suite.Require().NoError(err)
+	suite.True(report.Intact)
+	suite.Equal(2, report.CheckedBlobs)
+	suite.Empty(report.Mismatches)
+
+	// Flip a digit of the blob shared by both version 1s
+	_, err = suite.db.Exec(`UPDATE version_blobs SET data = REPLACE(data, '10', '70') WHERE data LIKE '%10%'`)
+	suite.Require().NoError(err)
+	// A version stored inline has no checksum to verify
+	_, err = suite.db.Exec(`INSERT INTO configurations (name, current_version, created_at, updated_at) VALUES ('legacy', 1, ?, ?)`, time.Now(), time.Now())
+	suite.Require().NoError(err)
+	_, err = suite.db.Exec(`INSERT INTO versions (configuration_name, version_number, json_data, created_at) VALUES ('legacy', 1, '{"max_limit": 20, "enabled": true}', ?)`, time.Now())
+	suite.Require().NoError(err)
+
+	report, err = service.VerifyChecksums()
+	suite.Require().NoError(err)
+	suite.False(report.Intact)
+	suite.Equal(1, report.UncheckedVersions)
+	suite.Require().Len(report.Mismatches, 2)
+	suite.Equal("checked-a", report.Mismatches[0].Name)
+	suite.Equal(1, report.Mismatches[0].Version)
+	suite.Equal("checked-b", report.Mismatches[1].Name)
+	suite.NotEqual(report.Mismatches[0].StoredChecksum, report.Mismatches[0].ComputedChecksum)
+}
+
+// TestScheduledBackup uploads the archive to a fake S3 endpoint, retrying a failed attempt,
+// and checks that every outcome is recorded in the backup log
+func (suite *DatabaseTestSuite) TestScheduledBackup() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(store, validationService)
+
+	_, err = service.CreateConfig("backed-up", `{"max_limit": 5, "enabled": true}`, "")
+	suite.Require().NoError(err)
+
+	var mu sync.Mutex
+	var requests int
+	var uploadedPath, authorization string
+	var uploaded []byte
+	failUntil := 1
+	objectStore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests <= failUntil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		uploadedPath, authorization = r.URL.Path, r.Header.Get("Authorization")
+		uploaded, _ = io.ReadAll(r.Body)
+	}))
+	defer objectStore.Close()
+
+	uploader := services.NewS3Uploader(objectStore.URL, "backups", "eu-west-1", "AKIDEXAMPLE", "secret")
+	job := services.NewBackupJob(service, uploader)
+	job.SetRetryPolicy(2, time.Millisecond)
+
+	// The first attempt fails and the retry succeeds
+	record := job.RunOnce()
+	suite.Equal(models.BackupStatusSucceeded, record.Status)
+	suite.Equal(2, record.Attempts)
+	suite.True(strings.HasPrefix(uploadedPath, "/backups/config-backups/config-archive-"))
+	suite.True(strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"))
+	suite.Equal(len(uploaded), record.SizeBytes)
+
+	var archive models.BackupArchive
+	suite.Require().NoError(json.Unmarshal(uploaded, &archive))
+	suite.Require().Len(archive.Configurations, 1)
+	suite.Equal("backed-up", archive.Configurations[0].Name)
+
+	// Exhausted retries are recorded as a failure rather than crashing the job
+	mu.Lock()
+	requests, failUntil = 0, 10
+	mu.Unlock()
+	record = job.RunOnce()
+	suite.Equal(models.BackupStatusFailed, record.Status)
+	suite.Equal(2, record.Attempts)
+	suite.Contains(record.Error, "503")
+
+	backups, err := service.ListBackups()
+	suite.Require().NoError(err)
+	suite.Require().Len(backups.Backups, 2)
+	statuses := []string{backups.Backups[0].Status, backups.Backups[1].Status}
+	suite.ElementsMatch([]string{models.BackupStatusSucceeded, models.BackupStatusFailed}, statuses)
+}
+
+// TestFindSchemaViolations stores data the schema rejects, bypassing validation, and checks
+// that the scan reports it page by page
+func (suite *DatabaseTestSuite) TestFindSchemaViolations() {
+	store := storage.NewSQLiteStore(suite.db)
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(store, validationService)
+
+	_, err = store.CreateConfiguration("a-invalid", `{"max_limit": 1, "enabled": true, "stray": 1}`, "", nil, "")
+	suite.Require().NoError(err)
+	_, err = store.UpdateConfiguration("a-invalid", `{"max_limit": 2, "enabled": true}`, "", nil, "")
+	suite.Require().NoError(err)
+	_, err = service.CreateConfig("b-valid", `{"max_limit": 3, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	_, err = store.CreateConfiguration("c-invalid", `{"max_limit": "three"}`, "", nil, "")
+	suite.Require().NoError(err)
+
+	// Latest versions only: a-invalid has been fixed since
+	report, err := service.FindSchemaViolations(false, 0, 0)
+	suite.Require().NoError(err)
+	suite.Equal(3, report.CheckedVersions)
+	suite.Require().Len(report.Violations, 1)
+	suite.Equal("c-invalid", report.Violations[0].Name)
+	suite.NotEmpty(report.Violations[0].ValidationErrors)
+
+	// Every version: a-invalid's first version fails too
+	report, err = service.FindSchemaViolations(true, 0, 0)
+	suite.Require().NoError(err)
+	suite.Equal(4, report.CheckedVersions)
+	suite.Require().Len(report.Violations, 2)
+	suite.Equal("a-invalid", report.Violations[0].Name)
+	suite.Equal(1, report.Violations[0].Version)
+
+	// Pages scan a window of configurations
+	report, err = service.FindSchemaViolations(true, 2, 0)
+	suite.Require().NoError(err)
+	suite.Equal(3, report.Pagination.Total)
+	suite.Len(report.Violations, 1)
+	report, err = service.FindSchemaViolations(true, 2, 2)
+	suite.Require().NoError(err)
+	suite.Require().Len(report.Violations, 1)
+	suite.Equal("c-invalid", report.Violations[0].Name)
+}
+
+// TestTagsFollowRename checks that a renamed configuration keeps its tags and is still found by them
+func (suite *DatabaseTestSuite) TestTagsFollowRename() {
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(storage.NewSQLiteStore(suite.db), validationService)
+
+	_, err = service.CreateConfig("old-name", `{"max_limit": 1000, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	_, err = service.SetTags("old-name", map[string]string{"team": "payments"})
+	suite.Require().NoError(err)
+
+	_, err = service.RenameConfig("old-name", "new-name")
+	suite.Require().NoError(err)
+
+	tags, err := service.GetTags("new-name")
+	suite.Require().NoError(err)
+	suite.Equal(map[string]string{"team": "payments"}, tags.Tags)
+
+	list, err := service.ListConfigs("name", false, false, []models.TagSelector{{Key: "team", Value: "payments"}}, 0, 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(list.Configurations, 1)
+	suite.Equal("new-name", list.Configurations[0].Name)
+	suite.Equal(1, list.Pagination.Total)
+}
+
+func (suite *DatabaseTestSuite) TestPruneVersions() {
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(storage.NewSQLiteStore(suite.db), validationService)
+
+	_, err = service.CreateConfig("pruned", `{"max_limit": 1, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	for limit := 2; limit <= 6; limit++ {
+		_, err = service.UpdateConfig("pruned", fmt.Sprintf(`{"max_limit": %d, "enabled": true}`, limit), "")
+		suite.Require().NoError(err)
+	}
+	_, err = service.ProtectVersion("pruned", 2)
+	suite.Require().NoError(err)
+	// Rolling back makes version 7 current with version 3's data and version 6 the redo target
+	_, err = service.RollbackConfig("pruned", 3, "")
+	suite.Require().NoError(err)
+	_, err = service.UpdateConfigAsDraft("pruned", `{"max_limit": 8, "enabled": true}`, 0, "")
+	suite.Require().NoError(err)
+
+	_, err = service.PruneVersions("pruned", 0)
+	suite.True(services.IsInvalidRetentionError(err))
+
+	result, err := service.PruneVersions("pruned", 2)
+	suite.Require().NoError(err)
+	suite.Equal([]int{1, 3, 4, 5}, result.RemovedVersions)
+	suite.Equal(7, result.CurrentVersion)
+
+	versions, err := service.ListVersions("pruned", services.ListVersionsOptions{IncludeDrafts: true})
+	suite.Require().NoError(err)
+	var remaining []int
+	for _, version := range versions.Versions {
+		remaining = append(remaining, version.Version)
+	}
+	suite.ElementsMatch([]int{2, 6, 7, 8}, remaining)
+
+	// The current version still reads back, with the data it was rolled back to
+	latest, err := service.GetLatestConfig("pruned")
+	suite.Require().NoError(err)
+	suite.Equal(7, latest.Version)
+	suite.Equal(3, latest.ConfigData.MaxLimit)
+
+	// Pruning again finds nothing left to remove
+	result, err = service.PruneVersions("pruned", 2)
+	suite.Require().NoError(err)
+	suite.Empty(result.RemovedVersions)
+
+	_, err = service.PruneVersions("missing", 2)
+	suite.IsType(&storage.ConfigNotFoundError{}, err)
+
+	// The global run applies the configured retention to every configuration
+	_, err = service.CreateConfig("other", `{"max_limit": 1, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	for limit := 2; limit <= 3; limit++ {
+		_, err = service.UpdateConfig("other", fmt.Sprintf(`{"max_limit": %d, "enabled": true}`, limit), "")
+		suite.Require().NoError(err)
+	}
+
+	removed, err := service.PruneAllVersions()
+	suite.Require().NoError(err)
+	suite.Equal(0, removed, "no retention configured")
+
+	service.SetVersionRetention(1)
+	removed, err = service.PruneAllVersions()
+	suite.Require().NoError(err)
+	suite.Equal(2, removed)
+
+	latest, err = service.GetLatestConfig("other")
+	suite.Require().NoError(err)
+	suite.Equal(3, latest.Version)
+}
+
+func (suite *DatabaseTestSuite) TestVersionCreatedBy() {
+	validationService, err := services.NewValidationService()
+	suite.Require().NoError(err)
+	service := services.NewConfigService(storage.NewSQLiteStore(suite.db), validationService)
+
+	_, err = service.CreateConfig("audited", `{"max_limit": 1, "enabled": true}`, "alice")
+	suite.Require().NoError(err)
+	_, err = service.UpdateConfig("audited", `{"max_limit": 2, "enabled": true}`, "bob")
+	suite.Require().NoError(err)
+	_, err = service.RollbackConfig("audited", 1, "carol")
+	suite.Require().NoError(err)
+	_, err = service.UpdateConfig("audited", `{"max_limit": 4, "enabled": true}`, "")
+	suite.Require().NoError(err)
+	_, err = service.UpdateConfigAsDraft("audited", `{"max_limit": 5, "enabled": true}`, 0, "dave")
+	suite.Require().NoError(err)
+
+	versions, err := service.ListVersions("audited", services.ListVersionsOptions{IncludeDrafts: true})
+	suite.Require().NoError(err)
+	createdBy := make(map[int]string)
+	for _, version := range versions.Versions {
+		createdBy[version.Version] = version.CreatedBy
+	}
+	// A rollback is attributed to whoever rolled back, not the author of the restored data
+	suite.Equal(map[int]string{1: "alice", 2: "bob", 3: "carol", 4: "", 5: "dave"}, createdBy)
+
+	version, err := service.GetConfigVersion("audited", 2)
+	suite.Require().NoError(err)
+	suite.Equal("bob", version.CreatedBy)
+
+	latest, err := service.GetLatestConfig("audited")
+	suite.Require().NoError(err)
+	suite.Equal(4, latest.Version)
+	suite.Empty(latest.CreatedBy)
+}
@@ -0,0 +1,33 @@
+//go:build sqlite || !postgres
+
+// Package testutil holds helpers shared by the contract and integration test suites
+package testutil
+
+import (
+	"database/sql"
+	"os"
+
+	"config-manager/src/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// OpenMigratedDB recreates the SQLite database at path and applies the real embedded
+// migrations, so tests run against exactly the schema production uses
+func OpenMigratedDB(path string) (*sql.DB, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", storage.SQLiteDSN(path))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storage.Migrate(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}